@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/mathbdw/subscription-service/config"
 	"github.com/mathbdw/subscription-service/internal/app"
@@ -14,6 +15,12 @@ func main() {
 		log.Fatalf("Config error: %s", err)
 	}
 
+	// Run import subcommand
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		app.RunImport(cfg, os.Args[2:])
+		return
+	}
+
 	// Run app
 	app.RunApp(cfg)
 }
@@ -40,6 +40,27 @@ type Rest struct {
 	WriteTimeout    time.Duration `yaml:"writeTimeout"`
 	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
 	Swagger         bool          `yaml:"swagger"`
+	// AdminToken - token of the admin in the X-Admin-Token header, admin requests are rejected when it is not set
+	AdminToken string `yaml:"adminToken" env:"ADMIN_TOKEN"`
+	// ImportLimit - max size of the CSV import in bytes, v1.DefaultImportLimit when it is not set
+	ImportLimit int `yaml:"importLimit"`
+}
+
+// ExchangeRates - contains parameters exchange rates source.
+type ExchangeRates struct {
+	File string `yaml:"file"`
+}
+
+// Purge - contains parameters of the job hard deleting soft-deleted subscriptions.
+// Expired idempotency keys are deleted with the same interval.
+type Purge struct {
+	Retention time.Duration `yaml:"retention"`
+	Interval  time.Duration `yaml:"interval"`
+}
+
+// Idempotency - contains parameters of the idempotency keys.
+type Idempotency struct {
+	TTL time.Duration `yaml:"ttl"`
 }
 
 // Project - contains all parameters project information.
@@ -56,6 +77,10 @@ type Config struct {
 	Project  Project  `yaml:"project"`
 	Rest     Rest     `yaml:"rest"`
 	Database Database `yaml:"database"`
+
+	ExchangeRates ExchangeRates `yaml:"exchangeRates"`
+	Purge         Purge         `yaml:"purge"`
+	Idempotency   Idempotency   `yaml:"idempotency"`
 }
 
 // ReadConfigYML - read configurations from file and init instance Config.
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"syscall"
@@ -8,13 +9,20 @@ import (
 	"github.com/pressly/goose/v3"
 
 	"github.com/mathbdw/subscription-service/config"
+	"github.com/mathbdw/subscription-service/internal/infrastructure/exchangerate/file"
 	"github.com/mathbdw/subscription-service/internal/infrastructure/httpserver"
 	"github.com/mathbdw/subscription-service/internal/infrastructure/observability/logger/zerolog"
 	"github.com/mathbdw/subscription-service/internal/infrastructure/persistence/postgres"
 	"github.com/mathbdw/subscription-service/internal/infrastructure/persistence/postgres/repositories"
+	"github.com/mathbdw/subscription-service/internal/interfaces/exchangerate"
 	httpimp "github.com/mathbdw/subscription-service/internal/interfaces/http"
 	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/usecases/audit"
+	"github.com/mathbdw/subscription-service/internal/usecases/idempotency"
+	"github.com/mathbdw/subscription-service/internal/usecases/service"
 	"github.com/mathbdw/subscription-service/internal/usecases/subscription"
+	"github.com/mathbdw/subscription-service/internal/usecases/tag"
+	"github.com/mathbdw/subscription-service/internal/usecases/user"
 )
 
 // initLogger - initializing logger
@@ -43,6 +51,16 @@ func initPostgres(cfg *config.Config, logger observability.Logger) *postgres.Pos
 	return pg
 }
 
+// initExchangeRates - initializing exchange rates provider
+func initExchangeRates(cfg *config.Config, logger observability.Logger) exchangerate.ExchangeRateProvider {
+	rates, err := file.New(cfg.ExchangeRates.File)
+	if err != nil {
+		logger.Fatal("app.initExchangeRates: load rates", map[string]any{"error": err})
+	}
+
+	return rates
+}
+
 // applyMigration - apply migration
 func applyMigration(cfg *config.Config, pg *postgres.Postgres, logger observability.Logger) {
 	if err := goose.Up(pg.Sqlx.DB, cfg.Database.Migrations); err != nil {
@@ -58,8 +76,26 @@ func RunApp(cfg *config.Config) {
 
 	applyMigration(cfg, pg, logger)
 
-	repoSub := repositories.NewUserRepository(pg.Sqlx, pg.Builder, logger)
-	usSub := subscription.NewSubscriptionUsecase(repoSub, logger)
+	rates := initExchangeRates(cfg, logger)
+
+	tx := repositories.NewTransactor(pg.Sqlx, logger)
+	repoSub := repositories.NewSubscriptionRepository(pg.Sqlx, pg.Builder, logger)
+	repoAudit := repositories.NewAuditRepository(pg.Sqlx, pg.Builder, logger)
+	usSub := subscription.NewSubscriptionUsecase(repoSub, repoAudit, tx, rates, logger)
+	usAudit := audit.NewAuditUsecase(repoAudit, logger)
+	repoIdempotency := repositories.NewIdempotencyRepository(pg.Sqlx, pg.Builder, logger)
+	usIdempotency := idempotency.NewIdempotencyUsecase(repoIdempotency, tx, cfg.Idempotency.TTL, logger)
+	repoService := repositories.NewServiceRepository(pg.Sqlx, pg.Builder, logger)
+	usService := service.NewServiceUsecase(repoService, tx, logger)
+	repoTag := repositories.NewTagRepository(pg.Sqlx, pg.Builder, logger)
+	usTag := tag.NewTagUsecase(repoTag, tx, logger)
+	repoUser := repositories.NewUserRepository(pg.Sqlx, pg.Builder, logger)
+	usUser := user.NewUserUsecase(repoUser, tx, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go runPurge(ctx, cfg.Purge, &usSub, logger)
+	go runPurgeIdempotencyKeys(ctx, cfg.Purge.Interval, &usIdempotency, logger)
 
 	httpServer := httpserver.New(
 		httpserver.Address(cfg.Rest.Host, cfg.Rest.Port),
@@ -67,8 +103,11 @@ func RunApp(cfg *config.Config) {
 		httpserver.ReadTimeout(cfg.Rest.ReadTimeout),
 		httpserver.WriteTimeout(cfg.Rest.WriteTimeout),
 		httpserver.ShutdownTimeout(cfg.Rest.ShutdownTimeout),
+		// the body limit of the handlers but the import is enforced by middleware.BodyLimit,
+		// the import is limited by middleware.StreamLimit
+		httpserver.StreamRequestBody(true),
 	)
-	httpimp.NewRouter(httpServer.App, &cfg.Rest, usSub, logger)
+	httpimp.NewRouter(httpServer.App, &cfg.Rest, usSub, usAudit, usIdempotency, usService, usTag, usUser, logger)
 
 	httpServer.Start()
 
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/mathbdw/subscription-service/config"
+	"github.com/mathbdw/subscription-service/internal/infrastructure/persistence/postgres/repositories"
+	"github.com/mathbdw/subscription-service/internal/interfaces/csvimport"
+	"github.com/mathbdw/subscription-service/internal/usecases/audit"
+	"github.com/mathbdw/subscription-service/internal/usecases/subscription"
+)
+
+// RunImport - imports subscriptions from CSV file, the report is written to stdout
+func RunImport(cfg *config.Config, args []string) {
+	flags := flag.NewFlagSet("import", flag.ExitOnError)
+	path := flags.String("file", "-", "CSV file, - reads stdin")
+	dryRun := flags.Bool("dry-run", false, "validate rows without creating subscriptions")
+	actor := flags.String("actor", "import", "actor of the audit log")
+	_ = flags.Parse(args)
+
+	logger := initLogger(cfg)
+	pg := initPostgres(cfg, logger)
+	defer pg.Sqlx.Close()
+
+	applyMigration(cfg, pg, logger)
+
+	rates := initExchangeRates(cfg, logger)
+
+	var input io.Reader = os.Stdin
+	if *path != "-" {
+		file, err := os.Open(*path)
+		if err != nil {
+			logger.Fatal("app.RunImport: open file", map[string]any{"err": err, "file": *path})
+		}
+		defer file.Close()
+		input = file
+	}
+
+	tx := repositories.NewTransactor(pg.Sqlx, logger)
+	repoSub := repositories.NewSubscriptionRepository(pg.Sqlx, pg.Builder, logger)
+	repoAudit := repositories.NewAuditRepository(pg.Sqlx, pg.Builder, logger)
+	usSub := subscription.NewSubscriptionUsecase(repoSub, repoAudit, tx, rates, logger)
+
+	importer := csvimport.NewImporter(&usSub, validator.New(validator.WithRequiredStructEnabled()), logger)
+	report, err := importer.Import(audit.WithActor(context.Background(), *actor), input, *dryRun)
+	if err != nil {
+		logger.Fatal("app.RunImport: import", map[string]any{"err": err})
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		logger.Fatal("app.RunImport: write report", map[string]any{"err": err})
+	}
+
+	logger.Info("app.RunImport: import finished", map[string]any{"rows": report.Rows, "created": report.Created, "failed": report.Failed})
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/mathbdw/subscription-service/config"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/usecases/idempotency"
+	"github.com/mathbdw/subscription-service/internal/usecases/subscription"
+)
+
+// runPurge - hard deletes subscriptions soft-deleted longer than retention ago every interval.
+// The job is disabled when retention or interval is not set.
+func runPurge(ctx context.Context, cfg config.Purge, uc *subscription.SubscriptionUsecase, logger observability.Logger) {
+	if cfg.Retention <= 0 || cfg.Interval <= 0 {
+		logger.Info("app.runPurge: disabled", nil)
+		return
+	}
+
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := uc.Purge(ctx, cfg.Retention)
+		if err != nil {
+			logger.Error("app.runPurge: purge", map[string]any{"error": err})
+		} else if purged > 0 {
+			logger.Info("app.runPurge: purged", map[string]any{"count": purged})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runPurgeIdempotencyKeys - deletes expired idempotency keys every interval.
+// The job is disabled when interval is not set, expired keys are not replayed anyway.
+func runPurgeIdempotencyKeys(ctx context.Context, interval time.Duration, uc *idempotency.IdempotencyUsecase, logger observability.Logger) {
+	if interval <= 0 {
+		logger.Info("app.runPurgeIdempotencyKeys: disabled", nil)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := uc.Purge(ctx)
+		if err != nil {
+			logger.Error("app.runPurgeIdempotencyKeys: purge", map[string]any{"error": err})
+		} else if purged > 0 {
+			logger.Info("app.runPurgeIdempotencyKeys: purged", map[string]any{"count": purged})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
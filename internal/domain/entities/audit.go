@@ -0,0 +1,44 @@
+package entities
+
+import "time"
+
+type AuditActionType string
+
+const (
+	AuditActionCreate  AuditActionType = "create"
+	AuditActionUpdate  AuditActionType = "update"
+	AuditActionDelete  AuditActionType = "delete"
+	AuditActionRestore AuditActionType = "restore"
+)
+
+// AuditEntitySubscription - entity type of subscription in the audit log
+const AuditEntitySubscription = "subscription"
+
+// AuditEntry - one change of an entity made by the actor.
+// Before, After and Diff hold JSON, Before is empty for create and After is empty for delete.
+type AuditEntry struct {
+	ID         int64           `db:"id"`
+	EntityType string          `db:"entity_type"`
+	EntityID   int64           `db:"entity_id"`
+	Action     AuditActionType `db:"action"`
+	Actor      string          `db:"actor"`
+	Before     []byte          `db:"data_before"`
+	After      []byte          `db:"data_after"`
+	Diff       []byte          `db:"diff"`
+	CreatedAt  time.Time       `db:"created_at"`
+}
+
+// AuditChange - values of one field before and after the change
+type AuditChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+// AuditFilter - filter of the audit log, zero values are not applied
+type AuditFilter struct {
+	EntityType string
+	EntityID   int64
+	Actor      string
+	Period     DateRange
+	Pagination PaginationParams
+}
@@ -0,0 +1,32 @@
+package entities
+
+type BulkModeType string
+
+const (
+	// BulkModeAtomic - all items are saved or none of them
+	BulkModeAtomic BulkModeType = "atomic"
+	// BulkModeBestEffort - valid items are saved, failed items are skipped
+	BulkModeBestEffort BulkModeType = "best_effort"
+)
+
+// BulkUpdate - fields of the subscription updated in bulk, zero Version skips the version check
+type BulkUpdate struct {
+	ID      int64
+	Fields  map[string]any
+	Version int64
+}
+
+// BulkItemResult - result of the bulk item by its index in the request
+type BulkItemResult struct {
+	Index int
+	ID    int64
+	Err   error
+}
+
+// BulkResult - results of the bulk items.
+// Committed is false when the atomic bulk is rolled back, then none of the items is saved.
+type BulkResult struct {
+	Mode      BulkModeType
+	Committed bool
+	Items     []BulkItemResult
+}
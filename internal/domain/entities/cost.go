@@ -0,0 +1,80 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CostGroupType string
+
+const (
+	CostGroupTypeServiceName CostGroupType = "service_name"
+	CostGroupTypeUserID      CostGroupType = "user_id"
+	CostGroupTypeCategory    CostGroupType = "category"
+	// CostGroupTypeTag - subscription with several tags is counted in the group of each of them
+	CostGroupTypeTag CostGroupType = "tag"
+)
+
+var CostGroupTypes = map[string]bool{
+	string(CostGroupTypeServiceName): true,
+	string(CostGroupTypeUserID):      true,
+	string(CostGroupTypeCategory):    true,
+	string(CostGroupTypeTag):         true,
+}
+
+// CostItem - cost of one subscription billed within the period
+type CostItem struct {
+	SubscriptionID  int64             `db:"id"`
+	ServiceName     string            `db:"service_name"`
+	UserId          uuid.UUID         `db:"user_id"`
+	Price           uint32            `db:"price"`
+	Currency        string            `db:"currency"`
+	BillingPeriod   BillingPeriodType `db:"billing_period"`
+	BillingInterval uint16            `db:"billing_interval"`
+	Months          int64             `db:"months"`
+	Cost            int64             `db:"cost"`
+
+	ConvertedCost *Converted `db:"-"`
+}
+
+// CostTotal - spend in one currency, amounts in different currencies are never summed up
+type CostTotal struct {
+	Currency string
+	Cost     int64
+}
+
+// Cost - total cost of subscriptions per currency with breakdown by subscription
+type Cost struct {
+	Totals         []CostTotal
+	TotalConverted *Converted
+	Items          []CostItem
+}
+
+// CostMonthService - spend of one service billed in the month
+type CostMonthService struct {
+	Month         time.Time `db:"month"`
+	ServiceName   string    `db:"service_name"`
+	Currency      string    `db:"currency"`
+	Subscriptions int64     `db:"subscriptions"`
+	Cost          int64     `db:"cost"`
+}
+
+// CostMonth - spend bucket of one calendar month
+type CostMonth struct {
+	Month         time.Time
+	Subscriptions int64
+	Totals        []CostTotal
+	Services      []CostMonthService
+}
+
+// CostGroup - spend of subscriptions grouped by service, user, category, tag or several of them
+type CostGroup struct {
+	ServiceName   string    `db:"service_name"`
+	UserId        uuid.UUID `db:"user_id"`
+	Category      string    `db:"category"`
+	Tag           string    `db:"tag"`
+	Currency      string    `db:"currency"`
+	Subscriptions int64     `db:"subscriptions"`
+	Cost          int64     `db:"cost"`
+}
@@ -0,0 +1,74 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// IdempotencyKey - response stored for the client key of a request, it is replayed for the retries of the request
+// until ExpiresAt. RequestHash identifies the request the key was used with first.
+type IdempotencyKey struct {
+	Key             string          `db:"key"`
+	RequestHash     string          `db:"request_hash"`
+	StatusCode      int             `db:"status_code"`
+	ResponseBody    []byte          `db:"response_body"`
+	ResponseHeaders ResponseHeaders `db:"response_headers"`
+	CreatedAt       time.Time       `db:"created_at"`
+	ExpiresAt       time.Time       `db:"expires_at"`
+}
+
+// IdempotentResponse - response of the request run for the key, only the successful one is stored
+type IdempotentResponse struct {
+	StatusCode int
+	Body       []byte
+	Headers    ResponseHeaders
+}
+
+// Succeeded - the response is 2xx
+func (r IdempotentResponse) Succeeded() bool {
+	return r.StatusCode >= http.StatusOK && r.StatusCode < http.StatusMultipleChoices
+}
+
+// ResponseHeaders - headers replayed with the stored response by name, stored as the JSON object
+type ResponseHeaders map[string]string
+
+// Scan - implements sql.Scanner
+func (h *ResponseHeaders) Scan(src any) error {
+	var data []byte
+	switch value := src.(type) {
+	case nil:
+		*h = ResponseHeaders{}
+		return nil
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	default:
+		return fmt.Errorf("ResponseHeaders.Scan: unsupported type %T", src)
+	}
+
+	headers := make(ResponseHeaders)
+	if err := json.Unmarshal(data, &headers); err != nil {
+		return fmt.Errorf("ResponseHeaders.Scan: %w", err)
+	}
+	*h = headers
+
+	return nil
+}
+
+// Value - implements driver.Valuer, nil headers are stored as the empty object
+func (h ResponseHeaders) Value() (driver.Value, error) {
+	if h == nil {
+		return []byte("{}"), nil
+	}
+
+	data, err := json.Marshal(map[string]string(h))
+	if err != nil {
+		return nil, fmt.Errorf("ResponseHeaders.Value: %w", err)
+	}
+
+	return data, nil
+}
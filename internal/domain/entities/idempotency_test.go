@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotency_ResponseHeaders(t *testing.T) {
+	headers := ResponseHeaders{"Location": "/api/v1/subscription/1", "ETag": `"1"`}
+
+	value, err := headers.Value()
+	require.NoError(t, err)
+
+	var scanned ResponseHeaders
+	require.NoError(t, scanned.Scan(value))
+	require.Equal(t, headers, scanned)
+
+	value, err = ResponseHeaders(nil).Value()
+	require.NoError(t, err)
+	require.Equal(t, []byte("{}"), value)
+
+	require.NoError(t, scanned.Scan(nil))
+	require.Equal(t, ResponseHeaders{}, scanned)
+	require.Error(t, scanned.Scan(1))
+}
+
+func TestIdempotency_ResponseSucceeded(t *testing.T) {
+	require.True(t, IdempotentResponse{StatusCode: 201}.Succeeded())
+	require.False(t, IdempotentResponse{StatusCode: 422}.Succeeded())
+}
@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ShareType string
+
+const (
+	// ShareTypePercent - member pays the percent of the price
+	ShareTypePercent ShareType = "percent"
+	// ShareTypeFixed - member pays the fixed amount in the currency of the subscription, at most the price of the month
+	ShareTypeFixed ShareType = "fixed"
+)
+
+var ShareTypes = map[string]bool{
+	string(ShareTypePercent): true,
+	string(ShareTypeFixed):   true,
+}
+
+// sharesTolerance - percents the sum of the shares may differ from 100 by, it covers rounding of the shares
+const sharesTolerance = 0.01
+
+// Member - user sharing the cost of the subscription
+type Member struct {
+	SubscriptionID int64     `db:"subscription_id"`
+	UserId         uuid.UUID `db:"user_id"`
+	ShareType      ShareType `db:"share_type"`
+	Share          float64   `db:"share"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+// Percent - part of the price paid by the member in percent
+func (m Member) Percent(price uint32) float64 {
+	if m.ShareType == ShareTypeFixed {
+		if price == 0 {
+			return 0
+		}
+
+		return m.Share * 100 / float64(price)
+	}
+
+	return m.Share
+}
+
+// SharesCoverPrice - shares of the members sum to 100% of the price
+func SharesCoverPrice(members []Member, price uint32) bool {
+	var total float64
+	for _, member := range members {
+		total += member.Percent(price)
+	}
+
+	return math.Abs(total-100) <= sharesTolerance
+}
@@ -0,0 +1,64 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMember_Percent(t *testing.T) {
+	assert.Equal(t, 25.0, Member{ShareType: ShareTypePercent, Share: 25}.Percent(400))
+	assert.Equal(t, 25.0, Member{ShareType: ShareTypeFixed, Share: 100}.Percent(400))
+	assert.Equal(t, 0.0, Member{ShareType: ShareTypeFixed, Share: 100}.Percent(0))
+}
+
+func TestMember_SharesCoverPrice(t *testing.T) {
+	tests := []struct {
+		name     string
+		members  []Member
+		price    uint32
+		expected bool
+	}{
+		{
+			name:     "Percent",
+			members:  []Member{{ShareType: ShareTypePercent, Share: 50}, {ShareType: ShareTypePercent, Share: 50}},
+			price:    300,
+			expected: true,
+		},
+		{
+			name:     "PercentRounded",
+			members:  []Member{{ShareType: ShareTypePercent, Share: 33.33}, {ShareType: ShareTypePercent, Share: 33.33}, {ShareType: ShareTypePercent, Share: 33.34}},
+			price:    300,
+			expected: true,
+		},
+		{
+			name:     "FixedAndPercent",
+			members:  []Member{{ShareType: ShareTypeFixed, Share: 150}, {ShareType: ShareTypePercent, Share: 50}},
+			price:    300,
+			expected: true,
+		},
+		{
+			name:     "Less",
+			members:  []Member{{ShareType: ShareTypeFixed, Share: 100}, {ShareType: ShareTypePercent, Share: 50}},
+			price:    300,
+			expected: false,
+		},
+		{
+			name:     "More",
+			members:  []Member{{ShareType: ShareTypePercent, Share: 60}, {ShareType: ShareTypePercent, Share: 50}},
+			price:    300,
+			expected: false,
+		},
+		{
+			name:     "Empty",
+			price:    300,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SharesCoverPrice(tt.members, tt.price))
+		})
+	}
+}
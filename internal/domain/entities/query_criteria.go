@@ -7,13 +7,21 @@ import (
 )
 
 type (
-	SortType    string
+	SortType      string
 	SortOrderType string
 )
 
 const (
 	SortTypeID          SortType = "id"
 	SortTypeServiceName SortType = "service_name"
+	SortTypeUserID      SortType = "user_id"
+	SortTypePrice       SortType = "price"
+	SortTypeStartDate   SortType = "start_date"
+	SortTypeEndDate     SortType = "end_date"
+	SortTypeCreatedAt   SortType = "created_at"
+	SortTypeUpdatedAt   SortType = "updated_at"
+	// SortTypeRelevance - similarity of the service name to the search text
+	SortTypeRelevance SortType = "relevance"
 
 	SortOrderTypeAsc  SortOrderType = "ASC"
 	SortOrderTypeDesc SortOrderType = "DESC"
@@ -22,13 +30,42 @@ const (
 type QueryCriteria struct {
 	Filter     FilterParams
 	Pagination PaginationParams
-	Sort       SortParams
+	// Sort - keys in the order of priority, rows with equal keys are ordered by id
+	Sort []SortParams
+	// Currency - reporting currency prices are converted into
+	Currency string
 }
 
 type FilterParams struct {
 	ServiceName string
 	UserId      uuid.UUID
 	StartDate   DateRange
+	Period      DateRange
+	// IncludeDeleted - soft-deleted subscriptions are not excluded
+	IncludeDeleted bool
+
+	// ServiceNames, UserIds - subscription matches any of them
+	ServiceNames []string
+	UserIds      []uuid.UUID
+	// ServiceNamePrefix, ServiceNameContains - case-insensitive match of the service name
+	ServiceNamePrefix   string
+	ServiceNameContains string
+	Price               PriceRange
+	EndDate             DateRange
+	// ActiveAt - subscription is billed in the month of the date
+	ActiveAt *time.Time
+	// NoEndDate - subscription has no end date
+	NoEndDate bool
+	// Search - fuzzy search text, service names are ranked by the similarity to it
+	Search string
+	// Tag, Category - subscription is labeled with the tag, belongs to the category
+	Tag      string
+	Category string
+}
+
+type PriceRange struct {
+	Min *uint32
+	Max *uint32
 }
 
 type DateRange struct {
@@ -39,6 +76,17 @@ type DateRange struct {
 type PaginationParams struct {
 	Page  uint64
 	Limit uint64
+	// Cursor - keyset pagination continues after the row of the cursor, Page is ignored
+	Cursor *Cursor
+	// WithoutTotal - total count is not queried
+	WithoutTotal bool
+}
+
+// Cursor - position of the keyset pagination: values of the sort keys and id of the last returned row
+type Cursor struct {
+	Sort   []SortParams
+	Values []string
+	ID     int64
 }
 
 type SortParams struct {
@@ -46,12 +94,19 @@ type SortParams struct {
 	SortOrder SortOrderType
 }
 
-var SortByTypes = map[string]bool {
-	string(SortTypeID): true,
+var SortByTypes = map[string]bool{
+	string(SortTypeID):          true,
 	string(SortTypeServiceName): true,
+	string(SortTypeUserID):      true,
+	string(SortTypePrice):       true,
+	string(SortTypeStartDate):   true,
+	string(SortTypeEndDate):     true,
+	string(SortTypeCreatedAt):   true,
+	string(SortTypeUpdatedAt):   true,
+	string(SortTypeRelevance):   true,
 }
 
-var SortOrderTypes = map[string]bool {
-	string(SortOrderTypeAsc): true,
+var SortOrderTypes = map[string]bool{
+	string(SortOrderTypeAsc):  true,
 	string(SortOrderTypeDesc): true,
 }
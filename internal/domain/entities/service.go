@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Service - entry of the service catalog, subscriptions with the same normalized name are linked to it
+type Service struct {
+	ID           int64         `db:"id"`
+	Name         string        `db:"name"`
+	Category     string        `db:"category"`
+	VendorURL    string        `db:"vendor_url"`
+	DefaultPrice sql.NullInt64 `db:"default_price"`
+	Currency     string        `db:"currency"`
+	CreatedAt    time.Time     `db:"created_at"`
+	UpdatedAt    time.Time     `db:"updated_at"`
+}
+
+// ServiceFilter - filter of the service catalog, zero values are not applied
+type ServiceFilter struct {
+	// Name - case-insensitive part of the name
+	Name       string
+	Category   string
+	Pagination PaginationParams
+}
+
+var ServiceUpdateFields = map[string]func(value any) bool{
+	"name":          isString,
+	"category":      isString,
+	"vendor_url":    isString,
+	"default_price": isUint32,
+	"currency":      isCurrency,
+	"updated_at":    isTime,
+}
@@ -2,20 +2,105 @@ package entities
 
 import (
 	"database/sql"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type BillingPeriodType string
+
+const (
+	BillingPeriodWeekly    BillingPeriodType = "weekly"
+	BillingPeriodMonthly   BillingPeriodType = "monthly"
+	BillingPeriodQuarterly BillingPeriodType = "quarterly"
+	BillingPeriodYearly    BillingPeriodType = "yearly"
+	// BillingPeriodCustom - billed every BillingInterval months
+	BillingPeriodCustom BillingPeriodType = "custom"
+)
+
+var BillingPeriodTypes = map[string]bool{
+	string(BillingPeriodWeekly):    true,
+	string(BillingPeriodMonthly):   true,
+	string(BillingPeriodQuarterly): true,
+	string(BillingPeriodYearly):    true,
+	string(BillingPeriodCustom):    true,
+}
+
+type SubscriptionStatusType string
+
+const (
+	SubscriptionStatusActive    SubscriptionStatusType = "active"
+	SubscriptionStatusPaused    SubscriptionStatusType = "paused"
+	SubscriptionStatusCancelled SubscriptionStatusType = "cancelled"
+	SubscriptionStatusExpired   SubscriptionStatusType = "expired"
+	SubscriptionStatusTrial     SubscriptionStatusType = "trial"
+)
+
+var SubscriptionStatusTypes = map[string]bool{
+	string(SubscriptionStatusActive):    true,
+	string(SubscriptionStatusPaused):    true,
+	string(SubscriptionStatusCancelled): true,
+	string(SubscriptionStatusExpired):   true,
+	string(SubscriptionStatusTrial):     true,
+}
+
 type Subscription struct {
-	ID          int64     `db:"id"`
-	ServiceName string    `db:"service_name"`
-	UserId      uuid.UUID `db:"user_id"`
-	Price       uint32    `db:"price"`
-	StartDate   time.Time `db:"start_date"`
-	EndDate     sql.NullTime `db:"end_date"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
+	ID              int64                  `db:"id"`
+	ServiceName     string                 `db:"service_name"`
+	UserId          uuid.UUID              `db:"user_id"`
+	Price           uint32                 `db:"price"`
+	Currency        string                 `db:"currency"`
+	BillingPeriod   BillingPeriodType      `db:"billing_period"`
+	BillingInterval uint16                 `db:"billing_interval"`
+	StartDate       time.Time              `db:"start_date"`
+	EndDate         sql.NullTime           `db:"end_date"`
+	TrialEndDate    sql.NullTime           `db:"trial_end_date"`
+	Status          SubscriptionStatusType `db:"status"`
+	StatusChangedAt time.Time              `db:"status_changed_at"`
+	CreatedAt       time.Time              `db:"created_at"`
+	UpdatedAt       time.Time              `db:"updated_at"`
+	DeletedAt       sql.NullTime           `db:"deleted_at"`
+	Version         int64                  `db:"version"`
+	// ServiceID - catalog entry of the service, linked by the normalized service name
+	ServiceID sql.NullInt64 `db:"service_id"`
+	// Category - normalized category the spend of the subscription is grouped by
+	Category string `db:"category"`
+	// Tags - normalized free-form labels of the subscription
+	Tags TagList `db:"tags"`
+	// Rank - similarity of the service name to the search text, set only by the search
+	Rank float32 `db:"rank"`
+
+	ConvertedPrice *Converted `db:"-"`
+}
+
+// NormalizeServiceName - service name compared by the search and the name filters:
+// lower case with single spaces, as the service_name_normalized column
+func NormalizeServiceName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// Converted - amount converted into the reporting currency
+type Converted struct {
+	Amount   float64
+	Currency string
+	Rate     float64
+}
+
+// SubscriptionStatusChange - period the subscription stayed in the status
+type SubscriptionStatusChange struct {
+	ID             int64                  `db:"id"`
+	SubscriptionID int64                  `db:"subscription_id"`
+	Status         SubscriptionStatusType `db:"status"`
+	StartedAt      time.Time              `db:"started_at"`
+	EndedAt        sql.NullTime           `db:"ended_at"`
+}
+
+// PriceChange - price of the subscription effective from the month
+type PriceChange struct {
+	Price         uint32    `db:"price"`
+	EffectiveFrom time.Time `db:"effective_from"`
+	CreatedAt     time.Time `db:"created_at"`
 }
 
 type PaginationInfo struct {
@@ -23,6 +108,11 @@ type PaginationInfo struct {
 	PageSize   uint16
 	TotalCount uint64
 	TotalPages uint32
+	// WithTotal - TotalCount and TotalPages are counted
+	WithTotal bool
+	HasNext   bool
+	// Next - cursor of the next page, nil on the last page
+	Next *Cursor
 }
 
 type ResponseListSubscription struct {
@@ -31,12 +121,17 @@ type ResponseListSubscription struct {
 }
 
 var SubscriptionUpdateFields = map[string]func(value any) bool{
-	"service_name": isString,
-	"user_id":      isUUID,
-	"price":        isUint32,
-	"start_date":   isTime,
-	"end_date":     isTime,
-	"updated_at":   isTime,
+	"service_name":     isString,
+	"user_id":          isUUID,
+	"price":            isPrice,
+	"currency":         isCurrency,
+	"billing_period":   isBillingPeriod,
+	"billing_interval": isUint16,
+	"start_date":       isTime,
+	"end_date":         isTime,
+	"trial_end_date":   isTime,
+	"category":         isString,
+	"updated_at":       isTime,
 }
 
 func isString(value any) bool {
@@ -54,6 +149,27 @@ func isUint32(value any) bool {
 	return ok
 }
 
+// isPrice - the price is never zero, so a zero value left by the request is not written
+func isPrice(value any) bool {
+	price, ok := value.(uint32)
+	return ok && price > 0
+}
+
+func isUint16(value any) bool {
+	_, ok := value.(uint16)
+	return ok
+}
+
+func isBillingPeriod(value any) bool {
+	period, ok := value.(BillingPeriodType)
+	return ok && BillingPeriodTypes[string(period)]
+}
+
+func isCurrency(value any) bool {
+	currency, ok := value.(string)
+	return ok && len(currency) == 3
+}
+
 func isTime(value any) bool {
 	_, ok := value.(time.Time)
 	return ok
@@ -8,7 +8,7 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestSubscription_IsString(t *testing.T){
+func TestSubscription_IsString(t *testing.T) {
 	dataSt := "test"
 
 	res := isString(dataSt)
@@ -16,7 +16,7 @@ func TestSubscription_IsString(t *testing.T){
 	require.True(t, res)
 }
 
-func TestSubscription_IsUUID(t *testing.T){
+func TestSubscription_IsUUID(t *testing.T) {
 	dataUUID := uuid.New()
 
 	res := isUUID(dataUUID)
@@ -24,7 +24,7 @@ func TestSubscription_IsUUID(t *testing.T){
 	require.True(t, res)
 }
 
-func TestSubscription_IsUint32(t *testing.T){
+func TestSubscription_IsUint32(t *testing.T) {
 	dataUint32 := uint32(1)
 
 	res := isUint32(dataUint32)
@@ -32,10 +32,41 @@ func TestSubscription_IsUint32(t *testing.T){
 	require.True(t, res)
 }
 
-func TestSubscription_IsTime(t *testing.T){
+func TestSubscription_IsPrice(t *testing.T) {
+	require.True(t, isPrice(uint32(100)))
+	require.False(t, isPrice(uint32(0)))
+	require.False(t, isPrice(100))
+}
+
+func TestSubscription_IsTime(t *testing.T) {
 	dataTime := time.Now()
 
 	res := isTime(dataTime)
 
 	require.True(t, res)
 }
+
+func TestSubscription_IsUint16(t *testing.T) {
+	dataUint16 := uint16(1)
+
+	res := isUint16(dataUint16)
+
+	require.True(t, res)
+}
+
+func TestSubscription_IsBillingPeriod(t *testing.T) {
+	require.True(t, isBillingPeriod(BillingPeriodYearly))
+	require.False(t, isBillingPeriod(BillingPeriodType("daily")))
+	require.False(t, isBillingPeriod("yearly"))
+}
+
+func TestSubscription_IsCurrency(t *testing.T) {
+	require.True(t, isCurrency("USD"))
+	require.False(t, isCurrency("US"))
+	require.False(t, isCurrency(uint32(840)))
+}
+
+func TestSubscription_NormalizeServiceName(t *testing.T) {
+	require.Equal(t, "youtube premium", NormalizeServiceName("YouTube Premium"))
+	require.Equal(t, "youtube premium", NormalizeServiceName("  youtube \t Premium "))
+}
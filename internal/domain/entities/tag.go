@@ -0,0 +1,70 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Tag - free-form label of subscriptions
+type Tag struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+	// Subscriptions - number of subscriptions labeled with the tag
+	Subscriptions int64 `db:"subscriptions"`
+}
+
+// TagFilter - filter of the tags, zero values are not applied
+type TagFilter struct {
+	// Name - part of the normalized name
+	Name       string
+	Pagination PaginationParams
+}
+
+// TagList - tags of the subscription ordered by name, scanned from the JSON array of the names
+type TagList []string
+
+// Scan - implements sql.Scanner
+func (l *TagList) Scan(src any) error {
+	var data []byte
+	switch value := src.(type) {
+	case nil:
+		*l = TagList{}
+		return nil
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	default:
+		return fmt.Errorf("TagList.Scan: unsupported type %T", src)
+	}
+
+	tags := make(TagList, 0)
+	if err := json.Unmarshal(data, &tags); err != nil {
+		return fmt.Errorf("TagList.Scan: %w", err)
+	}
+	*l = tags
+
+	return nil
+}
+
+// NormalizeTag - tags and categories are kept in lower case with single spaces,
+// so "Dev  Tools" and "dev tools" group together
+func NormalizeTag(name string) string {
+	return NormalizeServiceName(name)
+}
+
+// NormalizeTags - normalized tags ordered by name without empty and repeated ones
+func NormalizeTags(names []string) TagList {
+	tags := make(TagList, 0, len(names))
+	for _, name := range names {
+		if tag := NormalizeTag(name); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	slices.Sort(tags)
+
+	return slices.Compact(tags)
+}
@@ -0,0 +1,43 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTag_NormalizeTags(t *testing.T) {
+	tags := NormalizeTags([]string{" Dev  Tools", "streaming", "", "dev tools", "Cloud"})
+
+	require.Equal(t, TagList{"cloud", "dev tools", "streaming"}, tags)
+}
+
+func TestTag_TagListScan(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      any
+		expected TagList
+		wantErr  bool
+	}{
+		{name: "bytes", src: []byte(`["cloud","streaming"]`), expected: TagList{"cloud", "streaming"}},
+		{name: "string", src: `["cloud"]`, expected: TagList{"cloud"}},
+		{name: "empty", src: `[]`, expected: TagList{}},
+		{name: "nil", src: nil, expected: TagList{}},
+		{name: "invalidJSON", src: `{`, wantErr: true},
+		{name: "invalidType", src: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tags TagList
+
+			err := tags.Scan(tt.src)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, tags)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// User - owner of subscriptions, subscriptions reference an existing user
+type User struct {
+	ID        uuid.UUID `db:"id"`
+	Name      string    `db:"name"`
+	Email     string    `db:"email"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// UserFilter - filter of the users, zero values are not applied
+type UserFilter struct {
+	// Search - case-insensitive part of the name or email
+	Search     string
+	Pagination PaginationParams
+}
+
+var UserUpdateFields = map[string]func(value any) bool{
+	"name":       isString,
+	"email":      isString,
+	"updated_at": isTime,
+}
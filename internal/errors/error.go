@@ -7,11 +7,13 @@ import (
 )
 
 var (
-	ErrNotFound      = New("not found")
-	ErrAlreadyExists = New("already exists")
-	ErrInvalidInput  = New("invalid input")
-	ErrUnauthorized  = New("unauthorized")
-	ErrInternal      = New("internal error")
+	ErrNotFound          = New("not found")
+	ErrAlreadyExists     = New("already exists")
+	ErrInvalidInput      = New("invalid input")
+	ErrUnauthorized      = New("unauthorized")
+	ErrInternal          = New("internal error")
+	ErrInvalidTransition = New("invalid status transition")
+	ErrConflict          = New("conflict")
 )
 
 // Error - represents a domain error
@@ -0,0 +1,75 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	errs "github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/exchangerate"
+)
+
+// rates - content of the exchange rates file
+type rates struct {
+	Base  string             `yaml:"base"`
+	Rates map[string]float64 `yaml:"rates"`
+}
+
+type provider struct {
+	rates map[string]float64
+}
+
+// New - Constructor ExchangeRateProvider loading rates from YAML file.
+// Rates are amounts of currency for one unit of the base currency.
+func New(filePath string) (exchangerate.ExchangeRateProvider, error) {
+	file, err := os.Open(filepath.Clean(filePath))
+	if err != nil {
+		return nil, errs.Wrap(err, "exchangerateFile.New: open file")
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var data rates
+	if err := yaml.NewDecoder(file).Decode(&data); err != nil {
+		return nil, errs.Wrap(err, "exchangerateFile.New: decode file")
+	}
+
+	if data.Base == "" {
+		return nil, errs.Wrap(errs.ErrInvalidInput, "exchangerateFile.New: empty base currency")
+	}
+
+	p := &provider{rates: map[string]float64{strings.ToUpper(data.Base): 1}}
+	for currency, rate := range data.Rates {
+		if rate <= 0 {
+			return nil, errs.Wrap(errs.ErrInvalidInput, fmt.Sprintf("exchangerateFile.New: invalid rate %s", currency))
+		}
+		p.rates[strings.ToUpper(currency)] = rate
+	}
+
+	return p, nil
+}
+
+// Rate - returns amount of currency `to` for one unit of currency `from`
+func (p *provider) Rate(_ context.Context, from, to string) (float64, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return 1, nil
+	}
+
+	rateFrom, ok := p.rates[from]
+	if !ok {
+		return 0, errs.Wrap(errs.ErrInvalidInput, fmt.Sprintf("exchangerateFile.Rate: unknown currency %s", from))
+	}
+
+	rateTo, ok := p.rates[to]
+	if !ok {
+		return 0, errs.Wrap(errs.ErrInvalidInput, fmt.Sprintf("exchangerateFile.Rate: unknown currency %s", to))
+	}
+
+	return rateTo / rateFrom, nil
+}
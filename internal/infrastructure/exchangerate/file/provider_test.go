@@ -0,0 +1,84 @@
+package file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	errs "github.com/mathbdw/subscription-service/internal/errors"
+)
+
+func writeRates(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "rates.yml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestProvider_New_ErrorFile(t *testing.T) {
+	_, err := New(filepath.Join(t.TempDir(), "missing.yml"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exchangerateFile.New: open file")
+}
+
+func TestProvider_New_ErrorDecode(t *testing.T) {
+	_, err := New(writeRates(t, "base: [RUB"))
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exchangerateFile.New: decode file")
+}
+
+func TestProvider_New_ErrorBase(t *testing.T) {
+	_, err := New(writeRates(t, "rates:\n  USD: 0.011\n"))
+
+	require.ErrorIs(t, err, errs.ErrInvalidInput)
+}
+
+func TestProvider_New_ErrorRate(t *testing.T) {
+	_, err := New(writeRates(t, "base: RUB\nrates:\n  USD: 0\n"))
+
+	require.ErrorIs(t, err, errs.ErrInvalidInput)
+}
+
+func TestProvider_Rate(t *testing.T) {
+	p, err := New(writeRates(t, "base: RUB\nrates:\n  USD: 0.0125\n  eur: 0.01\n"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		from   string
+		to     string
+		expect float64
+	}{
+		{name: "sameCurrency", from: "USD", to: "usd", expect: 1},
+		{name: "fromBase", from: "RUB", to: "USD", expect: 0.0125},
+		{name: "toBase", from: "EUR", to: "RUB", expect: 100},
+		{name: "cross", from: "USD", to: "EUR", expect: 0.8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := p.Rate(context.Background(), tt.from, tt.to)
+
+			require.NoError(t, err)
+			assert.InDelta(t, tt.expect, rate, 1e-9)
+		})
+	}
+}
+
+func TestProvider_Rate_ErrorUnknown(t *testing.T) {
+	p, err := New(writeRates(t, "base: RUB\nrates:\n  USD: 0.0125\n"))
+	require.NoError(t, err)
+
+	_, err = p.Rate(context.Background(), "USD", "XXX")
+	require.ErrorIs(t, err, errs.ErrInvalidInput)
+
+	_, err = p.Rate(context.Background(), "XXX", "USD")
+	require.ErrorIs(t, err, errs.ErrInvalidInput)
+}
@@ -8,10 +8,10 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestOption_Address(t *testing.T){
+func TestOption_Address(t *testing.T) {
 	h := "localhost"
 	p := uint16(1111)
-	
+
 	s := &Server{}
 	opt := Address(h, p)
 	opt(s)
@@ -19,7 +19,7 @@ func TestOption_Address(t *testing.T){
 	require.Equal(t, fmt.Sprintf("%s:%d", h, p), s.address)
 }
 
-func TestOption_Prefork(t *testing.T){
+func TestOption_Prefork(t *testing.T) {
 	s := &Server{}
 	opt := Prefork(true)
 	opt(s)
@@ -27,7 +27,7 @@ func TestOption_Prefork(t *testing.T){
 	require.Equal(t, true, s.prefork)
 }
 
-func TestOption_ReadTimeout(t *testing.T){
+func TestOption_ReadTimeout(t *testing.T) {
 	tm := 5 * time.Second
 	s := &Server{}
 	opt := ReadTimeout(tm)
@@ -36,7 +36,7 @@ func TestOption_ReadTimeout(t *testing.T){
 	require.Equal(t, tm, s.readTimeout)
 }
 
-func TestOption_WriteTimeout(t *testing.T){
+func TestOption_WriteTimeout(t *testing.T) {
 	tm := 5 * time.Second
 	s := &Server{}
 	opt := WriteTimeout(tm)
@@ -45,11 +45,19 @@ func TestOption_WriteTimeout(t *testing.T){
 	require.Equal(t, tm, s.writeTimeout)
 }
 
-func TestOption_ShutdownTimeout(t *testing.T){
+func TestOption_ShutdownTimeout(t *testing.T) {
 	tm := 5 * time.Second
 	s := &Server{}
 	opt := ShutdownTimeout(tm)
 	opt(s)
 
 	require.Equal(t, tm, s.shutdownTimeout)
-}
\ No newline at end of file
+}
+
+func TestOption_StreamRequestBody(t *testing.T) {
+	s := &Server{}
+	opt := StreamRequestBody(true)
+	opt(s)
+
+	require.Equal(t, true, s.streamRequestBody)
+}
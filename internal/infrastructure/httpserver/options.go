@@ -43,3 +43,10 @@ func ShutdownTimeout(timeout time.Duration) Option {
 		s.shutdownTimeout = timeout
 	}
 }
+
+// StreamRequestBody - request body is read by the handler as a stream
+func StreamRequestBody(stream bool) Option {
+	return func(s *Server) {
+		s.streamRequestBody = stream
+	}
+}
@@ -23,6 +23,8 @@ type Server struct {
 	readTimeout     time.Duration
 	writeTimeout    time.Duration
 	shutdownTimeout time.Duration
+
+	streamRequestBody bool
 }
 
 // New - constructor server.
@@ -47,6 +49,8 @@ func New(opts ...Option) *Server {
 		WriteTimeout: s.writeTimeout,
 		JSONDecoder:  json.Unmarshal,
 		JSONEncoder:  json.Marshal,
+
+		StreamRequestBody: s.streamRequestBody,
 	})
 
 	s.App = app
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	errs "github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/interfaces/repositories"
+)
+
+type auditRepository struct {
+	querier sqlx.ExtContext
+	builder sq.StatementBuilderType
+
+	logger observability.Logger
+}
+
+// NewAuditRepository - Constructor AuditRepository
+func NewAuditRepository(querier sqlx.ExtContext, builder sq.StatementBuilderType, logger observability.Logger) repositories.AuditRepository {
+	return &auditRepository{
+		querier: querier,
+		builder: builder,
+
+		logger: logger,
+	}
+}
+
+var (
+	tableAudit   = "audit_log"
+	columnsAudit = []string{"id", "entity_type", "entity_id", "action", "actor", "data_before", "data_after", "diff", "created_at"}
+)
+
+// conn - returns the transaction carried by the context or the repository querier
+func (r *auditRepository) conn(ctx context.Context) sqlx.ExtContext {
+	return querierFromContext(ctx, r.querier)
+}
+
+// Create - create new row, it joins the transaction of the audited change
+func (r *auditRepository) Create(ctx context.Context, entry entities.AuditEntry) error {
+	query, args, err := r.builder.Insert(tableAudit).
+		SetMap(map[string]any{
+			"entity_type": entry.EntityType,
+			"entity_id":   entry.EntityID,
+			"action":      entry.Action,
+			"actor":       entry.Actor,
+			"data_before": jsonOrNull(entry.Before),
+			"data_after":  jsonOrNull(entry.After),
+			"diff":        jsonOrNull(entry.Diff),
+		}).
+		ToSql()
+	if err != nil {
+		return errs.Wrap(err, "auditRepositories.Create: build query")
+	}
+
+	if _, err = r.conn(ctx).ExecContext(ctx, query, args...); err != nil {
+		return errs.Wrap(err, "auditRepositories.Create: exec query")
+	}
+
+	return nil
+}
+
+// List - Returns audit entries by filter, the latest go first
+func (r *auditRepository) List(ctx context.Context, filter entities.AuditFilter) ([]entities.AuditEntry, error) {
+	query := r.builder.Select(columnsAudit...).From(tableAudit)
+	query = conditionAudit(query, filter)
+	query = query.OrderBy("created_at DESC", "id DESC")
+
+	if filter.Pagination.Limit > 0 {
+		query = query.Limit(filter.Pagination.Limit)
+		if filter.Pagination.Page > 1 {
+			query = query.Offset((filter.Pagination.Page - 1) * filter.Pagination.Limit)
+		}
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, errs.Wrap(err, "auditRepositories.List: build query")
+	}
+
+	rows, err := r.conn(ctx).QueryxContext(ctx, sql, args...)
+	if err != nil {
+		return nil, errs.Wrap(err, "auditRepositories.List: get query")
+	}
+	defer rows.Close()
+
+	entries := make([]entities.AuditEntry, 0)
+	for rows.Next() {
+		var entry entities.AuditEntry
+		err = rows.StructScan(&entry)
+		if err != nil {
+			return nil, errs.Wrap(err, "auditRepositories.List: scan query")
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errs.Wrap(err, "auditRepositories.List: iteration rows")
+	}
+
+	return entries, nil
+}
+
+// conditionAudit - SelectBuilder query condition builder for audit log
+func conditionAudit(query sq.SelectBuilder, filter entities.AuditFilter) sq.SelectBuilder {
+	if filter.EntityType != "" {
+		query = query.Where(sq.Eq{"entity_type": filter.EntityType})
+	}
+
+	if filter.EntityID != 0 {
+		query = query.Where(sq.Eq{"entity_id": filter.EntityID})
+	}
+
+	if filter.Actor != "" {
+		query = query.Where(sq.Eq{"actor": filter.Actor})
+	}
+
+	if filter.Period.From != nil {
+		query = query.Where(sq.GtOrEq{"created_at": filter.Period.From})
+	}
+
+	if filter.Period.To != nil {
+		query = query.Where(sq.LtOrEq{"created_at": filter.Period.To})
+	}
+
+	return query
+}
+
+// jsonOrNull - returns JSON document as query argument, empty document is stored as NULL
+func jsonOrNull(data []byte) any {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return string(data)
+}
@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/mocks"
+)
+
+var auditTest = entities.AuditEntry{
+	ID:         1,
+	EntityType: entities.AuditEntitySubscription,
+	EntityID:   1,
+	Action:     entities.AuditActionUpdate,
+	Actor:      "admin",
+	Before:     []byte(`{"price":100}`),
+	After:      []byte(`{"price":200}`),
+	Diff:       []byte(`{"price":{"before":100,"after":200}}`),
+	CreatedAt:  time.Now(),
+}
+
+func TestAudit_Create_Success(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewAuditRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO audit_log (action,actor,data_after,data_before,diff,entity_id,entity_type) VALUES ($1,$2,$3,$4,$5,$6,$7)")).
+		WithArgs(auditTest.Action, auditTest.Actor, nil, string(auditTest.Before), string(auditTest.Diff), auditTest.EntityID, auditTest.EntityType).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	entry := auditTest
+	entry.After = nil
+	err = repo.Create(ctx, entry)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAudit_Create_ErrorExec(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewAuditRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO audit_log")).
+		WillReturnError(errors.New("exec"))
+
+	err = repo.Create(ctx, auditTest)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "auditRepositories.Create: exec query")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAudit_List_Success(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewAuditRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	filter := entities.AuditFilter{
+		EntityType: entities.AuditEntitySubscription,
+		EntityID:   auditTest.EntityID,
+		Actor:      auditTest.Actor,
+		Period:     entities.DateRange{From: &from},
+		Pagination: entities.PaginationParams{Page: 2, Limit: 20},
+	}
+
+	rows := sqlmock.NewRows([]string{"id", "entity_type", "entity_id", "action", "actor", "data_before", "data_after", "diff", "created_at"}).
+		AddRow(auditTest.ID, auditTest.EntityType, auditTest.EntityID, auditTest.Action, auditTest.Actor, auditTest.Before, auditTest.After, auditTest.Diff, auditTest.CreatedAt)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, entity_type, entity_id, action, actor, data_before, data_after, diff, created_at FROM audit_log "+
+		"WHERE entity_type = $1 AND entity_id = $2 AND actor = $3 AND created_at >= $4 ORDER BY created_at DESC, id DESC LIMIT 20 OFFSET 20")).
+		WithArgs(filter.EntityType, filter.EntityID, filter.Actor, &from).
+		WillReturnRows(rows)
+
+	entries, err := repo.List(ctx, filter)
+
+	require.NoError(t, err)
+	require.Equal(t, []entities.AuditEntry{auditTest}, entries)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAudit_List_ErrorGetQuery(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewAuditRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, entity_type, entity_id, action, actor, data_before, data_after, diff, created_at FROM audit_log ORDER BY created_at DESC, id DESC")).
+		WillReturnError(errors.New("query"))
+
+	entries, err := repo.List(ctx, entities.AuditFilter{})
+
+	require.Nil(t, entries)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "auditRepositories.List: get query")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -5,15 +5,33 @@ import "github.com/mathbdw/subscription-service/internal/domain/entities"
 // SubscriptionToMap - convert struct Subscription to map
 func SubscriptionToMap(subs entities.Subscription) map[string]any {
 	data := map[string]any{
-		"service_name": subs.ServiceName,
-		"user_id":      subs.UserId,
-		"price":        subs.Price,
-		"start_date":   subs.StartDate,
+		"service_name":     subs.ServiceName,
+		"user_id":          subs.UserId,
+		"price":            subs.Price,
+		"billing_period":   subs.BillingPeriod,
+		"billing_interval": subs.BillingInterval,
+		"start_date":       subs.StartDate,
+	}
+
+	if subs.Currency != "" {
+		data["currency"] = subs.Currency
 	}
 
 	if subs.EndDate.Valid {
 		data["end_date"] = subs.EndDate.Time.Format("2006-01-02")
 	}
 
+	if subs.TrialEndDate.Valid {
+		data["trial_end_date"] = subs.TrialEndDate.Time.Format("2006-01-02")
+	}
+
+	if category := entities.NormalizeTag(subs.Category); category != "" {
+		data["category"] = category
+	}
+
+	if subs.Status != "" {
+		data["status"] = subs.Status
+	}
+
 	return data
 }
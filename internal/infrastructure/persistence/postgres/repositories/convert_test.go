@@ -12,33 +12,49 @@ import (
 
 func TestUser_SubscriptionToMap(t *testing.T) {
 	subsTest := entities.Subscription{
-		ServiceName: "test service",
-		UserId:      uuid.New(),
-		Price:       100,
-		StartDate:   time.Now(),
+		ServiceName:     "test service",
+		UserId:          uuid.New(),
+		Price:           100,
+		BillingPeriod:   entities.BillingPeriodMonthly,
+		BillingInterval: 1,
+		StartDate:       time.Now(),
 	}
 
 	tests := []struct {
-		name    string
-		endTime sql.NullTime
+		name      string
+		endTime   sql.NullTime
+		trialEnd  sql.NullTime
+		currency  string
 		expectLen int
 	}{
 		{
-			name:  "withoutEndTime",
-			expectLen: 4,
+			name:      "withoutEndTime",
+			expectLen: 6,
 		},
 		{
-			name:    "withEndTime",
-			expectLen: 5,
-			endTime: sql.NullTime{Time: time.Now(), Valid: true},
+			name:      "withEndTime",
+			expectLen: 7,
+			endTime:   sql.NullTime{Time: time.Now(), Valid: true},
+		},
+		{
+			name:      "withCurrency",
+			expectLen: 7,
+			currency:  "USD",
+		},
+		{
+			name:      "withTrial",
+			expectLen: 8,
+			trialEnd:  sql.NullTime{Time: time.Now(), Valid: true},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			sub := subsTest
-
-			if tt.expectLen > 4 {
-				sub.EndDate = tt.endTime
+			sub.EndDate = tt.endTime
+			sub.Currency = tt.currency
+			sub.TrialEndDate = tt.trialEnd
+			if tt.trialEnd.Valid {
+				sub.Status = entities.SubscriptionStatusTrial
 			}
 
 			resMap := SubscriptionToMap(sub)
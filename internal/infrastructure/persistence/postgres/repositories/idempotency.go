@@ -0,0 +1,158 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	errs "github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/interfaces/repositories"
+)
+
+type idempotencyRepository struct {
+	querier sqlx.ExtContext
+	builder sq.StatementBuilderType
+
+	logger observability.Logger
+}
+
+// NewIdempotencyRepository - Constructor IdempotencyRepository
+func NewIdempotencyRepository(querier sqlx.ExtContext, builder sq.StatementBuilderType, logger observability.Logger) repositories.IdempotencyRepository {
+	return &idempotencyRepository{
+		querier: querier,
+		builder: builder,
+
+		logger: logger,
+	}
+}
+
+var (
+	tableIdempotency   = "idempotency_key"
+	columnsIdempotency = []string{"key", "request_hash", "status_code", "response_body", "response_headers", "created_at", "expires_at"}
+)
+
+// conflictIdempotency - the key is taken over only when the stored one has expired but is not deleted yet
+const conflictIdempotency = "ON CONFLICT (key) DO UPDATE SET request_hash = EXCLUDED.request_hash, status_code = EXCLUDED.status_code, " +
+	"response_body = EXCLUDED.response_body, response_headers = EXCLUDED.response_headers, created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at " +
+	"WHERE idempotency_key.expires_at <= EXCLUDED.created_at"
+
+// conn - returns the transaction carried by the context or the repository querier
+func (r *idempotencyRepository) conn(ctx context.Context) sqlx.ExtContext {
+	return querierFromContext(ctx, r.querier)
+}
+
+// Get - Returns the key not expired at the time
+func (r *idempotencyRepository) Get(ctx context.Context, key string, at time.Time) (*entities.IdempotencyKey, error) {
+	query, args, err := r.builder.Select(columnsIdempotency...).
+		From(tableIdempotency).
+		Where(sq.Eq{"key": key}).
+		Where(sq.Gt{"expires_at": at}).
+		ToSql()
+	if err != nil {
+		return nil, errs.Wrap(err, "idempotencyRepositories.Get: build query")
+	}
+
+	stored := &entities.IdempotencyKey{}
+
+	err = r.conn(ctx).QueryRowxContext(ctx, query, args...).StructScan(stored)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrNotFound
+		}
+		return nil, errs.Wrap(err, "idempotencyRepositories.Get: scan query")
+	}
+
+	return stored, nil
+}
+
+// Create - create new row, ErrAlreadyExists is returned while the key stored before has not expired
+func (r *idempotencyRepository) Create(ctx context.Context, key entities.IdempotencyKey) error {
+	query, args, err := r.builder.Insert(tableIdempotency).
+		SetMap(map[string]any{
+			"key":              key.Key,
+			"request_hash":     key.RequestHash,
+			"status_code":      key.StatusCode,
+			"response_body":    key.ResponseBody,
+			"response_headers": key.ResponseHeaders,
+			"created_at":       key.CreatedAt,
+			"expires_at":       key.ExpiresAt,
+		}).
+		Suffix(conflictIdempotency).
+		ToSql()
+	if err != nil {
+		return errs.Wrap(err, "idempotencyRepositories.Create: build query")
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return errs.Wrap(err, "idempotencyRepositories.Create: exec query")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return errs.Wrap(err, "idempotencyRepositories.Create: get affected rows")
+	}
+
+	if rowsAffected == 0 {
+		return errs.Wrap(errs.ErrAlreadyExists, "idempotencyRepositories.Create")
+	}
+
+	return nil
+}
+
+// SetResponse - stores the response of the request with its replayed headers for the key created before
+func (r *idempotencyRepository) SetResponse(ctx context.Context, key string, resp entities.IdempotentResponse) error {
+	query, args, err := r.builder.Update(tableIdempotency).
+		Set("status_code", resp.StatusCode).
+		Set("response_body", resp.Body).
+		Set("response_headers", resp.Headers).
+		Where(sq.Eq{"key": key}).
+		ToSql()
+	if err != nil {
+		return errs.Wrap(err, "idempotencyRepositories.SetResponse: build query")
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return errs.Wrap(err, "idempotencyRepositories.SetResponse: exec query")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return errs.Wrap(err, "idempotencyRepositories.SetResponse: get affected rows")
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteExpired - hard deletes keys expired at the time, returns the number of deleted rows
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context, at time.Time) (int64, error) {
+	query, args, err := r.builder.Delete(tableIdempotency).
+		Where(sq.LtOrEq{"expires_at": at}).
+		ToSql()
+	if err != nil {
+		return 0, errs.Wrap(err, "idempotencyRepositories.DeleteExpired: build query")
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, errs.Wrap(err, "idempotencyRepositories.DeleteExpired: exec query")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, errs.Wrap(err, "idempotencyRepositories.DeleteExpired: get affected rows")
+	}
+
+	return rowsAffected, nil
+}
@@ -0,0 +1,212 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/mocks"
+)
+
+var idempotencyTest = entities.IdempotencyKey{
+	Key:             "key-1",
+	RequestHash:     "hash",
+	StatusCode:      201,
+	ResponseBody:    []byte(`{"id":1}`),
+	ResponseHeaders: entities.ResponseHeaders{"Location": "/api/v1/subscription/1"},
+	CreatedAt:       time.Now(),
+	ExpiresAt:       time.Now().Add(time.Hour),
+}
+
+func TestIdempotency_Get_Success(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewIdempotencyRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+	now := time.Now()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT key, request_hash, status_code, response_body, response_headers, created_at, expires_at FROM idempotency_key WHERE key = $1 AND expires_at > $2")).
+		WithArgs(idempotencyTest.Key, now).
+		WillReturnRows(sqlmock.NewRows(columnsIdempotency).
+			AddRow(idempotencyTest.Key, idempotencyTest.RequestHash, idempotencyTest.StatusCode, idempotencyTest.ResponseBody, []byte(`{"Location":"/api/v1/subscription/1"}`), idempotencyTest.CreatedAt, idempotencyTest.ExpiresAt))
+
+	stored, err := repo.Get(ctx, idempotencyTest.Key, now)
+
+	require.NoError(t, err)
+	assert.Equal(t, idempotencyTest.RequestHash, stored.RequestHash)
+	assert.Equal(t, idempotencyTest.StatusCode, stored.StatusCode)
+	assert.Equal(t, idempotencyTest.ResponseBody, stored.ResponseBody)
+	assert.Equal(t, idempotencyTest.ResponseHeaders, stored.ResponseHeaders)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotency_Get_ErrorNotFound(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewIdempotencyRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT key, request_hash, status_code, response_body, response_headers, created_at, expires_at FROM idempotency_key")).
+		WillReturnRows(sqlmock.NewRows(columnsIdempotency))
+
+	_, err = repo.Get(ctx, idempotencyTest.Key, time.Now())
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotency_Create_Success(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewIdempotencyRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO idempotency_key (created_at,expires_at,key,request_hash,response_body,response_headers,status_code) VALUES ($1,$2,$3,$4,$5,$6,$7) "+conflictIdempotency)).
+		WithArgs(idempotencyTest.CreatedAt, idempotencyTest.ExpiresAt, idempotencyTest.Key, idempotencyTest.RequestHash, idempotencyTest.ResponseBody, []byte(`{"Location":"/api/v1/subscription/1"}`), idempotencyTest.StatusCode).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.Create(ctx, idempotencyTest)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotency_Create_ErrorAlreadyExists(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewIdempotencyRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO idempotency_key")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Create(ctx, idempotencyTest)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrAlreadyExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotency_Create_ErrorExec(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewIdempotencyRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO idempotency_key")).
+		WillReturnError(errors.New("exec"))
+
+	err = repo.Create(ctx, idempotencyTest)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "idempotencyRepositories.Create: exec query")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotency_SetResponse_Success(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewIdempotencyRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE idempotency_key SET status_code = $1, response_body = $2, response_headers = $3 WHERE key = $4")).
+		WithArgs(201, []byte(`{"id":1}`), []byte(`{"ETag":"\"1\""}`), idempotencyTest.Key).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.SetResponse(ctx, idempotencyTest.Key, entities.IdempotentResponse{StatusCode: 201, Body: []byte(`{"id":1}`), Headers: entities.ResponseHeaders{"ETag": `"1"`}})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotency_SetResponse_ErrorNotFound(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewIdempotencyRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE idempotency_key")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.SetResponse(ctx, idempotencyTest.Key, entities.IdempotentResponse{StatusCode: 201})
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotency_DeleteExpired_Success(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewIdempotencyRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+	now := time.Now()
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM idempotency_key WHERE expires_at <= $1")).
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	deleted, err := repo.DeleteExpired(ctx, now)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// codeForeignKeyViolation - SQLSTATE of the row referencing a missing row or of the deleted row still referenced
+const codeForeignKeyViolation = "23503"
+
+// isForeignKeyViolation - err is returned by PostgreSQL for the violated foreign key
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && pgErr.Code == codeForeignKeyViolation
+}
@@ -2,20 +2,23 @@ package repositories
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
-	
+
 	"github.com/mathbdw/subscription-service/internal/domain/entities"
 )
 
 // conditionList - SelectBuilder query condition builder for list
 func conditionList(query sq.SelectBuilder, params entities.FilterParams) sq.SelectBuilder {
 	if params.ServiceName != "" {
-		query = query.Where(sq.Like{"service_name": params.ServiceName})
+		query = query.Where(sq.Eq{"service_name_normalized": entities.NormalizeServiceName(params.ServiceName)})
 	}
 
-	if params.UserId != uuid.Nil{
+	if params.UserId != uuid.Nil {
 		query = query.Where(sq.Eq{"user_id": params.UserId})
 	}
 
@@ -27,53 +30,429 @@ func conditionList(query sq.SelectBuilder, params entities.FilterParams) sq.Sele
 		query = query.Where(sq.LtOrEq{"start_date": params.StartDate.To})
 	}
 
+	if len(params.ServiceNames) > 0 {
+		names := make([]string, 0, len(params.ServiceNames))
+		for _, name := range params.ServiceNames {
+			names = append(names, entities.NormalizeServiceName(name))
+		}
+		query = query.Where(sq.Eq{"service_name_normalized": names})
+	}
+
+	if len(params.UserIds) > 0 {
+		query = query.Where(sq.Eq{"user_id": params.UserIds})
+	}
+
+	if params.ServiceNamePrefix != "" {
+		query = query.Where(sq.ILike{"service_name": escapeLike(params.ServiceNamePrefix) + "%"})
+	}
+
+	if params.ServiceNameContains != "" {
+		query = query.Where(sq.ILike{"service_name": "%" + escapeLike(params.ServiceNameContains) + "%"})
+	}
+
+	if params.Price.Min != nil {
+		query = query.Where(sq.GtOrEq{"price": *params.Price.Min})
+	}
+
+	if params.Price.Max != nil {
+		query = query.Where(sq.LtOrEq{"price": *params.Price.Max})
+	}
+
+	if params.EndDate.From != nil {
+		query = query.Where(sq.GtOrEq{"end_date": params.EndDate.From})
+	}
+
+	if params.EndDate.To != nil {
+		query = query.Where(sq.LtOrEq{"end_date": params.EndDate.To})
+	}
+
+	if params.ActiveAt != nil {
+		query = query.Where(sq.And{
+			sq.LtOrEq{"start_date": params.ActiveAt},
+			sq.Or{sq.Eq{"end_date": nil}, sq.GtOrEq{"end_date": params.ActiveAt}},
+		})
+	}
+
+	if params.NoEndDate {
+		query = query.Where(sq.Eq{"end_date": nil})
+	}
+
+	if search := entities.NormalizeServiceName(params.Search); search != "" {
+		query = query.Where(sq.Or{
+			sq.Like{"service_name_normalized": "%" + escapeLike(search) + "%"},
+			sq.Expr("? <% service_name_normalized", search),
+		})
+	}
+
+	if params.Tag != "" {
+		query = query.Where(conditionTag(table+".id", params.Tag))
+	}
+
+	if params.Category != "" {
+		query = query.Where(sq.Eq{"category": entities.NormalizeTag(params.Category)})
+	}
+
 	return query
 }
 
-// paginationList - SelectBuilder query pagination builder for list
-func paginationList(query sq.SelectBuilder, totalCount uint64, params *entities.PaginationParams) sq.SelectBuilder {
-	limit := params.Limit
-	page := params.Page
-	offset := (page - 1) * limit
+// conditionTag - subscription with the id column is labeled with the tag
+func conditionTag(idColumn, tag string) sq.Sqlizer {
+	return sq.Expr("EXISTS (SELECT 1 FROM subscription_tags AS st JOIN tags AS t ON t.id = st.tag_id"+
+		" WHERE st.subscription_id = "+idColumn+" AND t.name = ?)", entities.NormalizeTag(tag))
+}
+
+// rankList - SelectBuilder selects the rank of the service name similarity to the search
+func rankList(query sq.SelectBuilder, search string) sq.SelectBuilder {
+	search = entities.NormalizeServiceName(search)
+	if search == "" {
+		return query
+	}
+
+	return query.Column(rankExpr+" AS rank", search)
+}
+
+// rankExpr - word similarity of the search to the service name, 1 when the search is a word of it
+const rankExpr = "word_similarity(?, service_name_normalized)"
+
+// escapeLike - escapes wildcards of the LIKE pattern, so the value is matched literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// paginationList - SelectBuilder query pagination builder for list, the page past the end is empty
+func paginationList(query sq.SelectBuilder, params entities.PaginationParams) sq.SelectBuilder {
+	return query.Limit(params.Limit).Offset((params.Page - 1) * params.Limit)
+}
+
+// sortColumn - expression the rows are sorted by, type of its cursor value and the value of the row
+type sortColumn struct {
+	expr  string
+	cast  string
+	value func(sub entities.Subscription) string
+	// search - expr takes the search text as the argument
+	search bool
+}
+
+// args - arguments of the column expr
+func (c sortColumn) args(search string) []any {
+	if !c.search {
+		return nil
+	}
+
+	return []any{entities.NormalizeServiceName(search)}
+}
 
-	if totalCount < page*limit {
-		limit = totalCount % limit
-		offset = totalCount - limit
+// sortColumns - sortable columns, rows without the end date go last as in the ascending order of NULL
+var sortColumns = map[entities.SortType]sortColumn{
+	entities.SortTypeServiceName: {expr: "service_name", cast: "text", value: func(sub entities.Subscription) string {
+		return sub.ServiceName
+	}},
+	entities.SortTypeUserID: {expr: "user_id", cast: "uuid", value: func(sub entities.Subscription) string {
+		return sub.UserId.String()
+	}},
+	entities.SortTypePrice: {expr: "price", cast: "bigint", value: func(sub entities.Subscription) string {
+		return strconv.FormatUint(uint64(sub.Price), 10)
+	}},
+	entities.SortTypeStartDate: {expr: "start_date", cast: "date", value: func(sub entities.Subscription) string {
+		return sub.StartDate.Format(time.DateOnly)
+	}},
+	entities.SortTypeEndDate: {expr: "COALESCE(end_date, 'infinity'::date)", cast: "date", value: func(sub entities.Subscription) string {
+		if !sub.EndDate.Valid {
+			return "infinity"
+		}
+		return sub.EndDate.Time.Format(time.DateOnly)
+	}},
+	entities.SortTypeCreatedAt: {expr: "created_at", cast: "timestamp", value: func(sub entities.Subscription) string {
+		return sub.CreatedAt.Format(time.RFC3339Nano)
+	}},
+	entities.SortTypeUpdatedAt: {expr: "updated_at", cast: "timestamp", value: func(sub entities.Subscription) string {
+		return sub.UpdatedAt.Format(time.RFC3339Nano)
+	}},
+	entities.SortTypeRelevance: {expr: rankExpr, cast: "real", search: true, value: func(sub entities.Subscription) string {
+		return strconv.FormatFloat(float64(sub.Rank), 'g', -1, 32)
+	}},
+}
 
-		params.Page = offset/params.Limit + 1
-		params.Limit = limit
+// sortKeys - keys of the sort before id and the order of id, it ends every sort so rows never tie.
+// Without id in the sort it follows the order of the last key.
+func sortKeys(params []entities.SortParams) ([]entities.SortParams, entities.SortOrderType) {
+	order := entities.SortOrderTypeAsc
+	for i, key := range params {
+		if key.SortBy == entities.SortTypeID {
+			return params[:i], key.SortOrder
+		}
+		order = key.SortOrder
 	}
-	query = query.Limit(limit).Offset(offset)
 
-	return query
+	return params, order
 }
 
-// sortList - SelectBuilder query sort builder
-func sortList(query sq.SelectBuilder, params entities.SortParams) sq.SelectBuilder {
-	if params.SortBy == "" {
+// isDesc - descending sort order
+func isDesc(order entities.SortOrderType) bool {
+	return strings.EqualFold(string(order), string(entities.SortOrderTypeDesc))
+}
+
+// sortList - SelectBuilder query sort builder, rows with the same sort keys are ordered by id
+func sortList(query sq.SelectBuilder, params []entities.SortParams, search string) sq.SelectBuilder {
+	if len(params) == 0 {
 		return query
 	}
 
-	return query.OrderBy(fmt.Sprintf("%s %s", params.SortBy, params.SortOrder))
+	keys, idOrder := sortKeys(params)
+	for _, key := range keys {
+		column := sortColumns[key.SortBy]
+		query = query.OrderByClause(fmt.Sprintf("%s %s", column.expr, key.SortOrder), column.args(search)...)
+	}
+
+	return query.OrderBy(fmt.Sprintf("id %s", idOrder))
+}
+
+// keysetList - SelectBuilder query condition of the rows following the cursor in its sort order.
+// Keys of the same order are compared as a row, mixed orders are compared key by key.
+func keysetList(query sq.SelectBuilder, cursor entities.Cursor, search string) sq.SelectBuilder {
+	keys, idOrder := sortKeys(cursor.Sort)
+
+	after := func(order entities.SortOrderType) string {
+		if isDesc(order) {
+			return "<"
+		}
+		return ">"
+	}
+
+	sameOrder := true
+	for _, key := range keys {
+		sameOrder = sameOrder && isDesc(key.SortOrder) == isDesc(idOrder)
+	}
+
+	if sameOrder {
+		exprs := make([]string, 0, len(keys)+1)
+		values := make([]string, 0, len(keys)+1)
+		args := make([]any, 0, 2*len(keys)+1)
+		for _, key := range keys {
+			column := sortColumns[key.SortBy]
+			exprs = append(exprs, column.expr)
+			values = append(values, fmt.Sprintf("CAST(? AS %s)", column.cast))
+			args = append(args, column.args(search)...)
+		}
+		for _, value := range cursor.Values {
+			args = append(args, value)
+		}
+		args = append(args, cursor.ID)
+
+		if len(keys) == 0 {
+			return query.Where(fmt.Sprintf("id %s ?", after(idOrder)), args...)
+		}
+
+		return query.Where(fmt.Sprintf("(%s, id) %s (%s, ?)", strings.Join(exprs, ", "), after(idOrder), strings.Join(values, ", ")), args...)
+	}
+
+	var (
+		or    sq.Or
+		equal sq.And
+	)
+	for i, key := range keys {
+		column := sortColumns[key.SortBy]
+		next := append(sq.And{}, equal...)
+		args := append(column.args(search), cursor.Values[i])
+		next = append(next, sq.Expr(fmt.Sprintf("%s %s CAST(? AS %s)", column.expr, after(key.SortOrder), column.cast), args...))
+		or = append(or, next)
+		equal = append(equal, sq.Expr(fmt.Sprintf("%s = CAST(? AS %s)", column.expr, column.cast), args...))
+	}
+	or = append(or, append(equal, sq.Expr(fmt.Sprintf("id %s ?", after(idOrder)), cursor.ID)))
+
+	return query.Where(or)
+}
+
+// cursorList - cursor pointing at the row in the sort order
+func cursorList(sub entities.Subscription, params []entities.SortParams) *entities.Cursor {
+	if len(params) == 0 {
+		params = []entities.SortParams{{SortBy: entities.SortTypeID, SortOrder: entities.SortOrderTypeAsc}}
+	}
+
+	keys, _ := sortKeys(params)
+	cursor := &entities.Cursor{Sort: params, Values: make([]string, 0, len(keys)), ID: sub.ID}
+	for _, key := range keys {
+		cursor.Values = append(cursor.Values, sortColumns[key.SortBy].value(sub))
+	}
+
+	return cursor
 }
 
 // conditionCost - SelectBuilder query condition builder for cost
 func conditionCost(query sq.SelectBuilder, params entities.FilterParams) sq.SelectBuilder {
 	if params.ServiceName != "" {
-		query = query.Where(sq.Eq{"service_name": params.ServiceName})
+		query = query.Where(sq.Eq{"s.service_name_normalized": entities.NormalizeServiceName(params.ServiceName)})
 	}
 
-	if params.UserId != uuid.Nil{
-		query = query.Where(sq.Eq{"user_id": params.UserId})
+	// the user is one of the payers joined by joinCostPayers
+	if params.UserId != uuid.Nil {
+		query = query.Where(sq.Eq{"p.user_id": params.UserId})
 	}
 
-	if params.StartDate.From != nil {
-		query = query.Where(sq.GtOrEq{"start_date": params.StartDate.From})
+	if params.Period.From != nil {
+		query = query.Where(sq.Or{sq.Eq{"s.end_date": nil}, sq.GtOrEq{"s.end_date": params.Period.From}})
 	}
 
-	if params.StartDate.To != nil {
-		query = query.Where(sq.LtOrEq{"start_date": params.StartDate.To})
+	if params.Period.To != nil {
+		query = query.Where(sq.LtOrEq{"s.start_date": params.Period.To})
 	}
 
-	return query
-}
\ No newline at end of file
+	if params.Tag != "" {
+		query = query.Where(conditionTag("s.id", params.Tag))
+	}
+
+	if params.Category != "" {
+		query = query.Where(sq.Eq{"s.category": entities.NormalizeTag(params.Category)})
+	}
+
+	// months of the free trial are not billed, the month the trial ends in is
+	return query.Where("(s.trial_end_date IS NULL OR m.month >= date_trunc('month', s.trial_end_date::timestamp))")
+}
+
+// excludePausedMonths - SelectBuilder excludes months the subscription stayed paused for entirely
+func excludePausedMonths(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.Where(conditionNotPaused)
+}
+
+// excludeDeleted - SelectBuilder excludes soft-deleted rows unless they are requested
+func excludeDeleted(query sq.SelectBuilder, column string, includeDeleted bool) sq.SelectBuilder {
+	if includeDeleted {
+		return query
+	}
+
+	return query.Where(sq.Eq{column: nil})
+}
+
+// whereVersion - UpdateBuilder limits the update to the row version when it is set
+func whereVersion(query sq.UpdateBuilder, version int64) sq.UpdateBuilder {
+	if version <= 0 {
+		return query
+	}
+
+	return query.Where(sq.Eq{"version": version})
+}
+
+var conditionNotPaused = fmt.Sprintf("NOT EXISTS (SELECT 1 FROM subscription_status_history AS h"+
+	" WHERE h.subscription_id = s.id AND h.status = '%s' AND h.started_at <= m.month"+
+	" AND (h.ended_at IS NULL OR h.ended_at >= m.month + interval '1 month'))", entities.SubscriptionStatusPaused)
+
+// joinCostMonths - SelectBuilder joins one row per month billed within the period.
+// Subscriptions without end_date are billed up to the end of period or the current month.
+func joinCostMonths(query sq.SelectBuilder, params entities.DateRange) sq.SelectBuilder {
+	var args []any
+
+	from := "date_trunc('month', s.start_date::timestamp)"
+	if params.From != nil {
+		from = fmt.Sprintf("GREATEST(%s, date_trunc('month', ?::timestamp))", from)
+		args = append(args, params.From)
+	}
+
+	to := "date_trunc('month', CURRENT_DATE::timestamp)"
+	if params.To != nil {
+		to = "date_trunc('month', ?::timestamp)"
+	}
+	to = fmt.Sprintf("LEAST(date_trunc('month', COALESCE(s.end_date::timestamp, %s)), %s)", to, to)
+	if params.To != nil {
+		args = append(args, params.To, params.To)
+	}
+
+	return query.JoinClause(
+		fmt.Sprintf("CROSS JOIN LATERAL generate_series(%s, %s, interval '1 month') AS m(month)", from, to),
+		args...,
+	)
+}
+
+// joinCostPrice - SelectBuilder joins the price effective in the billed month.
+// Subscriptions without price history are billed by the current price.
+func joinCostPrice(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.JoinClause("LEFT JOIN LATERAL (SELECT ph.price FROM subscription_price_history AS ph" +
+		" WHERE ph.subscription_id = s.id AND ph.effective_from <= m.month" +
+		" ORDER BY ph.effective_from DESC LIMIT 1) AS p ON true")
+}
+
+// memberShares - totals of the fixed and percent shares of the subscription members
+const memberShares = "CROSS JOIN LATERAL (SELECT COALESCE(SUM(sm.share) FILTER (WHERE sm.share_type = 'fixed'), 0) AS fixed," +
+	" COALESCE(SUM(sm.share) FILTER (WHERE sm.share_type = 'percent'), 0) AS percent" +
+	" FROM subscription_members AS sm WHERE sm.subscription_id = s.id) AS st"
+
+// memberPrice - part of the month price paid by the member.
+// Fixed shares are capped together at the price, percent shares at the rest of the price left by the fixed shares.
+const memberPrice = "CASE sm.share_type WHEN 'fixed' THEN sm.share * LEAST(1, mp.price / st.fixed)" +
+	" ELSE sm.share / st.percent * LEAST(mp.price * st.percent / 100, mp.price - LEAST(st.fixed, mp.price)) END"
+
+// ownerPrice - rest of the month price not covered by the shares of the members
+const ownerPrice = "GREATEST(mp.price - st.fixed - mp.price * st.percent / 100, 0)"
+
+// joinCostPayers - SelectBuilder joins one row per user paying for the subscription with the part of the price effective in the billed month.
+// The user of the subscription pays the rest not covered by the members, subscription without members is paid by its user entirely.
+func joinCostPayers(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.
+		JoinClause("CROSS JOIN LATERAL (SELECT COALESCE((SELECT ph.price FROM subscription_price_history AS ph" +
+			" WHERE ph.subscription_id = s.id AND ph.effective_from <= m.month" +
+			" ORDER BY ph.effective_from DESC LIMIT 1), s.price) AS price) AS mp").
+		JoinClause(memberShares).
+		JoinClause("CROSS JOIN LATERAL (SELECT sh.user_id, SUM(sh.price) AS price FROM (SELECT sm.user_id, " + memberPrice + " AS price" +
+			" FROM subscription_members AS sm WHERE sm.subscription_id = s.id" +
+			" UNION ALL SELECT s.user_id, " + ownerPrice + " WHERE " + ownerPrice + " > 0 OR st.fixed + st.percent = 0) AS sh" +
+			" GROUP BY sh.user_id) AS p")
+}
+
+// joinCost - SelectBuilder joins the billed months and the price of every month.
+// Cost per user attributes to the user only the share of the shared subscriptions.
+func joinCost(query sq.SelectBuilder, period entities.DateRange, perUser bool) sq.SelectBuilder {
+	query = joinCostMonths(query, period)
+	if !perUser {
+		return joinCostPrice(query)
+	}
+
+	return joinCostPayers(query)
+}
+
+// costGroupColumn - grouping expression of cost, alias names the CostGroup field of an expression that is not a column
+type costGroupColumn struct {
+	expr  string
+	alias string
+}
+
+var costGroupColumns = map[entities.CostGroupType]costGroupColumn{
+	entities.CostGroupTypeServiceName: {expr: "s.service_name"},
+	entities.CostGroupTypeUserID:      {expr: "p.user_id"},
+	entities.CostGroupTypeCategory:    {expr: "s.category"},
+	entities.CostGroupTypeTag:         {expr: "COALESCE(t.name, '')", alias: "tag"},
+}
+
+// groupCost - SelectBuilder adds grouping columns for cost, the most expensive groups go first.
+// Subscriptions without tags are grouped under the empty tag, every group is split by currency.
+func groupCost(query sq.SelectBuilder, groupBy []entities.CostGroupType) sq.SelectBuilder {
+	columns := make([]string, 0, len(groupBy)+1)
+	selected := make([]string, 0, len(groupBy)+1)
+	for _, group := range groupBy {
+		column, ok := costGroupColumns[group]
+		if !ok {
+			continue
+		}
+
+		if group == entities.CostGroupTypeTag {
+			query = joinCostTags(query)
+		}
+
+		columns = append(columns, column.expr)
+		if column.alias != "" {
+			selected = append(selected, column.expr+" AS "+column.alias)
+		} else {
+			selected = append(selected, column.expr)
+		}
+	}
+	columns = append(columns, "s.currency")
+	selected = append(selected, "s.currency")
+
+	return query.Columns(selected...).
+		GroupBy(columns...).
+		OrderBy(append([]string{"cost DESC"}, columns...)...)
+}
+
+// joinCostTags - SelectBuilder joins one row per tag of the subscription, subscriptions without tags are kept
+func joinCostTags(query sq.SelectBuilder) sq.SelectBuilder {
+	return query.JoinClause("LEFT JOIN subscription_tags AS st ON st.subscription_id = s.id" +
+		" LEFT JOIN tags AS t ON t.id = st.tag_id")
+}
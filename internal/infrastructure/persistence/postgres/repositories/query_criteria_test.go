@@ -15,7 +15,7 @@ import (
 
 var builder sq.StatementBuilderType
 
-func init (){
+func init() {
 	builder = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 }
 
@@ -34,71 +34,113 @@ func TestQueryCriteria_ConditionList(t *testing.T) {
 
 	sql, _, _ := build.ToSql()
 
-	assert.Equal(t, "SELECT * FROM test WHERE service_name LIKE $1 AND user_id = $2 AND start_date >= $3 AND start_date <= $4", sql)
+	assert.Equal(t, "SELECT * FROM test WHERE service_name_normalized = $1 AND user_id = $2 AND start_date >= $3 AND start_date <= $4", sql)
+}
+
+func TestQueryCriteria_ConditionListRich(t *testing.T) {
+	build := builder.Select("*").From("test")
+
+	activeAt := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	endFrom := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	endTo := time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)
+	priceMin, priceMax := uint32(100), uint32(500)
+	userIds := []uuid.UUID{uuid.New(), uuid.New()}
+
+	filter := entities.FilterParams{
+		ServiceNames:        []string{"Netflix", "Spotify  Premium"},
+		UserIds:             userIds,
+		ServiceNamePrefix:   "net",
+		ServiceNameContains: "50%_off",
+		Price:               entities.PriceRange{Min: &priceMin, Max: &priceMax},
+		EndDate:             entities.DateRange{From: &endFrom, To: &endTo},
+		ActiveAt:            &activeAt,
+	}
+	build = conditionList(build, filter)
+
+	sql, args, err := build.ToSql()
+
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM test WHERE service_name_normalized IN ($1,$2) AND user_id IN ($3,$4) AND service_name ILIKE $5 AND service_name ILIKE $6 "+
+		"AND price >= $7 AND price <= $8 AND end_date >= $9 AND end_date <= $10 "+
+		"AND (start_date <= $11 AND (end_date IS NULL OR end_date >= $12))", sql)
+	assert.Equal(t, []any{"netflix", "spotify premium", userIds[0], userIds[1], "net%", `%50\%\_off%`, priceMin, priceMax, &endFrom, &endTo, &activeAt, &activeAt}, args)
+}
+
+func TestQueryCriteria_ConditionListNoEndDate(t *testing.T) {
+	build := conditionList(builder.Select("*").From("test"), entities.FilterParams{NoEndDate: true})
+
+	sql, _, err := build.ToSql()
+
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM test WHERE end_date IS NULL", sql)
+}
+
+func TestQueryCriteria_ConditionListSearch(t *testing.T) {
+	build := conditionList(builder.Select("*").From("test"), entities.FilterParams{Search: " YouTube  50%"})
+
+	sql, args, err := build.ToSql()
+
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM test WHERE (service_name_normalized LIKE $1 OR $2 <% service_name_normalized)", sql)
+	assert.Equal(t, []any{`%youtube 50\%%`, "youtube 50%"}, args)
+}
+
+func TestQueryCriteria_ConditionListTagCategory(t *testing.T) {
+	build := conditionList(builder.Select("*").From("test"), entities.FilterParams{Tag: "Dev Tools", Category: " Streaming"})
+
+	sql, args, err := build.ToSql()
+
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM test WHERE EXISTS (SELECT 1 FROM subscription_tags AS st JOIN tags AS t ON t.id = st.tag_id "+
+		"WHERE st.subscription_id = subscription.id AND t.name = $1) AND category = $2", sql)
+	assert.Equal(t, []any{"dev tools", "streaming"}, args)
+}
+
+func TestQueryCriteria_RankList(t *testing.T) {
+	sql, args, err := rankList(builder.Select("id").From("test"), "Netf").ToSql()
+
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id, word_similarity($1, service_name_normalized) AS rank FROM test", sql)
+	assert.Equal(t, []any{"netf"}, args)
+
+	sql, _, err = rankList(builder.Select("id").From("test"), "").ToSql()
+
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM test", sql)
 }
 
 func TestQueryCriteria_PaginationList(t *testing.T) {
 	tests := []struct {
-		name          string
-		expectedSql   string
-		expectedPage  uint64
-		expectedLimit uint64
-		totalCount    uint64
-		params        *entities.PaginationParams
+		name        string
+		expectedSql string
+		params      entities.PaginationParams
 	}{
 		{
-			name:          "first_page_no_full",
-			expectedSql:   "SELECT * FROM test LIMIT 13 OFFSET 0",
-			expectedPage:  1,
-			expectedLimit: 13,
-			totalCount:    13,
-			params:        &entities.PaginationParams{Page: 1, Limit: 20},
-		},
-		{
-			name:          "page_gte_total",
-			expectedSql:   "SELECT * FROM test LIMIT 13 OFFSET 0",
-			expectedPage:  1,
-			expectedLimit: 13,
-			totalCount:    13,
-			params:        &entities.PaginationParams{Page: 8, Limit: 20},
-		},
-		{
-			name:          "first_page_full",
-			expectedSql:   "SELECT * FROM test LIMIT 20 OFFSET 0",
-			expectedPage:  1,
-			expectedLimit: 20,
-			totalCount:    23,
-			params:        &entities.PaginationParams{Page: 1, Limit: 20},
+			name:        "first_page",
+			expectedSql: "SELECT * FROM test LIMIT 20 OFFSET 0",
+			params:      entities.PaginationParams{Page: 1, Limit: 20},
 		},
 		{
-			name:          "page_middle_total",
-			expectedSql:   "SELECT * FROM test LIMIT 20 OFFSET 20",
-			expectedPage:  2,
-			expectedLimit: 20,
-			totalCount:    68,
-			params:        &entities.PaginationParams{Page: 2, Limit: 20},
+			name:        "page_middle",
+			expectedSql: "SELECT * FROM test LIMIT 20 OFFSET 20",
+			params:      entities.PaginationParams{Page: 2, Limit: 20},
 		},
 		{
-			name:          "page_eq_total",
-			expectedSql:   "SELECT * FROM test LIMIT 20 OFFSET 40",
-			expectedPage:  3,
-			expectedLimit: 20,
-			totalCount:    60,
-			params:        &entities.PaginationParams{Page: 3, Limit: 20},
+			name:        "page_past_end",
+			expectedSql: "SELECT * FROM test LIMIT 20 OFFSET 140",
+			params:      entities.PaginationParams{Page: 8, Limit: 20},
 		},
 	}
 
 	query := builder.Select("*").From("test")
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tmpQuery := paginationList(query, tt.totalCount, tt.params)
+			tmpQuery := paginationList(query, tt.params)
 
 			sql, _, err := tmpQuery.ToSql()
 
 			require.NoError(t, err)
 			assert.Equal(t, tt.expectedSql, sql)
-			assert.Equal(t, tt.expectedPage, tt.params.Page)
-			assert.Equal(t, tt.expectedLimit, tt.params.Limit)
 		})
 	}
 }
@@ -106,9 +148,7 @@ func TestQueryCriteria_PaginationList(t *testing.T) {
 func TestQueryCriteria_SortListEmpty(t *testing.T) {
 	build := builder.Select("*").From("test")
 
-	params := entities.SortParams{}
-
-	build = sortList(build, params)
+	build = sortList(build, nil, "")
 	sql, _, err := build.ToSql()
 
 	require.NoError(t, err)
@@ -118,17 +158,181 @@ func TestQueryCriteria_SortListEmpty(t *testing.T) {
 func TestQueryCriteria_SortList(t *testing.T) {
 	build := builder.Select("*").From("test")
 
-	params := entities.SortParams{
+	params := []entities.SortParams{{
 		SortBy:    entities.SortType(entities.SortTypeID),
 		SortOrder: entities.SortOrderTypeDesc,
+	}}
+
+	build = sortList(build, params, "")
+
+	sql, _, err := build.ToSql()
+
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("SELECT * FROM test ORDER BY %s %s", params[0].SortBy, params[0].SortOrder), sql)
+}
+
+func TestQueryCriteria_SortListTiebreaker(t *testing.T) {
+	build := builder.Select("*").From("test")
+
+	params := []entities.SortParams{{
+		SortBy:    entities.SortTypeServiceName,
+		SortOrder: entities.SortOrderTypeDesc,
+	}}
+
+	build = sortList(build, params, "")
+
+	sql, _, err := build.ToSql()
+
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM test ORDER BY service_name DESC, id DESC", sql)
+}
+
+func TestQueryCriteria_SortListMultiKey(t *testing.T) {
+	build := builder.Select("*").From("test")
+
+	params := []entities.SortParams{
+		{SortBy: entities.SortTypePrice, SortOrder: entities.SortOrderTypeDesc},
+		{SortBy: entities.SortTypeEndDate, SortOrder: entities.SortOrderTypeAsc},
+	}
+
+	build = sortList(build, params, "")
+
+	sql, _, err := build.ToSql()
+
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM test ORDER BY price DESC, COALESCE(end_date, 'infinity'::date) ASC, id ASC", sql)
+}
+
+func TestQueryCriteria_SortListExplicitID(t *testing.T) {
+	build := builder.Select("*").From("test")
+
+	params := []entities.SortParams{
+		{SortBy: entities.SortTypePrice, SortOrder: entities.SortOrderTypeAsc},
+		{SortBy: entities.SortTypeID, SortOrder: entities.SortOrderTypeDesc},
+		{SortBy: entities.SortTypeStartDate, SortOrder: entities.SortOrderTypeAsc},
 	}
 
-	build = sortList(build, params)
+	build = sortList(build, params, "")
 
 	sql, _, err := build.ToSql()
 
 	require.NoError(t, err)
-	assert.Equal(t, fmt.Sprintf("SELECT * FROM test ORDER BY %s %s", params.SortBy, params.SortOrder), sql)
+	assert.Equal(t, "SELECT * FROM test ORDER BY price ASC, id DESC", sql)
+}
+
+func TestQueryCriteria_SortListRelevance(t *testing.T) {
+	params := []entities.SortParams{{SortBy: entities.SortTypeRelevance, SortOrder: entities.SortOrderTypeDesc}}
+
+	sql, args, err := sortList(builder.Select("*").From("test"), params, "Netf").ToSql()
+
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM test ORDER BY word_similarity($1, service_name_normalized) DESC, id DESC", sql)
+	assert.Equal(t, []any{"netf"}, args)
+}
+
+func TestQueryCriteria_KeysetList(t *testing.T) {
+	tests := []struct {
+		name         string
+		cursor       entities.Cursor
+		search       string
+		expectedSql  string
+		expectedArgs []any
+	}{
+		{
+			name:         "id_asc",
+			cursor:       entities.Cursor{Sort: []entities.SortParams{{SortBy: entities.SortTypeID, SortOrder: entities.SortOrderTypeAsc}}, ID: 7},
+			expectedSql:  "SELECT * FROM test WHERE id > $1",
+			expectedArgs: []any{int64(7)},
+		},
+		{
+			name:         "id_desc",
+			cursor:       entities.Cursor{Sort: []entities.SortParams{{SortBy: entities.SortTypeID, SortOrder: "desc"}}, ID: 7},
+			expectedSql:  "SELECT * FROM test WHERE id < $1",
+			expectedArgs: []any{int64(7)},
+		},
+		{
+			name:         "service_name_asc",
+			cursor:       entities.Cursor{Sort: []entities.SortParams{{SortBy: entities.SortTypeServiceName, SortOrder: entities.SortOrderTypeAsc}}, Values: []string{"Netflix"}, ID: 7},
+			expectedSql:  "SELECT * FROM test WHERE (service_name, id) > (CAST($1 AS text), $2)",
+			expectedArgs: []any{"Netflix", int64(7)},
+		},
+		{
+			name: "same_order_desc",
+			cursor: entities.Cursor{
+				Sort: []entities.SortParams{
+					{SortBy: entities.SortTypePrice, SortOrder: entities.SortOrderTypeDesc},
+					{SortBy: entities.SortTypeEndDate, SortOrder: entities.SortOrderTypeDesc},
+				},
+				Values: []string{"400", "infinity"},
+				ID:     7,
+			},
+			expectedSql:  "SELECT * FROM test WHERE (price, COALESCE(end_date, 'infinity'::date), id) < (CAST($1 AS bigint), CAST($2 AS date), $3)",
+			expectedArgs: []any{"400", "infinity", int64(7)},
+		},
+		{
+			name: "mixed_order",
+			cursor: entities.Cursor{
+				Sort: []entities.SortParams{
+					{SortBy: entities.SortTypePrice, SortOrder: entities.SortOrderTypeDesc},
+					{SortBy: entities.SortTypeStartDate, SortOrder: entities.SortOrderTypeAsc},
+				},
+				Values: []string{"400", "2025-01-01"},
+				ID:     7,
+			},
+			expectedSql: "SELECT * FROM test WHERE ((price < CAST($1 AS bigint)) OR (price = CAST($2 AS bigint) AND start_date > CAST($3 AS date))" +
+				" OR (price = CAST($4 AS bigint) AND start_date = CAST($5 AS date) AND id > $6))",
+			expectedArgs: []any{"400", "400", "2025-01-01", "400", "2025-01-01", int64(7)},
+		},
+		{
+			name: "relevance",
+			cursor: entities.Cursor{
+				Sort:   []entities.SortParams{{SortBy: entities.SortTypeRelevance, SortOrder: entities.SortOrderTypeDesc}},
+				Values: []string{"0.5"},
+				ID:     7,
+			},
+			search:       "Netf",
+			expectedSql:  "SELECT * FROM test WHERE (word_similarity($1, service_name_normalized), id) < (CAST($2 AS real), $3)",
+			expectedArgs: []any{"netf", "0.5", int64(7)},
+		},
+		{
+			name: "relevance_mixed_order",
+			cursor: entities.Cursor{
+				Sort: []entities.SortParams{
+					{SortBy: entities.SortTypeRelevance, SortOrder: entities.SortOrderTypeDesc},
+					{SortBy: entities.SortTypeID, SortOrder: entities.SortOrderTypeAsc},
+				},
+				Values: []string{"0.5"},
+				ID:     7,
+			},
+			search: "Netf",
+			expectedSql: "SELECT * FROM test WHERE ((word_similarity($1, service_name_normalized) < CAST($2 AS real))" +
+				" OR (word_similarity($3, service_name_normalized) = CAST($4 AS real) AND id > $5))",
+			expectedArgs: []any{"netf", "0.5", "netf", "0.5", int64(7)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := keysetList(builder.Select("*").From("test"), tt.cursor, tt.search).ToSql()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSql, sql)
+			assert.Equal(t, tt.expectedArgs, args)
+		})
+	}
+}
+
+func TestQueryCriteria_CursorList(t *testing.T) {
+	startDate := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	sub := entities.Subscription{ID: 7, ServiceName: "Netflix", Price: 400, StartDate: startDate}
+	sort := []entities.SortParams{
+		{SortBy: entities.SortTypePrice, SortOrder: entities.SortOrderTypeDesc},
+		{SortBy: entities.SortTypeEndDate, SortOrder: entities.SortOrderTypeAsc},
+		{SortBy: entities.SortTypeStartDate, SortOrder: entities.SortOrderTypeAsc},
+	}
+
+	assert.Equal(t, &entities.Cursor{Sort: sort, Values: []string{"400", "infinity", "2025-01-01"}, ID: 7}, cursorList(sub, sort))
+	assert.Equal(t, &entities.Cursor{Sort: []entities.SortParams{{SortBy: entities.SortTypeID, SortOrder: entities.SortOrderTypeAsc}}, Values: []string{}, ID: 7}, cursorList(sub, nil))
 }
 
 func TestQueryCriteria_ConditionCost(t *testing.T) {
@@ -150,27 +354,33 @@ func TestQueryCriteria_ConditionCost(t *testing.T) {
 		{
 			name:           "empty",
 			fn:             func() {},
-			exepectedQuery: "SELECT * FROM test",
+			exepectedQuery: "SELECT * FROM test WHERE (s.trial_end_date IS NULL OR m.month >= date_trunc('month', s.trial_end_date::timestamp))",
 		},
 		{
 			name:           "WithServiceName",
-			fn:             func() { filter.ServiceName = serviceName},
-			exepectedQuery: "SELECT * FROM test WHERE service_name = $1",
+			fn:             func() { filter.ServiceName = serviceName },
+			exepectedQuery: "SELECT * FROM test WHERE s.service_name_normalized = $1 AND (s.trial_end_date IS NULL OR m.month >= date_trunc('month', s.trial_end_date::timestamp))",
 		},
 		{
 			name:           "WithServiceNameUserId",
 			fn:             func() { filter.UserId = userId },
-			exepectedQuery: "SELECT * FROM test WHERE service_name = $1 AND user_id = $2",
+			exepectedQuery: "SELECT * FROM test WHERE s.service_name_normalized = $1 AND p.user_id = $2 AND (s.trial_end_date IS NULL OR m.month >= date_trunc('month', s.trial_end_date::timestamp))",
+		},
+		{
+			name:           "WithServiceNameUserIdPeriodFrom",
+			fn:             func() { filter.Period = startDate },
+			exepectedQuery: "SELECT * FROM test WHERE s.service_name_normalized = $1 AND p.user_id = $2 AND (s.end_date IS NULL OR s.end_date >= $3) AND (s.trial_end_date IS NULL OR m.month >= date_trunc('month', s.trial_end_date::timestamp))",
 		},
 		{
-			name: "WithServiceNameUserIdDateFrom",
-			fn:   func() { filter.StartDate = startDate },
-			exepectedQuery: "SELECT * FROM test WHERE service_name = $1 AND user_id = $2 AND start_date >= $3",
+			name:           "WithServiceNameUserIdPeriodFromPeriodTo",
+			fn:             func() { filter.Period = fullDate },
+			exepectedQuery: "SELECT * FROM test WHERE s.service_name_normalized = $1 AND p.user_id = $2 AND (s.end_date IS NULL OR s.end_date >= $3) AND s.start_date <= $4 AND (s.trial_end_date IS NULL OR m.month >= date_trunc('month', s.trial_end_date::timestamp))",
 		},
 		{
-			name: "WithServiceNameUserIdDateFromDateTo",
-			fn:   func() { filter.StartDate = fullDate },
-			exepectedQuery: "SELECT * FROM test WHERE service_name = $1 AND user_id = $2 AND start_date >= $3 AND start_date <= $4",
+			name: "WithTagCategory",
+			fn:   func() { filter = entities.FilterParams{Tag: "cloud", Category: "dev tools"} },
+			exepectedQuery: "SELECT * FROM test WHERE EXISTS (SELECT 1 FROM subscription_tags AS st JOIN tags AS t ON t.id = st.tag_id WHERE st.subscription_id = s.id AND t.name = $1) " +
+				"AND s.category = $2 AND (s.trial_end_date IS NULL OR m.month >= date_trunc('month', s.trial_end_date::timestamp))",
 		},
 	}
 
@@ -185,3 +395,182 @@ func TestQueryCriteria_ConditionCost(t *testing.T) {
 		})
 	}
 }
+
+func TestQueryCriteria_JoinCostMonths(t *testing.T) {
+	from := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2020, time.December, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		period        entities.DateRange
+		expectedQuery string
+		expectedArgs  []any
+	}{
+		{
+			name:          "empty",
+			period:        entities.DateRange{},
+			expectedQuery: "SELECT * FROM test CROSS JOIN LATERAL generate_series(date_trunc('month', s.start_date::timestamp), LEAST(date_trunc('month', COALESCE(s.end_date::timestamp, date_trunc('month', CURRENT_DATE::timestamp))), date_trunc('month', CURRENT_DATE::timestamp)), interval '1 month') AS m(month)",
+		},
+		{
+			name:          "WithFrom",
+			period:        entities.DateRange{From: &from},
+			expectedQuery: "SELECT * FROM test CROSS JOIN LATERAL generate_series(GREATEST(date_trunc('month', s.start_date::timestamp), date_trunc('month', $1::timestamp)), LEAST(date_trunc('month', COALESCE(s.end_date::timestamp, date_trunc('month', CURRENT_DATE::timestamp))), date_trunc('month', CURRENT_DATE::timestamp)), interval '1 month') AS m(month)",
+			expectedArgs:  []any{&from},
+		},
+		{
+			name:          "WithFromTo",
+			period:        entities.DateRange{From: &from, To: &to},
+			expectedQuery: "SELECT * FROM test CROSS JOIN LATERAL generate_series(GREATEST(date_trunc('month', s.start_date::timestamp), date_trunc('month', $1::timestamp)), LEAST(date_trunc('month', COALESCE(s.end_date::timestamp, date_trunc('month', $2::timestamp))), date_trunc('month', $3::timestamp)), interval '1 month') AS m(month)",
+			expectedArgs:  []any{&from, &to, &to},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			build := builder.Select("*").From("test")
+			build = joinCostMonths(build, tt.period)
+			sql, args, err := build.ToSql()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedQuery, sql)
+			assert.Equal(t, len(tt.expectedArgs), len(args))
+			for i := range tt.expectedArgs {
+				assert.Equal(t, tt.expectedArgs[i], args[i])
+			}
+		})
+	}
+}
+
+func TestQueryCriteria_JoinCostPrice(t *testing.T) {
+	build := builder.Select("*").From("test")
+	build = joinCostPrice(build)
+	sql, args, err := build.ToSql()
+
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM test LEFT JOIN LATERAL (SELECT ph.price FROM subscription_price_history AS ph "+
+		"WHERE ph.subscription_id = s.id AND ph.effective_from <= m.month ORDER BY ph.effective_from DESC LIMIT 1) AS p ON true", sql)
+	assert.Empty(t, args)
+}
+
+func TestQueryCriteria_ExcludePausedMonths(t *testing.T) {
+	build := builder.Select("*").From("test")
+	build = excludePausedMonths(build)
+	sql, args, err := build.ToSql()
+
+	require.NoError(t, err)
+	// the month is excluded only when the pause covers it from its first day to the first day of the next month
+	assert.Equal(t, "SELECT * FROM test WHERE NOT EXISTS (SELECT 1 FROM subscription_status_history AS h "+
+		"WHERE h.subscription_id = s.id AND h.status = 'paused' AND h.started_at <= m.month "+
+		"AND (h.ended_at IS NULL OR h.ended_at >= m.month + interval '1 month'))", sql)
+	assert.Empty(t, args)
+}
+
+func TestQueryCriteria_JoinCost(t *testing.T) {
+	months := "CROSS JOIN LATERAL generate_series(date_trunc('month', s.start_date::timestamp), " +
+		"LEAST(date_trunc('month', COALESCE(s.end_date::timestamp, date_trunc('month', CURRENT_DATE::timestamp))), date_trunc('month', CURRENT_DATE::timestamp)), " +
+		"interval '1 month') AS m(month)"
+
+	tests := []struct {
+		name          string
+		perUser       bool
+		expectedQuery string
+	}{
+		{
+			name: "Subscription",
+			expectedQuery: "SELECT * FROM test " + months + " LEFT JOIN LATERAL (SELECT ph.price FROM subscription_price_history AS ph " +
+				"WHERE ph.subscription_id = s.id AND ph.effective_from <= m.month ORDER BY ph.effective_from DESC LIMIT 1) AS p ON true",
+		},
+		{
+			name:    "PerUser",
+			perUser: true,
+			expectedQuery: "SELECT * FROM test " + months + " CROSS JOIN LATERAL (SELECT COALESCE((SELECT ph.price FROM subscription_price_history AS ph " +
+				"WHERE ph.subscription_id = s.id AND ph.effective_from <= m.month ORDER BY ph.effective_from DESC LIMIT 1), s.price) AS price) AS mp " +
+				"CROSS JOIN LATERAL (SELECT COALESCE(SUM(sm.share) FILTER (WHERE sm.share_type = 'fixed'), 0) AS fixed, " +
+				"COALESCE(SUM(sm.share) FILTER (WHERE sm.share_type = 'percent'), 0) AS percent " +
+				"FROM subscription_members AS sm WHERE sm.subscription_id = s.id) AS st " +
+				"CROSS JOIN LATERAL (SELECT sh.user_id, SUM(sh.price) AS price FROM (SELECT sm.user_id, " +
+				"CASE sm.share_type WHEN 'fixed' THEN sm.share * LEAST(1, mp.price / st.fixed) " +
+				"ELSE sm.share / st.percent * LEAST(mp.price * st.percent / 100, mp.price - LEAST(st.fixed, mp.price)) END AS price " +
+				"FROM subscription_members AS sm WHERE sm.subscription_id = s.id " +
+				"UNION ALL SELECT s.user_id, GREATEST(mp.price - st.fixed - mp.price * st.percent / 100, 0) " +
+				"WHERE GREATEST(mp.price - st.fixed - mp.price * st.percent / 100, 0) > 0 OR st.fixed + st.percent = 0) AS sh " +
+				"GROUP BY sh.user_id) AS p",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			build := builder.Select("*").From("test")
+			build = joinCost(build, entities.DateRange{}, tt.perUser)
+			sql, args, err := build.ToSql()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedQuery, sql)
+			assert.Empty(t, args)
+		})
+	}
+}
+
+func TestQueryCriteria_GroupCost(t *testing.T) {
+	tests := []struct {
+		name          string
+		groupBy       []entities.CostGroupType
+		expectedQuery string
+	}{
+		{
+			name:          "ServiceName",
+			groupBy:       []entities.CostGroupType{entities.CostGroupTypeServiceName},
+			expectedQuery: "SELECT SUM(s.price) AS cost, s.service_name, s.currency FROM test GROUP BY s.service_name, s.currency ORDER BY cost DESC, s.service_name, s.currency",
+		},
+		{
+			name:          "UserId",
+			groupBy:       []entities.CostGroupType{entities.CostGroupTypeUserID},
+			expectedQuery: "SELECT SUM(s.price) AS cost, p.user_id, s.currency FROM test GROUP BY p.user_id, s.currency ORDER BY cost DESC, p.user_id, s.currency",
+		},
+		{
+			name:          "ServiceNameUserId",
+			groupBy:       []entities.CostGroupType{entities.CostGroupTypeServiceName, entities.CostGroupTypeUserID},
+			expectedQuery: "SELECT SUM(s.price) AS cost, s.service_name, p.user_id, s.currency FROM test GROUP BY s.service_name, p.user_id, s.currency ORDER BY cost DESC, s.service_name, p.user_id, s.currency",
+		},
+		{
+			name:          "Category",
+			groupBy:       []entities.CostGroupType{entities.CostGroupTypeCategory},
+			expectedQuery: "SELECT SUM(s.price) AS cost, s.category, s.currency FROM test GROUP BY s.category, s.currency ORDER BY cost DESC, s.category, s.currency",
+		},
+		{
+			name:    "TagCategory",
+			groupBy: []entities.CostGroupType{entities.CostGroupTypeTag, entities.CostGroupTypeCategory},
+			expectedQuery: "SELECT SUM(s.price) AS cost, COALESCE(t.name, '') AS tag, s.category, s.currency FROM test " +
+				"LEFT JOIN subscription_tags AS st ON st.subscription_id = s.id LEFT JOIN tags AS t ON t.id = st.tag_id " +
+				"GROUP BY COALESCE(t.name, ''), s.category, s.currency ORDER BY cost DESC, COALESCE(t.name, ''), s.category, s.currency",
+		},
+		{
+			name:          "Unknown",
+			groupBy:       []entities.CostGroupType{"unknown", entities.CostGroupTypeUserID},
+			expectedQuery: "SELECT SUM(s.price) AS cost, p.user_id, s.currency FROM test GROUP BY p.user_id, s.currency ORDER BY cost DESC, p.user_id, s.currency",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			build := builder.Select("SUM(s.price) AS cost").From("test")
+			build = groupCost(build, tt.groupBy)
+			sql, _, err := build.ToSql()
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedQuery, sql)
+		})
+	}
+}
+
+func TestQueryCriteria_ExcludeDeleted(t *testing.T) {
+	build := builder.Select("*").From("test")
+
+	sql, _, err := excludeDeleted(build, "s.deleted_at", false).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM test WHERE s.deleted_at IS NULL", sql)
+
+	sql, _, err = excludeDeleted(build, "s.deleted_at", true).ToSql()
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM test", sql)
+}
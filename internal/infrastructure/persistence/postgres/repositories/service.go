@@ -0,0 +1,259 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	errs "github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/interfaces/repositories"
+)
+
+type serviceRepository struct {
+	querier sqlx.ExtContext
+	builder sq.StatementBuilderType
+
+	logger observability.Logger
+}
+
+// NewServiceRepository - Constructor ServiceRepository
+func NewServiceRepository(querier sqlx.ExtContext, builder sq.StatementBuilderType, logger observability.Logger) repositories.ServiceRepository {
+	return &serviceRepository{
+		querier: querier,
+		builder: builder,
+
+		logger: logger,
+	}
+}
+
+var (
+	tableService   = "services"
+	columnsService = []string{"id", "name", "category", "vendor_url", "default_price", "currency", "created_at", "updated_at"}
+)
+
+// conflictService - names differing only in case and spaces are the same service
+const conflictService = "ON CONFLICT (name_normalized) DO NOTHING"
+
+// conn - returns the transaction carried by the context or the repository querier
+func (r *serviceRepository) conn(ctx context.Context) sqlx.ExtContext {
+	return querierFromContext(ctx, r.querier)
+}
+
+// Create - create new row, ErrAlreadyExists is returned when the service with the same normalized name exists
+func (r *serviceRepository) Create(ctx context.Context, service entities.Service) (*entities.Service, error) {
+	data := map[string]any{
+		"name":       service.Name,
+		"category":   service.Category,
+		"vendor_url": service.VendorURL,
+	}
+	if service.DefaultPrice.Valid {
+		data["default_price"] = service.DefaultPrice.Int64
+	}
+	if service.Currency != "" {
+		data["currency"] = service.Currency
+	}
+
+	query, args, err := r.builder.Insert(tableService).
+		SetMap(data).
+		Suffix(conflictService + " RETURNING " + strings.Join(columnsService, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, errs.Wrap(err, "serviceRepositories.Create: build query")
+	}
+
+	created := &entities.Service{}
+	err = r.conn(ctx).QueryRowxContext(ctx, query, args...).StructScan(created)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.Wrap(errs.ErrAlreadyExists, "serviceRepositories.Create")
+		}
+		return nil, errs.Wrap(err, "serviceRepositories.Create: exec query")
+	}
+
+	return created, nil
+}
+
+// GetByID - Returns service by ID
+func (r *serviceRepository) GetByID(ctx context.Context, id int64) (*entities.Service, error) {
+	query, args, err := r.builder.Select(columnsService...).
+		From(tableService).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, errs.Wrap(err, "serviceRepositories.GetByID: build query")
+	}
+
+	service := &entities.Service{}
+	err = r.conn(ctx).QueryRowxContext(ctx, query, args...).StructScan(service)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrNotFound
+		}
+		return nil, errs.Wrap(err, "serviceRepositories.GetByID: scan query")
+	}
+
+	return service, nil
+}
+
+// List - Returns services by filter ordered by name
+func (r *serviceRepository) List(ctx context.Context, filter entities.ServiceFilter) ([]entities.Service, error) {
+	query := r.builder.Select(columnsService...).From(tableService)
+	query = conditionService(query, filter)
+	query = query.OrderBy("name_normalized", "id")
+
+	if filter.Pagination.Limit > 0 {
+		query = query.Limit(filter.Pagination.Limit)
+		if filter.Pagination.Page > 1 {
+			query = query.Offset((filter.Pagination.Page - 1) * filter.Pagination.Limit)
+		}
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, errs.Wrap(err, "serviceRepositories.List: build query")
+	}
+
+	rows, err := r.conn(ctx).QueryxContext(ctx, sql, args...)
+	if err != nil {
+		return nil, errs.Wrap(err, "serviceRepositories.List: get query")
+	}
+	defer rows.Close()
+
+	services := make([]entities.Service, 0)
+	for rows.Next() {
+		var service entities.Service
+		err = rows.StructScan(&service)
+		if err != nil {
+			return nil, errs.Wrap(err, "serviceRepositories.List: scan query")
+		}
+		services = append(services, service)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errs.Wrap(err, "serviceRepositories.List: iteration rows")
+	}
+
+	return services, nil
+}
+
+// Update - Updated fields of service by ID.
+// ErrAlreadyExists is returned when the new name is the name of another service.
+func (r *serviceRepository) Update(ctx context.Context, id int64, fields map[string]any) error {
+	for key, value := range fields {
+		validator, ok := entities.ServiceUpdateFields[key]
+		if !ok || !validator(value) {
+			r.logger.Error("serviceRepositories.Update: validate fields", fields)
+
+			return fmt.Errorf("serviceRepositories.Update: invalid field %s", key)
+		}
+	}
+
+	fields["updated_at"] = time.Now().UTC()
+
+	build := r.builder.Update(tableService).
+		Where(sq.Eq{"id": id}).
+		SetMap(fields)
+	if name, ok := fields["name"].(string); ok {
+		build = build.Where("NOT EXISTS (SELECT 1 FROM services AS o WHERE o.name_normalized = ? AND o.id <> ?)",
+			entities.NormalizeServiceName(name), id)
+	}
+
+	query, args, err := build.ToSql()
+	if err != nil {
+		return errs.Wrap(err, "serviceRepositories.Update: build query")
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return errs.Wrap(err, "serviceRepositories.Update: exec query")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return errs.Wrap(err, "serviceRepositories.Update: get affected rows")
+	}
+
+	if rowsAffected == 0 {
+		return errs.Wrap(errs.ErrAlreadyExists, "serviceRepositories.Update: name taken")
+	}
+
+	return nil
+}
+
+// Delete - Deletes the row with the id, linked subscriptions keep their names without the link
+func (r *serviceRepository) Delete(ctx context.Context, id int64) error {
+	query, args, err := r.builder.Delete(tableService).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return errs.Wrap(err, "serviceRepositories.Delete: build query")
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return errs.Wrap(err, "serviceRepositories.Delete: exec query")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return errs.Wrap(err, "serviceRepositories.Delete: get affected rows")
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
+
+// LinkSubscriptions - Links subscriptions with the normalized name of the service to it
+// and gives the linked subscriptions the canonical name, returns the number of changed rows
+func (r *serviceRepository) LinkSubscriptions(ctx context.Context, service entities.Service) (int64, error) {
+	query, args, err := r.builder.Update(table).
+		Set("service_id", service.ID).
+		Set("service_name", service.Name).
+		Set("updated_at", time.Now().UTC()).
+		Set("version", versionNext).
+		Where(sq.Or{
+			sq.Eq{"service_id": service.ID},
+			sq.And{sq.Eq{"service_id": nil}, sq.Eq{"service_name_normalized": entities.NormalizeServiceName(service.Name)}},
+		}).
+		Where(sq.Or{sq.Eq{"service_id": nil}, sq.NotEq{"service_name": service.Name}}).
+		ToSql()
+	if err != nil {
+		return 0, errs.Wrap(err, "serviceRepositories.LinkSubscriptions: build query")
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, errs.Wrap(err, "serviceRepositories.LinkSubscriptions: exec query")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, errs.Wrap(err, "serviceRepositories.LinkSubscriptions: get affected rows")
+	}
+
+	return rowsAffected, nil
+}
+
+// conditionService - SelectBuilder query condition builder for the service catalog
+func conditionService(query sq.SelectBuilder, filter entities.ServiceFilter) sq.SelectBuilder {
+	if filter.Name != "" {
+		query = query.Where(sq.Like{"name_normalized": "%" + escapeLike(entities.NormalizeServiceName(filter.Name)) + "%"})
+	}
+
+	if filter.Category != "" {
+		query = query.Where(sq.Eq{"category": filter.Category})
+	}
+
+	return query
+}
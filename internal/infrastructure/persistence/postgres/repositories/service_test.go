@@ -0,0 +1,191 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/mocks"
+)
+
+var serviceTest = entities.Service{
+	ID:           1,
+	Name:         "Spotify",
+	Category:     "music",
+	VendorURL:    "https://spotify.com",
+	DefaultPrice: sql.NullInt64{Int64: 299, Valid: true},
+	Currency:     "RUB",
+}
+
+const serviceColumns = "id, name, category, vendor_url, default_price, currency, created_at, updated_at"
+
+func newServiceRepository(t *testing.T) (*serviceRepository, sqlmock.Sqlmock, *mocks.MockLogger) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	t.Cleanup(func() { mockDB.Close() })
+
+	ctrl := gomock.NewController(t)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewServiceRepository(sqlx.NewDb(mockDB, "sqlmock"), sq.StatementBuilder.PlaceholderFormat(sq.Dollar), logger)
+
+	return repo.(*serviceRepository), mock, logger
+}
+
+func TestService_Create_Success(t *testing.T) {
+	repo, mock, _ := newServiceRepository(t)
+	createdAt := time.Now().UTC()
+
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO services (category,currency,default_price,name,vendor_url) VALUES ($1,$2,$3,$4,$5) "+
+		"ON CONFLICT (name_normalized) DO NOTHING RETURNING "+serviceColumns)).
+		WithArgs(serviceTest.Category, serviceTest.Currency, serviceTest.DefaultPrice.Int64, serviceTest.Name, serviceTest.VendorURL).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at", "updated_at"}).
+			AddRow(1, serviceTest.Name, createdAt, createdAt))
+
+	created, err := repo.Create(context.Background(), serviceTest)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), created.ID)
+	assert.Equal(t, createdAt, created.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestService_Create_AlreadyExists(t *testing.T) {
+	repo, mock, _ := newServiceRepository(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO services (category,name,vendor_url) VALUES ($1,$2,$3) ON CONFLICT (name_normalized) DO NOTHING")).
+		WithArgs("", "spotify", "").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.Create(context.Background(), entities.Service{Name: "spotify"})
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrAlreadyExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestService_GetByID_NotFound(t *testing.T) {
+	repo, mock, _ := newServiceRepository(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT " + serviceColumns + " FROM services WHERE id = $1")).
+		WithArgs(int64(1)).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := repo.GetByID(context.Background(), 1)
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestService_GetByID_Success(t *testing.T) {
+	repo, mock, _ := newServiceRepository(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT " + serviceColumns + " FROM services WHERE id = $1")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "default_price"}).AddRow(1, "Spotify", nil))
+
+	service, err := repo.GetByID(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Spotify", service.Name)
+	assert.False(t, service.DefaultPrice.Valid)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestService_List_Success(t *testing.T) {
+	repo, mock, _ := newServiceRepository(t)
+
+	filter := entities.ServiceFilter{
+		Name:       "Spot",
+		Category:   "music",
+		Pagination: entities.PaginationParams{Page: 2, Limit: 10},
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT "+serviceColumns+" FROM services WHERE name_normalized LIKE $1 AND category = $2 "+
+		"ORDER BY name_normalized, id LIMIT 10 OFFSET 10")).
+		WithArgs("%spot%", "music").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Spotify").AddRow(2, "Spotify Family"))
+
+	services, err := repo.List(context.Background(), filter)
+
+	require.NoError(t, err)
+	require.Len(t, services, 2)
+	assert.Equal(t, "Spotify Family", services[1].Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestService_Update_InvalidField(t *testing.T) {
+	repo, _, logger := newServiceRepository(t)
+
+	logger.EXPECT().Error(gomock.Any(), gomock.Any())
+
+	err := repo.Update(context.Background(), 1, map[string]any{"user_id": "x"})
+
+	require.Error(t, err)
+}
+
+func TestService_Update_NameTaken(t *testing.T) {
+	repo, mock, _ := newServiceRepository(t)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE services SET name = $1, updated_at = $2 WHERE id = $3 "+
+		"AND NOT EXISTS (SELECT 1 FROM services AS o WHERE o.name_normalized = $4 AND o.id <> $5)")).
+		WithArgs("Spotify ", sqlmock.AnyArg(), int64(1), "spotify", int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.Update(context.Background(), 1, map[string]any{"name": "Spotify "})
+
+	assert.ErrorIs(t, err, errors.ErrAlreadyExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestService_Update_Success(t *testing.T) {
+	repo, mock, _ := newServiceRepository(t)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE services SET category = $1, updated_at = $2 WHERE id = $3")).
+		WithArgs("music", sqlmock.AnyArg(), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.Update(context.Background(), 1, map[string]any{"category": "music"})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestService_Delete_NotFound(t *testing.T) {
+	repo, mock, _ := newServiceRepository(t)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM services WHERE id = $1")).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.Delete(context.Background(), 1)
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestService_LinkSubscriptions_Success(t *testing.T) {
+	repo, mock, _ := newServiceRepository(t)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET service_id = $1, service_name = $2, updated_at = $3, version = version + 1 "+
+		"WHERE (service_id = $4 OR (service_id IS NULL AND service_name_normalized = $5)) AND (service_id IS NULL OR service_name <> $6)")).
+		WithArgs(int64(1), "Spotify", sqlmock.AnyArg(), int64(1), "spotify", "Spotify").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	table = "subscription"
+	linked, err := repo.LinkSubscriptions(context.Background(), serviceTest)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), linked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -5,9 +5,12 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 
 	"github.com/mathbdw/subscription-service/internal/domain/entities"
@@ -23,8 +26,8 @@ type subscriptionRepository struct {
 	logger observability.Logger
 }
 
-// NewUserRepository - Constructor UserRepository
-func NewUserRepository(querier sqlx.ExtContext, builder sq.StatementBuilderType, logger observability.Logger) repositories.SubscriptionRepository {
+// NewSubscriptionRepository - Constructor SubscriptionRepository
+func NewSubscriptionRepository(querier sqlx.ExtContext, builder sq.StatementBuilderType, logger observability.Logger) repositories.SubscriptionRepository {
 	return &subscriptionRepository{
 		querier: querier,
 		builder: builder,
@@ -33,52 +36,104 @@ func NewUserRepository(querier sqlx.ExtContext, builder sq.StatementBuilderType,
 	}
 }
 
+// conn - returns the transaction carried by the context or the repository querier
+func (r *subscriptionRepository) conn(ctx context.Context) sqlx.ExtContext {
+	return querierFromContext(ctx, r.querier)
+}
+
 var (
-	table              = "subscription"
-	columnsSelect      = []string{"id", "service_name", "user_id", "price", "start_date", "end_date"}
-	columnsSelectCount = []string{"COUNT(*)"}
-	columnsCost        = []string{"COALESCE(SUM(price), 0)"}
+	table                 = "subscription"
+	tableStatusHistory    = "subscription_status_history"
+	tablePriceHistory     = "subscription_price_history"
+	tableTags             = "tags"
+	tableSubscriptionTags = "subscription_tags"
+	tableMembers          = "subscription_members"
+	columnsSelect         = []string{"id", "service_name", "user_id", "price", "currency", "billing_period", "billing_interval", "start_date", "end_date", "trial_end_date", "status", "status_changed_at", "deleted_at", "version", "created_at", "updated_at", "service_id", "category", columnTags}
+	columnsStatus         = []string{"id", "subscription_id", "status", "started_at", "ended_at"}
+	columnsPrice          = []string{"price", "effective_from", "created_at"}
+	columnsMember         = []string{"subscription_id", "user_id", "share_type", "share", "created_at"}
+	columnsSelectCount    = []string{"COUNT(*)"}
+	columnsCost           = []string{"s.id", "s.service_name", "s.user_id", "s.price", "s.currency", "s.billing_period", "s.billing_interval", "COUNT(*) AS months", "ROUND(SUM(" + priceMonthly + "))::bigint AS cost"}
+	columnsCostGrouped    = []string{"COUNT(DISTINCT s.id) AS subscriptions", "ROUND(SUM(" + priceMonthly + "))::bigint AS cost"}
+	columnsCostMonthly    = []string{"m.month", "s.service_name", "s.currency", "COUNT(DISTINCT s.id) AS subscriptions", "ROUND(SUM(" + priceMonthly + "))::bigint AS cost"}
 )
 
-// Create - create new row
-func (r *subscriptionRepository) Create(ctx context.Context, subs entities.Subscription) error {
-	dataMap := SubscriptionToMap(subs)
+// columnTags - names of the subscription tags as JSON array ordered by name
+const columnTags = "COALESCE((SELECT json_agg(t.name ORDER BY t.name) FROM subscription_tags AS st" +
+	" JOIN tags AS t ON t.id = st.tag_id WHERE st.subscription_id = subscription.id), '[]') AS tags"
 
-	query, args, err := r.builder.Insert(table).SetMap(dataMap).ToSql()
-	if err != nil {
-		return errs.Wrap(err, "subscriptionRepositories.Create: build query")
+// exportCursor - name of the server-side cursor of the export, exportFetchSize - rows fetched from it at once
+const (
+	exportCursor    = "subscription_export"
+	exportFetchSize = 500
+)
+
+// priceEffective - price of the subscription effective in the billed month
+const priceEffective = "COALESCE(p.price, s.price)"
+
+// priceMonthly - price of the subscription normalized to one month
+const priceMonthly = "CASE s.billing_period" +
+	" WHEN 'weekly' THEN " + priceEffective + " * 52.0 / 12" +
+	" WHEN 'quarterly' THEN " + priceEffective + " / 3.0" +
+	" WHEN 'yearly' THEN " + priceEffective + " / 12.0" +
+	" WHEN 'custom' THEN " + priceEffective + "::numeric / s.billing_interval" +
+	" ELSE " + priceEffective + " END"
+
+// serviceLink - columns linking the subscription to the catalog entry with the normalized name,
+// the subscription takes the canonical name of the entry, without the entry the name is kept
+func serviceLink(name string) map[string]any {
+	normalized := entities.NormalizeServiceName(name)
+
+	return map[string]any{
+		"service_id":   sq.Expr("(SELECT id FROM services WHERE name_normalized = ?)", normalized),
+		"service_name": sq.Expr("COALESCE((SELECT name FROM services WHERE name_normalized = ?), ?)", normalized, name),
 	}
+}
 
-	res, err := r.querier.ExecContext(ctx, query, args...)
-	if err != nil {
-		return errs.Wrap(err, "subscriptionRepositories.Create: exec query")
+// Create - create new row, returns the row hydrated with the generated ID, defaults and timestamps.
+// The row is linked to the service catalog by the service name.
+// ErrInvalidInput is returned when the user does not exist.
+func (r *subscriptionRepository) Create(ctx context.Context, subs entities.Subscription) (*entities.Subscription, error) {
+	dataMap := SubscriptionToMap(subs)
+	for column, value := range serviceLink(subs.ServiceName) {
+		dataMap[column] = value
 	}
 
-	rowsAffected, err := res.RowsAffected()
+	query, args, err := r.builder.Insert(table).
+		SetMap(dataMap).
+		Suffix("RETURNING " + strings.Join(columnsSelect, ", ")).
+		ToSql()
 	if err != nil {
-		return errs.Wrap(err, "subscriptionRepositories.Create: get affected rows")
+		return nil, errs.Wrap(err, "subscriptionRepositories.Create: build query")
 	}
 
-	if rowsAffected != 1 {
-		return fmt.Errorf("subscriptionRepositories.Create: expected rowsAffected %d", rowsAffected)
+	created := &entities.Subscription{}
+	err = r.conn(ctx).QueryRowxContext(ctx, query, args...).StructScan(created)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, errs.Wrap(errs.ErrInvalidInput, "subscriptionRepositories.Create: unknown user")
+		}
+		return nil, errs.Wrap(err, "subscriptionRepositories.Create: exec query")
 	}
 
-	return nil
+	return created, nil
 }
 
-// GetByID - Returns subscription by ID
-func (r *subscriptionRepository) GetByID(ctx context.Context, id int64) (*entities.Subscription, error) {
-	query, args, err := r.builder.Select(columnsSelect...).
+// GetByID - Returns subscription by ID, soft-deleted subscription is returned only with includeDeleted
+func (r *subscriptionRepository) GetByID(ctx context.Context, id int64, includeDeleted bool) (*entities.Subscription, error) {
+	build := r.builder.Select(columnsSelect...).
 		From(table).
-		Where(sq.Eq{"id": id}).
-		ToSql()
+		Where(sq.Eq{"id": id})
+	build = excludeDeleted(build, "deleted_at", includeDeleted)
+
+	query, args, err := build.ToSql()
 	if err != nil {
 		return nil, errs.Wrap(err, "subscriptionRepositories.getByID: build query")
 	}
 
 	sub := &entities.Subscription{}
 
-	err = r.querier.QueryRowxContext(ctx, query, args...).StructScan(sub)
+	err = r.conn(ctx).QueryRowxContext(ctx, query, args...).StructScan(sub)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errs.ErrNotFound
@@ -89,34 +144,54 @@ func (r *subscriptionRepository) GetByID(ctx context.Context, id int64) (*entiti
 	return sub, nil
 }
 
-// List - Returns a list of subscription using query criteria
+// List - Returns a list of subscription using query criteria.
+// With the cursor rows after it are returned (keyset pagination), otherwise the page by offset.
+// The total count is queried unless WithoutTotal is set.
 func (r *subscriptionRepository) List(ctx context.Context, params entities.QueryCriteria) (*entities.ResponseListSubscription, error) {
-	query := r.builder.Select(columnsSelectCount...).From(table)
-	query = conditionList(query, params.Filter)
-	sql, args, err := query.ToSql()
-	if err != nil {
-		return nil, errs.Wrap(err, "subscriptionRepositories.List: build query count()")
-	}
+	info := entities.PaginationInfo{WithTotal: !params.Pagination.WithoutTotal}
 
 	var totalCount uint64
-	err = r.querier.QueryRowxContext(ctx, sql, args...).Scan(&totalCount)
-	if err != nil {
-		return nil, errs.Wrap(err, "subscriptionRepositories.List: scan query")
+	if info.WithTotal {
+		query := r.builder.Select(columnsSelectCount...).From(table)
+		query = conditionList(query, params.Filter)
+		query = excludeDeleted(query, "deleted_at", params.Filter.IncludeDeleted)
+		sql, args, err := query.ToSql()
+		if err != nil {
+			return nil, errs.Wrap(err, "subscriptionRepositories.List: build query count()")
+		}
+
+		err = r.conn(ctx).QueryRowxContext(ctx, sql, args...).Scan(&totalCount)
+		if err != nil {
+			return nil, errs.Wrap(err, "subscriptionRepositories.List: scan query")
+		}
 	}
 
 	limit := params.Pagination.Limit
+	cursor := params.Pagination.Cursor
 
-	query = r.builder.Select(columnsSelect...).From(table)
+	query := r.builder.Select(columnsSelect...).From(table)
+	query = rankList(query, params.Filter.Search)
 	query = conditionList(query, params.Filter)
-	query = paginationList(query, totalCount, &params.Pagination)
-	query = sortList(query, params.Sort)
+	query = excludeDeleted(query, "deleted_at", params.Filter.IncludeDeleted)
+	switch {
+	case cursor != nil:
+		// one more row tells whether there is the next page
+		params.Sort = cursor.Sort
+		query = keysetList(query, *cursor, params.Filter.Search).Limit(limit + 1)
+	case info.WithTotal:
+		query = paginationList(query, params.Pagination)
+	default:
+		// one more row tells whether there is the next page
+		query = paginationList(query, params.Pagination).Limit(limit + 1)
+	}
+	query = sortList(query, params.Sort, params.Filter.Search)
 
-	sql, args, err = query.ToSql()
+	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, errs.Wrap(err, "subscriptionRepositories.List: build query")
 	}
 
-	rows, err := r.querier.QueryxContext(ctx, sql, args...)
+	rows, err := r.conn(ctx).QueryxContext(ctx, sql, args...)
 	if err != nil {
 		return nil, errs.Wrap(err, "subscriptionRepositories.List: get query")
 	}
@@ -136,24 +211,47 @@ func (r *subscriptionRepository) List(ctx context.Context, params entities.Query
 		return nil, errs.Wrap(err, "subscriptionRepositories.List: iteration rows")
 	}
 
-	totalPages := totalCount / limit
-	if totalPages*limit < totalCount {
-		totalPages++
+	if info.WithTotal {
+		totalPages := totalCount / limit
+		if totalPages*limit < totalCount {
+			totalPages++
+		}
+
+		info.TotalCount = totalCount
+		info.TotalPages = uint32(totalPages)
+	}
+
+	if cursor == nil && info.WithTotal {
+		info.Page = params.Pagination.Page
+		info.PageSize = uint16(params.Pagination.Limit)
+		info.HasNext = info.Page < uint64(info.TotalPages)
+	} else {
+		if cursor == nil {
+			info.Page = params.Pagination.Page
+		}
+		info.PageSize = uint16(limit)
+		info.HasNext = uint64(len(subs)) > limit
+		if info.HasNext {
+			subs = subs[:limit]
+		}
+	}
+
+	if info.HasNext && len(subs) > 0 {
+		info.Next = cursorList(subs[len(subs)-1], params.Sort)
 	}
 
 	return &entities.ResponseListSubscription{
 		Data: subs,
-		Info: entities.PaginationInfo{
-			Page:       params.Pagination.Page,
-			PageSize:   uint16(params.Pagination.Limit),
-			TotalCount: totalCount,
-			TotalPages: uint32(totalPages),
-		},
+		Info: info,
 	}, nil
 }
 
-// Update - Updated the fields
-func (r *subscriptionRepository) Update(ctx context.Context, id int64, fields map[string]any) error {
+// versionNext - increments the row version on every write
+var versionNext = sq.Expr("version + 1")
+
+// Update - Updated the fields and increments the version.
+// When version is set the row is updated only in that version, otherwise ErrConflict is returned.
+func (r *subscriptionRepository) Update(ctx context.Context, id int64, fields map[string]any, version int64) error {
 	if err := validateUpdateFields(fields); err != nil {
 		r.logger.Error("subscriptionRepositories.Update: validateUpdateFields", fields)
 
@@ -162,16 +260,33 @@ func (r *subscriptionRepository) Update(ctx context.Context, id int64, fields ma
 
 	fields["updated_at"] = time.Now().UTC()
 
-	query, args, err := r.builder.Update(table).
+	set := make(map[string]any, len(fields)+1)
+	for column, value := range fields {
+		set[column] = value
+	}
+	if name, ok := fields["service_name"].(string); ok {
+		for column, value := range serviceLink(name) {
+			set[column] = value
+		}
+	}
+	if category, ok := fields["category"].(string); ok {
+		set["category"] = entities.NormalizeTag(category)
+	}
+
+	query, args, err := whereVersion(r.builder.Update(table).
 		Where(sq.Eq{"id": id}).
-		SetMap(fields).
+		SetMap(set).
+		Set("version", versionNext), version).
 		ToSql()
 	if err != nil {
 		return errs.Wrap(err, "subscriptionRepositories.Update: build query")
 	}
 
-	res, err := r.querier.ExecContext(ctx, query, args...)
+	res, err := r.conn(ctx).ExecContext(ctx, query, args...)
 	if err != nil {
+		if isForeignKeyViolation(err) {
+			return errs.Wrap(errs.ErrInvalidInput, "subscriptionRepositories.Update: unknown user")
+		}
 		return errs.Wrap(err, "subscriptionRepositories.Update: exec query")
 	}
 
@@ -180,6 +295,10 @@ func (r *subscriptionRepository) Update(ctx context.Context, id int64, fields ma
 		return errs.Wrap(err, "subscriptionRepositories.Update: get affected rows")
 	}
 
+	if rowsAffected == 0 && version > 0 {
+		return errs.Wrap(errs.ErrConflict, "subscriptionRepositories.Update: version changed")
+	}
+
 	if rowsAffected != 1 {
 		return fmt.Errorf("subscriptionRepositories.Update: expected rowsAffected %d", rowsAffected)
 	}
@@ -187,16 +306,22 @@ func (r *subscriptionRepository) Update(ctx context.Context, id int64, fields ma
 	return nil
 }
 
-// Delete - Deleted row with the id
-func (r *subscriptionRepository) Delete(ctx context.Context, id int64) error {
-	query, args, err := r.builder.Delete(table).
-		Where(sq.Eq{"id": id}).
+// Delete - Marks the row with the id deleted, it is kept until purge.
+// When version is set the row is deleted only in that version, otherwise ErrConflict is returned.
+func (r *subscriptionRepository) Delete(ctx context.Context, id int64, version int64) error {
+	now := time.Now().UTC()
+
+	query, args, err := whereVersion(r.builder.Update(table).
+		Set("deleted_at", now).
+		Set("updated_at", now).
+		Set("version", versionNext).
+		Where(sq.Eq{"id": id, "deleted_at": nil}), version).
 		ToSql()
 	if err != nil {
 		return errs.Wrap(err, "subscriptionRepositories.Delete: build query")
 	}
 
-	res, err := r.querier.ExecContext(ctx, query, args...)
+	res, err := r.conn(ctx).ExecContext(ctx, query, args...)
 	if err != nil {
 		return errs.Wrap(err, "subscriptionRepositories.Delete: exec query")
 	}
@@ -206,6 +331,10 @@ func (r *subscriptionRepository) Delete(ctx context.Context, id int64) error {
 		return errs.Wrap(err, "subscriptionRepositories.Delete: get affected rows")
 	}
 
+	if rowsAffected == 0 && version > 0 {
+		return errs.Wrap(errs.ErrConflict, "subscriptionRepositories.Delete: version changed")
+	}
+
 	if rowsAffected != 1 {
 		return fmt.Errorf("subscriptionRepositories.Delete: expected rowsAffected %d", rowsAffected)
 	}
@@ -213,20 +342,612 @@ func (r *subscriptionRepository) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
-// GetCost - Returns total cost of user subscription
-func (r *subscriptionRepository) GetCost(ctx context.Context, params entities.FilterParams) (int64, error) {
-	query := r.builder.Select(columnsCost...).From(table)
+// Restore - Clears the deletion mark of the row with the id.
+// ErrNotFound is returned when there is no deleted row with the id.
+func (r *subscriptionRepository) Restore(ctx context.Context, id int64) error {
+	query, args, err := r.builder.Update(table).
+		Set("deleted_at", nil).
+		Set("updated_at", time.Now().UTC()).
+		Set("version", versionNext).
+		Where(sq.Eq{"id": id}).
+		Where(sq.NotEq{"deleted_at": nil}).
+		ToSql()
+	if err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.Restore: build query")
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.Restore: exec query")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.Restore: get affected rows")
+	}
+
+	if rowsAffected == 0 {
+		return errs.Wrap(errs.ErrNotFound, "subscriptionRepositories.Restore: no deleted row")
+	}
+
+	if rowsAffected != 1 {
+		return fmt.Errorf("subscriptionRepositories.Restore: expected rowsAffected %d", rowsAffected)
+	}
+
+	return nil
+}
+
+// Purge - Hard deletes rows marked deleted before the time, returns the number of deleted rows
+func (r *subscriptionRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	query, args, err := r.builder.Delete(table).
+		Where(sq.Lt{"deleted_at": before}).
+		ToSql()
+	if err != nil {
+		return 0, errs.Wrap(err, "subscriptionRepositories.Purge: build query")
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, errs.Wrap(err, "subscriptionRepositories.Purge: exec query")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, errs.Wrap(err, "subscriptionRepositories.Purge: get affected rows")
+	}
+
+	return rowsAffected, nil
+}
+
+// GetCost - Returns cost of every subscription billed within the period.
+// With the user filter the cost of the shared subscriptions is the share of the user.
+func (r *subscriptionRepository) GetCost(ctx context.Context, params entities.FilterParams) ([]entities.CostItem, error) {
+	query := r.builder.Select(columnsCost...).From(table + " AS s")
+	query = joinCost(query, params.Period, params.UserId != uuid.Nil)
 	query = conditionCost(query, params)
+	query = excludePausedMonths(query)
+	query = excludeDeleted(query, "s.deleted_at", params.IncludeDeleted)
+	query = query.GroupBy("s.id").OrderBy("s.id")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.GetCost: build query")
+	}
+
+	rows, err := r.conn(ctx).QueryxContext(ctx, sql, args...)
+	if err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.GetCost: get query")
+	}
+	defer rows.Close()
+
+	items := make([]entities.CostItem, 0)
+	for rows.Next() {
+		var item entities.CostItem
+		err = rows.StructScan(&item)
+		if err != nil {
+			return nil, errs.Wrap(err, "subscriptionRepositories.GetCost: scan query")
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.GetCost: iteration rows")
+	}
+
+	return items, nil
+}
+
+// GetCostMonthly - Returns cost of every service billed within the period grouped by month and currency
+func (r *subscriptionRepository) GetCostMonthly(ctx context.Context, params entities.FilterParams) ([]entities.CostMonthService, error) {
+	query := r.builder.Select(columnsCostMonthly...).From(table + " AS s")
+	query = joinCost(query, params.Period, params.UserId != uuid.Nil)
+	query = conditionCost(query, params)
+	query = excludePausedMonths(query)
+	query = excludeDeleted(query, "s.deleted_at", params.IncludeDeleted)
+	query = query.GroupBy("m.month", "s.service_name", "s.currency").OrderBy("m.month", "s.service_name", "s.currency")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.GetCostMonthly: build query")
+	}
+
+	rows, err := r.conn(ctx).QueryxContext(ctx, sql, args...)
+	if err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.GetCostMonthly: get query")
+	}
+	defer rows.Close()
+
+	services := make([]entities.CostMonthService, 0)
+	for rows.Next() {
+		var service entities.CostMonthService
+		err = rows.StructScan(&service)
+		if err != nil {
+			return nil, errs.Wrap(err, "subscriptionRepositories.GetCostMonthly: scan query")
+		}
+		services = append(services, service)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.GetCostMonthly: iteration rows")
+	}
+
+	return services, nil
+}
+
+// GetCostGrouped - Returns cost of subscriptions billed within the period grouped by service, user or both.
+// Shared subscriptions are split between the groups of the users by the shares of the members.
+func (r *subscriptionRepository) GetCostGrouped(ctx context.Context, params entities.FilterParams, groupBy []entities.CostGroupType) ([]entities.CostGroup, error) {
+	query := r.builder.Select(columnsCostGrouped...).From(table + " AS s")
+	query = joinCost(query, params.Period, params.UserId != uuid.Nil || slices.Contains(groupBy, entities.CostGroupTypeUserID))
+	query = conditionCost(query, params)
+	query = excludePausedMonths(query)
+	query = excludeDeleted(query, "s.deleted_at", params.IncludeDeleted)
+	query = groupCost(query, groupBy)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.GetCostGrouped: build query")
+	}
+
+	rows, err := r.conn(ctx).QueryxContext(ctx, sql, args...)
+	if err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.GetCostGrouped: get query")
+	}
+	defer rows.Close()
+
+	groups := make([]entities.CostGroup, 0)
+	for rows.Next() {
+		var group entities.CostGroup
+		err = rows.StructScan(&group)
+		if err != nil {
+			return nil, errs.Wrap(err, "subscriptionRepositories.GetCostGrouped: scan query")
+		}
+		groups = append(groups, group)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.GetCostGrouped: iteration rows")
+	}
+
+	return groups, nil
+}
+
+// ChangeStatus - Sets status of the subscription, closes the current status period and opens a new one.
+// ErrNotFound is returned when the subscription does not exist or is deleted.
+func (r *subscriptionRepository) ChangeStatus(ctx context.Context, id int64, status entities.SubscriptionStatusType, at time.Time) error {
+	query, args, err := r.builder.Update(table).
+		Where(sq.Eq{"id": id}).
+		Where(sq.Eq{"deleted_at": nil}).
+		SetMap(map[string]any{
+			"status":            status,
+			"status_changed_at": at,
+			"updated_at":        at,
+			"version":           versionNext,
+		}).
+		ToSql()
+	if err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.ChangeStatus: build query")
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.ChangeStatus: exec query")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.ChangeStatus: get affected rows")
+	}
+
+	if rowsAffected == 0 {
+		return errs.Wrap(errs.ErrNotFound, "subscriptionRepositories.ChangeStatus")
+	}
+
+	if rowsAffected != 1 {
+		return fmt.Errorf("subscriptionRepositories.ChangeStatus: expected rowsAffected %d", rowsAffected)
+	}
+
+	query, args, err = r.builder.Update(tableStatusHistory).
+		Set("ended_at", at).
+		Where(sq.Eq{"subscription_id": id, "ended_at": nil}).
+		ToSql()
+	if err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.ChangeStatus: build query close period")
+	}
+
+	if _, err = r.conn(ctx).ExecContext(ctx, query, args...); err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.ChangeStatus: exec query close period")
+	}
+
+	query, args, err = r.builder.Insert(tableStatusHistory).
+		SetMap(map[string]any{
+			"subscription_id": id,
+			"status":          status,
+			"started_at":      at,
+		}).
+		ToSql()
+	if err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.ChangeStatus: build query open period")
+	}
+
+	if _, err = r.conn(ctx).ExecContext(ctx, query, args...); err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.ChangeStatus: exec query open period")
+	}
+
+	return nil
+}
+
+// GetStatusHistory - Returns status periods of the subscription in chronological order
+func (r *subscriptionRepository) GetStatusHistory(ctx context.Context, id int64) ([]entities.SubscriptionStatusChange, error) {
+	query, args, err := r.builder.Select(columnsStatus...).
+		From(tableStatusHistory).
+		Where(sq.Eq{"subscription_id": id}).
+		OrderBy("started_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.GetStatusHistory: build query")
+	}
+
+	rows, err := r.conn(ctx).QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.GetStatusHistory: get query")
+	}
+	defer rows.Close()
+
+	history := make([]entities.SubscriptionStatusChange, 0)
+	for rows.Next() {
+		var change entities.SubscriptionStatusChange
+		err = rows.StructScan(&change)
+		if err != nil {
+			return nil, errs.Wrap(err, "subscriptionRepositories.GetStatusHistory: scan query")
+		}
+		history = append(history, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.GetStatusHistory: iteration rows")
+	}
+
+	return history, nil
+}
+
+// ListTrialsEnding - Returns subscriptions in trial which convert to paid within the period.
+// The stored trial status is not switched when the trial ends, so a trial ended before at is skipped
+func (r *subscriptionRepository) ListTrialsEnding(ctx context.Context, period entities.DateRange, at time.Time) ([]entities.Subscription, error) {
+	query := r.builder.Select(columnsSelect...).
+		From(table).
+		Where(sq.Eq{"status": entities.SubscriptionStatusTrial}).
+		Where(sq.Gt{"trial_end_date": at})
+	query = excludeDeleted(query, "deleted_at", false)
+
+	if period.From != nil {
+		query = query.Where(sq.GtOrEq{"trial_end_date": period.From})
+	}
+
+	if period.To != nil {
+		query = query.Where(sq.LtOrEq{"trial_end_date": period.To})
+	}
+
+	sql, args, err := query.OrderBy("trial_end_date", "id").ToSql()
+	if err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.ListTrialsEnding: build query")
+	}
+
+	rows, err := r.conn(ctx).QueryxContext(ctx, sql, args...)
+	if err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.ListTrialsEnding: get query")
+	}
+	defer rows.Close()
+
+	subs := make([]entities.Subscription, 0)
+	for rows.Next() {
+		var sub entities.Subscription
+		err = rows.StructScan(&sub)
+		if err != nil {
+			return nil, errs.Wrap(err, "subscriptionRepositories.ListTrialsEnding: scan query")
+		}
+		subs = append(subs, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.ListTrialsEnding: iteration rows")
+	}
+
+	return subs, nil
+}
+
+// AddPrice - Sets price of the subscription effective from the month, replaces the price set for the same month
+func (r *subscriptionRepository) AddPrice(ctx context.Context, id int64, price uint32, effectiveFrom time.Time) error {
+	query, args, err := r.builder.Insert(tablePriceHistory).
+		SetMap(map[string]any{
+			"subscription_id": id,
+			"price":           price,
+			"effective_from":  effectiveFrom.Format("2006-01-02"),
+		}).
+		Suffix("ON CONFLICT (subscription_id, effective_from) DO UPDATE SET price = EXCLUDED.price, created_at = NOW()").
+		ToSql()
+	if err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.AddPrice: build query")
+	}
+
+	if _, err = r.conn(ctx).ExecContext(ctx, query, args...); err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.AddPrice: exec query")
+	}
+
+	return nil
+}
+
+// DeletePricesUntil - Deletes prices of the subscription effective up to the date inclusive
+func (r *subscriptionRepository) DeletePricesUntil(ctx context.Context, id int64, until time.Time) error {
+	query, args, err := r.builder.Delete(tablePriceHistory).
+		Where(sq.Eq{"subscription_id": id}).
+		Where(sq.LtOrEq{"effective_from": until.Format("2006-01-02")}).
+		ToSql()
+	if err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.DeletePricesUntil: build query")
+	}
+
+	if _, err = r.conn(ctx).ExecContext(ctx, query, args...); err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.DeletePricesUntil: exec query")
+	}
+
+	return nil
+}
+
+// GetPriceHistory - Returns price changes of the subscription in chronological order
+func (r *subscriptionRepository) GetPriceHistory(ctx context.Context, id int64) ([]entities.PriceChange, error) {
+	query, args, err := r.builder.Select(columnsPrice...).
+		From(tablePriceHistory).
+		Where(sq.Eq{"subscription_id": id}).
+		OrderBy("effective_from").
+		ToSql()
+	if err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.GetPriceHistory: build query")
+	}
+
+	rows, err := r.conn(ctx).QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.GetPriceHistory: get query")
+	}
+	defer rows.Close()
+
+	prices := make([]entities.PriceChange, 0)
+	for rows.Next() {
+		var price entities.PriceChange
+		err = rows.StructScan(&price)
+		if err != nil {
+			return nil, errs.Wrap(err, "subscriptionRepositories.GetPriceHistory: scan query")
+		}
+		prices = append(prices, price)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.GetPriceHistory: iteration rows")
+	}
+
+	return prices, nil
+}
+
+// Export - Passes subscriptions matching the filter to fn in the sort order without pagination.
+// Rows are read by a server-side cursor, so it must be called within a transaction.
+func (r *subscriptionRepository) Export(ctx context.Context, params entities.QueryCriteria, fn func(entities.Subscription) error) error {
+	query := r.builder.Select(columnsSelect...).From(table)
+	query = rankList(query, params.Filter.Search)
+	query = conditionList(query, params.Filter)
+	query = excludeDeleted(query, "deleted_at", params.Filter.IncludeDeleted)
+	query = sortList(query, params.Sort, params.Filter.Search)
+
 	sql, args, err := query.ToSql()
 	if err != nil {
-		return 0, errs.Wrap(err, "subscriptionRepositories.GetCost: build query")
+		return errs.Wrap(err, "subscriptionRepositories.Export: build query")
 	}
 
-	var cost int64
+	conn := r.conn(ctx)
+	_, err = conn.ExecContext(ctx, fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s", exportCursor, sql), args...)
+	if err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.Export: declare cursor")
+	}
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM %s", exportFetchSize, exportCursor)
+	for {
+		fetched, err := r.fetchExport(ctx, conn, fetch, fn)
+		if err != nil {
+			return err
+		}
+		if fetched < exportFetchSize {
+			break
+		}
+	}
 
-	err = r.querier.QueryRowxContext(ctx, sql, args...).Scan(&cost)
+	_, err = conn.ExecContext(ctx, "CLOSE "+exportCursor)
 	if err != nil {
-		return 0, errs.Wrap(err, "subscriptionRepositories.GetCost: scan query")
+		return errs.Wrap(err, "subscriptionRepositories.Export: close cursor")
 	}
-	return cost, nil
+
+	return nil
+}
+
+// fetchExport - fetches the next rows of the export cursor, returns the number of fetched rows
+func (r *subscriptionRepository) fetchExport(ctx context.Context, conn sqlx.ExtContext, fetch string, fn func(entities.Subscription) error) (int, error) {
+	rows, err := conn.QueryxContext(ctx, fetch)
+	if err != nil {
+		return 0, errs.Wrap(err, "subscriptionRepositories.Export: fetch cursor")
+	}
+	defer rows.Close()
+
+	fetched := 0
+	for rows.Next() {
+		var sub entities.Subscription
+		if err := rows.StructScan(&sub); err != nil {
+			return 0, errs.Wrap(err, "subscriptionRepositories.Export: scan query")
+		}
+		fetched++
+
+		if err := fn(sub); err != nil {
+			return 0, errs.Wrap(err, "subscriptionRepositories.Export: write row")
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return 0, errs.Wrap(err, "subscriptionRepositories.Export: iteration rows")
+	}
+
+	return fetched, nil
+}
+
+// touch - increments the version of the subscription whose linked rows are replaced
+func (r *subscriptionRepository) touch(ctx context.Context, conn sqlx.ExtContext, id int64) error {
+	query, args, err := r.builder.Update(table).
+		Set("updated_at", time.Now().UTC()).
+		Set("version", versionNext).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return errs.Wrap(err, "build version query")
+	}
+
+	if _, err := conn.ExecContext(ctx, query, args...); err != nil {
+		return errs.Wrap(err, "exec version query")
+	}
+
+	return nil
+}
+
+// SetTags - Replaces tags of the subscription with the normalized tags, missing tags are created.
+// The version of the subscription is incremented.
+func (r *subscriptionRepository) SetTags(ctx context.Context, id int64, tags []string) error {
+	conn := r.conn(ctx)
+
+	if err := r.touch(ctx, conn, id); err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.SetTags")
+	}
+
+	query, args, err := r.builder.Delete(tableSubscriptionTags).
+		Where(sq.Eq{"subscription_id": id}).
+		ToSql()
+	if err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.SetTags: build delete query")
+	}
+
+	if _, err := conn.ExecContext(ctx, query, args...); err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.SetTags: exec delete query")
+	}
+
+	names := entities.NormalizeTags(tags)
+	if len(names) == 0 {
+		return nil
+	}
+
+	insert := r.builder.Insert(tableTags).
+		Columns("name").
+		Suffix("ON CONFLICT (name) DO NOTHING")
+	for _, name := range names {
+		insert = insert.Values(name)
+	}
+
+	query, args, err = insert.ToSql()
+	if err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.SetTags: build tags query")
+	}
+
+	if _, err := conn.ExecContext(ctx, query, args...); err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.SetTags: exec tags query")
+	}
+
+	query, args, err = r.builder.Insert(tableSubscriptionTags).
+		Columns("subscription_id", "tag_id").
+		Select(sq.Select().
+			Column("CAST(? AS bigint)", id).
+			Column("id").
+			From(tableTags).
+			Where(sq.Eq{"name": []string(names)})).
+		ToSql()
+	if err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.SetTags: build link query")
+	}
+
+	if _, err := conn.ExecContext(ctx, query, args...); err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.SetTags: exec link query")
+	}
+
+	return nil
+}
+
+// GetMembers - Returns members sharing the cost of the subscription ordered by the time they joined
+func (r *subscriptionRepository) GetMembers(ctx context.Context, id int64) ([]entities.Member, error) {
+	query, args, err := r.builder.Select(columnsMember...).
+		From(tableMembers).
+		Where(sq.Eq{"subscription_id": id}).
+		OrderBy("created_at", "user_id").
+		ToSql()
+	if err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.GetMembers: build query")
+	}
+
+	rows, err := r.conn(ctx).QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.GetMembers: get query")
+	}
+	defer rows.Close()
+
+	members := make([]entities.Member, 0)
+	for rows.Next() {
+		var member entities.Member
+		err = rows.StructScan(&member)
+		if err != nil {
+			return nil, errs.Wrap(err, "subscriptionRepositories.GetMembers: scan query")
+		}
+		members = append(members, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errs.Wrap(err, "subscriptionRepositories.GetMembers: iteration rows")
+	}
+
+	return members, nil
+}
+
+// SetMembers - Replaces members of the subscription, empty members leave the subscription to its user.
+// The version of the subscription is incremented, ErrInvalidInput is returned when a member is not an existing user.
+func (r *subscriptionRepository) SetMembers(ctx context.Context, id int64, members []entities.Member) error {
+	conn := r.conn(ctx)
+
+	if err := r.touch(ctx, conn, id); err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.SetMembers")
+	}
+
+	query, args, err := r.builder.Delete(tableMembers).
+		Where(sq.Eq{"subscription_id": id}).
+		ToSql()
+	if err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.SetMembers: build delete query")
+	}
+
+	if _, err := conn.ExecContext(ctx, query, args...); err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.SetMembers: exec delete query")
+	}
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	insert := r.builder.Insert(tableMembers).
+		Columns("subscription_id", "user_id", "share_type", "share")
+	for _, member := range members {
+		insert = insert.Values(id, member.UserId, member.ShareType, member.Share)
+	}
+
+	query, args, err = insert.ToSql()
+	if err != nil {
+		return errs.Wrap(err, "subscriptionRepositories.SetMembers: build insert query")
+	}
+
+	if _, err := conn.ExecContext(ctx, query, args...); err != nil {
+		if isForeignKeyViolation(err) {
+			return errs.Wrap(errs.ErrInvalidInput, "subscriptionRepositories.SetMembers: unknown user")
+		}
+		return errs.Wrap(err, "subscriptionRepositories.SetMembers: exec insert query")
+	}
+
+	return nil
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,12 +24,20 @@ import (
 )
 
 var subTest = entities.Subscription{
-	ServiceName: "test service",
-	UserId:      uuid.New(),
-	Price:       100,
-	StartDate:   time.Now(),
+	ServiceName:     "test service",
+	UserId:          uuid.New(),
+	Price:           100,
+	Currency:        "USD",
+	BillingPeriod:   entities.BillingPeriodMonthly,
+	BillingInterval: 1,
+	StartDate:       time.Now(),
+	Status:          entities.SubscriptionStatusActive,
+	StatusChangedAt: time.Now(),
 }
 
+const tagsColumn = "COALESCE((SELECT json_agg(t.name ORDER BY t.name) FROM subscription_tags AS st " +
+	"JOIN tags AS t ON t.id = st.tag_id WHERE st.subscription_id = subscription.id), '[]') AS tags"
+
 func TestUser_Create_ErrorBuilder(t *testing.T) {
 	mockDB, mock, err := sqlmock.New()
 	require.NoError(t, err, "create mock")
@@ -38,20 +47,23 @@ func TestUser_Create_ErrorBuilder(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
-	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO (price,service_name,start_date,user_id) VALUES ($1,$2,$3,$4)")).
-		WithArgs(subTest.Price, subTest.ServiceName, subTest.StartDate, subTest.UserId).
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO (billing_interval,billing_period,currency,price,service_name,start_date,user_id) VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING id, service_name")).
+		WithArgs(subTest.BillingInterval, subTest.BillingPeriod, subTest.Currency, subTest.Price, entities.NormalizeServiceName(subTest.ServiceName), entities.NormalizeServiceName(subTest.ServiceName), subTest.ServiceName, subTest.StartDate, subTest.UserId).
 		WillReturnError(errors.New("build query"))
 
 	table = ""
-	err = repo.Create(ctx, entities.Subscription{
-		ServiceName: subTest.ServiceName,
-		UserId:      subTest.UserId,
-		Price:       subTest.Price,
-		StartDate:   subTest.StartDate,
-		EndDate:     subTest.EndDate,
+	_, err = repo.Create(ctx, entities.Subscription{
+		ServiceName:     subTest.ServiceName,
+		UserId:          subTest.UserId,
+		Price:           subTest.Price,
+		Currency:        subTest.Currency,
+		BillingPeriod:   subTest.BillingPeriod,
+		BillingInterval: subTest.BillingInterval,
+		StartDate:       subTest.StartDate,
+		EndDate:         subTest.EndDate,
 	})
 
 	require.Error(t, err)
@@ -67,20 +79,24 @@ func TestUser_Create_ErrorExec(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
-	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO subscription (price,service_name,start_date,user_id) VALUES ($1,$2,$3,$4)")).
-		WithArgs(subTest.Price, subTest.ServiceName, subTest.StartDate, subTest.UserId).
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO subscription (billing_interval,billing_period,currency,price,service_id,service_name,start_date,user_id) "+
+		"VALUES ($1,$2,$3,$4,(SELECT id FROM services WHERE name_normalized = $5),COALESCE((SELECT name FROM services WHERE name_normalized = $6), $7),$8,$9) RETURNING id, service_name")).
+		WithArgs(subTest.BillingInterval, subTest.BillingPeriod, subTest.Currency, subTest.Price, entities.NormalizeServiceName(subTest.ServiceName), entities.NormalizeServiceName(subTest.ServiceName), subTest.ServiceName, subTest.StartDate, subTest.UserId).
 		WillReturnError(sql.ErrNoRows)
 
 	table = "subscription"
-	err = repo.Create(ctx, entities.Subscription{
-		ServiceName: subTest.ServiceName,
-		UserId:      subTest.UserId,
-		Price:       subTest.Price,
-		StartDate:   subTest.StartDate,
-		EndDate:     subTest.EndDate,
+	_, err = repo.Create(ctx, entities.Subscription{
+		ServiceName:     subTest.ServiceName,
+		UserId:          subTest.UserId,
+		Price:           subTest.Price,
+		Currency:        subTest.Currency,
+		BillingPeriod:   subTest.BillingPeriod,
+		BillingInterval: subTest.BillingInterval,
+		StartDate:       subTest.StartDate,
+		EndDate:         subTest.EndDate,
 	})
 
 	require.Error(t, err)
@@ -88,18 +104,7 @@ func TestUser_Create_ErrorExec(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-// Create custom ErrorResult
-type ErrorResult struct{}
-
-func (r *ErrorResult) LastInsertId() (int64, error) {
-	return 0, nil
-}
-
-func (r *ErrorResult) RowsAffected() (int64, error) {
-	return 0, errors.New("rows affected error")
-}
-
-func TestUser_Create_ErrorAffectedRows(t *testing.T) {
+func TestUser_Create_UnknownUser(t *testing.T) {
 	mockDB, mock, err := sqlmock.New()
 	require.NoError(t, err, "create mock")
 	defer mockDB.Close()
@@ -108,53 +113,27 @@ func TestUser_Create_ErrorAffectedRows(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
-	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO subscription (price,service_name,start_date,user_id) VALUES ($1,$2,$3,$4)")).
-		WithArgs(subTest.Price, subTest.ServiceName, subTest.StartDate, subTest.UserId).
-		WillReturnResult(&ErrorResult{})
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO subscription")).
+		WillReturnError(&pgconn.PgError{Code: codeForeignKeyViolation})
 
-	err = repo.Create(ctx, entities.Subscription{
-		ServiceName: subTest.ServiceName,
-		UserId:      subTest.UserId,
-		Price:       subTest.Price,
-		StartDate:   subTest.StartDate,
-		EndDate:     subTest.EndDate,
-	})
+	_, err = repo.Create(ctx, subTest)
 
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "subscriptionRepositories.Create: get affected rows")
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestUser_Create_ErrorNotEquilRowsAffected(t *testing.T) {
-	mockDB, mock, err := sqlmock.New()
-	require.NoError(t, err, "create mock")
-	defer mockDB.Close()
-
-	ctrl := gomock.NewController(t)
-	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
-	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
-	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
-	ctx := context.Background()
-
-	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO subscription (price,service_name,start_date,user_id) VALUES ($1,$2,$3,$4)")).
-		WithArgs(subTest.Price, subTest.ServiceName, subTest.StartDate, subTest.UserId).
-		WillReturnResult(sqlmock.NewResult(0, 0))
+// Create custom ErrorResult
+type ErrorResult struct{}
 
-	err = repo.Create(ctx, entities.Subscription{
-		ServiceName: subTest.ServiceName,
-		UserId:      subTest.UserId,
-		Price:       subTest.Price,
-		StartDate:   subTest.StartDate,
-		EndDate:     subTest.EndDate,
-	})
+func (r *ErrorResult) LastInsertId() (int64, error) {
+	return 0, nil
+}
 
-	require.Error(t, err)
-	assert.Contains(t, err.Error(), "subscriptionRepositories.Create: expected rowsAffected")
-	assert.NoError(t, mock.ExpectationsWereMet())
+func (r *ErrorResult) RowsAffected() (int64, error) {
+	return 0, errors.New("rows affected error")
 }
 
 func TestUser_Create_Success(t *testing.T) {
@@ -166,20 +145,23 @@ func TestUser_Create_Success(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
+	createdAt := time.Now().UTC()
 	// endTime := sql.NullTime{Time: time.Now(), Valid: true}
 	tests := []struct {
 		name    string
 		endTime sql.NullTime
 		query   string
-		args []driver.Value
+		args    []driver.Value
 	}{
 		{
-			name:  "withoutEndTime",
-			query: "INSERT INTO subscription (price,service_name,start_date,user_id) VALUES ($1,$2,$3,$4)",
-			args:  []driver.Value{subTest.Price, subTest.ServiceName, subTest.StartDate, subTest.UserId},
+			name: "withoutEndTime",
+			query: "INSERT INTO subscription (billing_interval,billing_period,currency,price,service_id,service_name,start_date,user_id) " +
+				"VALUES ($1,$2,$3,$4,(SELECT id FROM services WHERE name_normalized = $5),COALESCE((SELECT name FROM services WHERE name_normalized = $6), $7),$8,$9) " +
+				"RETURNING id, service_name, user_id, price, currency, billing_period, billing_interval, start_date, end_date, trial_end_date, status, status_changed_at, deleted_at, version, created_at, updated_at, service_id, category, " + tagsColumn,
+			args: []driver.Value{subTest.BillingInterval, subTest.BillingPeriod, subTest.Currency, subTest.Price, entities.NormalizeServiceName(subTest.ServiceName), entities.NormalizeServiceName(subTest.ServiceName), subTest.ServiceName, subTest.StartDate, subTest.UserId},
 		},
 		// {
 		// 	name:    "withEndTime",
@@ -190,19 +172,28 @@ func TestUser_Create_Success(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mock.ExpectExec(regexp.QuoteMeta(tt.query)).
+			mock.ExpectQuery(regexp.QuoteMeta(tt.query)).
 				WithArgs(tt.args...).
-				WillReturnResult(sqlmock.NewResult(0, 1))
-
-			err = repo.Create(ctx, entities.Subscription{
-				ServiceName: subTest.ServiceName,
-				UserId:      subTest.UserId,
-				Price:       subTest.Price,
-				StartDate:   subTest.StartDate,
-				EndDate:     tt.endTime,
+				WillReturnRows(sqlmock.NewRows([]string{"id", "status", "version", "created_at", "updated_at"}).
+					AddRow(1, entities.SubscriptionStatusActive, 1, createdAt, createdAt))
+
+			created, err := repo.Create(ctx, entities.Subscription{
+				ServiceName:     subTest.ServiceName,
+				UserId:          subTest.UserId,
+				Price:           subTest.Price,
+				Currency:        subTest.Currency,
+				BillingPeriod:   subTest.BillingPeriod,
+				BillingInterval: subTest.BillingInterval,
+				StartDate:       subTest.StartDate,
+				EndDate:         tt.endTime,
 			})
 
 			require.Nil(t, err)
+			assert.Equal(t, int64(1), created.ID)
+			assert.Equal(t, entities.SubscriptionStatusActive, created.Status)
+			assert.Equal(t, int64(1), created.Version)
+			assert.Equal(t, createdAt, created.CreatedAt)
+			assert.Equal(t, createdAt, created.UpdatedAt)
 			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
@@ -217,7 +208,7 @@ func TestUser_GetByID_ErrorBuilder(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
 	columnsSelect = []string{}
@@ -225,7 +216,7 @@ func TestUser_GetByID_ErrorBuilder(t *testing.T) {
 		WithArgs(subTest.ID).
 		WillReturnError(errors.New("build query"))
 
-	sub, err := repo.GetByID(ctx, subTest.ID)
+	sub, err := repo.GetByID(ctx, subTest.ID, false)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "subscriptionRepositories.getByID: build query")
@@ -241,15 +232,15 @@ func TestUser_GetByID_ErrorScan(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, service_name, user_id, price, start_date, end_date FROM subscription WHERE id = $1")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, service_name, user_id, price, currency, billing_period, billing_interval, start_date, end_date, trial_end_date, status, status_changed_at, deleted_at, version, created_at, updated_at, service_id, category, " + tagsColumn + " FROM subscription WHERE id = $1 AND deleted_at IS NULL")).
 		WithArgs(subTest.ID).
 		WillReturnError(sql.ErrNoRows)
 
-	columnsSelect = []string{"id", "service_name", "user_id", "price", "start_date", "end_date"}
-	user, err := repo.GetByID(ctx, subTest.ID)
+	columnsSelect = []string{"id", "service_name", "user_id", "price", "currency", "billing_period", "billing_interval", "start_date", "end_date", "trial_end_date", "status", "status_changed_at", "deleted_at", "version", "created_at", "updated_at", "service_id", "category", columnTags}
+	user, err := repo.GetByID(ctx, subTest.ID, false)
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 	assert.Error(t, err)
@@ -266,17 +257,17 @@ func TestUser_GetByID_Success(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, service_name, user_id, price, start_date, end_date FROM subscription WHERE id = $1")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, service_name, user_id, price, currency, billing_period, billing_interval, start_date, end_date, trial_end_date, status, status_changed_at, deleted_at, version, created_at, updated_at, service_id, category, " + tagsColumn + " FROM subscription WHERE id = $1 AND deleted_at IS NULL")).
 		WithArgs(subTest.ID).
 		WillReturnRows(
-			sqlmock.NewRows([]string{"id", "service_name", "user_id", "price", "start_date", "end_date"}).
-				AddRow(subTest.ID, subTest.ServiceName, subTest.UserId, subTest.Price, subTest.StartDate, subTest.EndDate),
+			sqlmock.NewRows([]string{"id", "service_name", "user_id", "price", "currency", "billing_period", "billing_interval", "start_date", "end_date", "trial_end_date", "status", "status_changed_at"}).
+				AddRow(subTest.ID, subTest.ServiceName, subTest.UserId, subTest.Price, subTest.Currency, subTest.BillingPeriod, subTest.BillingInterval, subTest.StartDate, subTest.EndDate, subTest.TrialEndDate, subTest.Status, subTest.StatusChangedAt),
 		)
 
-	model, err := repo.GetByID(ctx, subTest.ID)
+	model, err := repo.GetByID(ctx, subTest.ID, false)
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 	assert.Nil(t, err)
@@ -297,7 +288,7 @@ func TestUser_List_ErrorBuildQueryCount(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
 	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM ")).
@@ -321,10 +312,10 @@ func TestUser_List_ErrorScanQueryCount(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM subscription")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM subscription WHERE deleted_at IS NULL")).
 		WithoutArgs().
 		WillReturnError(sql.ErrNoRows)
 
@@ -346,7 +337,7 @@ func TestUser_List_ErrorBuildQuery(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
 	qc := entities.QueryCriteria{
@@ -356,12 +347,12 @@ func TestUser_List_ErrorBuildQuery(t *testing.T) {
 	limit := qc.Pagination.Limit
 	offset := (qc.Pagination.Page - 1) * limit
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM subscription")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM subscription WHERE deleted_at IS NULL")).
 		WithoutArgs().
 		WillReturnRows(mock.NewRows([]string{"COUNT(*)"}).AddRow(uint64(10)))
 
 	columnsSelect = []string{}
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT  FROM subscription LIMIT $1 OFFSET $2")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT  FROM subscription WHERE deleted_at IS NULL LIMIT $1 OFFSET $2")).
 		WithArgs(offset, limit).
 		WillReturnError(errors.New("build query"))
 
@@ -381,7 +372,7 @@ func TestUser_List_ErrorGetQuery(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
 	qc := entities.QueryCriteria{
@@ -391,12 +382,12 @@ func TestUser_List_ErrorGetQuery(t *testing.T) {
 	limit := qc.Pagination.Limit
 	offset := (qc.Pagination.Page - 1) * limit
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM subscription")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM subscription WHERE deleted_at IS NULL")).
 		WithoutArgs().
 		WillReturnRows(mock.NewRows([]string{"COUNT(*)"}).AddRow(uint64(10)))
 
-	columnsSelect = []string{"id", "service_name", "user_id", "price", "start_date", "end_date"}
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf("SELECT id, service_name, user_id, price, start_date, end_date FROM subscription LIMIT %d OFFSET %d", limit, offset))).
+	columnsSelect = []string{"id", "service_name", "user_id", "price", "currency", "billing_period", "billing_interval", "start_date", "end_date", "trial_end_date", "status", "status_changed_at", "deleted_at", "version", "created_at", "updated_at", "service_id", "category", columnTags}
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf("SELECT id, service_name, user_id, price, currency, billing_period, billing_interval, start_date, end_date, trial_end_date, status, status_changed_at, deleted_at, version, created_at, updated_at, service_id, category, "+tagsColumn+" FROM subscription WHERE deleted_at IS NULL LIMIT %d OFFSET %d", limit, offset))).
 		WithoutArgs().
 		WillReturnError(sql.ErrNoRows)
 
@@ -416,7 +407,7 @@ func TestUser_List_ErrorScanQuery(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
 	qc := entities.QueryCriteria{
@@ -426,14 +417,14 @@ func TestUser_List_ErrorScanQuery(t *testing.T) {
 	limit := qc.Pagination.Limit
 	offset := (qc.Pagination.Page - 1) * limit
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM subscription")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM subscription WHERE deleted_at IS NULL")).
 		WithoutArgs().
 		WillReturnRows(mock.NewRows([]string{"COUNT(*)"}).AddRow(uint64(10)))
 
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf("SELECT id, service_name, user_id, price, start_date, end_date FROM subscription LIMIT %d OFFSET %d", limit, offset))).
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf("SELECT id, service_name, user_id, price, currency, billing_period, billing_interval, start_date, end_date, trial_end_date, status, status_changed_at, deleted_at, version, created_at, updated_at, service_id, category, "+tagsColumn+" FROM subscription WHERE deleted_at IS NULL LIMIT %d OFFSET %d", limit, offset))).
 		WithoutArgs().
-		WillReturnRows(mock.NewRows([]string{"id", "service_name", "user_id", "price", "start_date", "end_date"}).
-			AddRow("", subTest.ServiceName, subTest.UserId, subTest.Price, subTest.StartDate, subTest.EndDate),
+		WillReturnRows(mock.NewRows([]string{"id", "service_name", "user_id", "price", "currency", "billing_period", "billing_interval", "start_date", "end_date", "trial_end_date", "status", "status_changed_at"}).
+			AddRow("", subTest.ServiceName, subTest.UserId, subTest.Price, subTest.Currency, subTest.BillingPeriod, subTest.BillingInterval, subTest.StartDate, subTest.EndDate, subTest.TrialEndDate, subTest.Status, subTest.StatusChangedAt),
 		)
 
 	respSubs, err := repo.List(ctx, qc)
@@ -452,7 +443,7 @@ func TestUser_List_ErrorIterationQuery(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
 	qc := entities.QueryCriteria{
@@ -462,16 +453,16 @@ func TestUser_List_ErrorIterationQuery(t *testing.T) {
 	limit := qc.Pagination.Limit
 	offset := (qc.Pagination.Page - 1) * limit
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM subscription")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM subscription WHERE deleted_at IS NULL")).
 		WithoutArgs().
 		WillReturnRows(mock.NewRows([]string{"COUNT(*)"}).AddRow(uint64(10)))
 
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf("SELECT id, service_name, user_id, price, start_date, end_date FROM subscription LIMIT %d OFFSET %d", limit, offset))).
-	WithoutArgs().
-	WillReturnRows(mock.NewRows([]string{"id", "service_name", "user_id", "price", "start_date", "end_date"}).
-		AddRow(subTest.ID, subTest.ServiceName, subTest.UserId, subTest.Price, subTest.StartDate, subTest.EndDate).
-		RowError(0, errors.New("network error")),
-	)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf("SELECT id, service_name, user_id, price, currency, billing_period, billing_interval, start_date, end_date, trial_end_date, status, status_changed_at, deleted_at, version, created_at, updated_at, service_id, category, "+tagsColumn+" FROM subscription WHERE deleted_at IS NULL LIMIT %d OFFSET %d", limit, offset))).
+		WithoutArgs().
+		WillReturnRows(mock.NewRows([]string{"id", "service_name", "user_id", "price", "currency", "billing_period", "billing_interval", "start_date", "end_date", "trial_end_date", "status", "status_changed_at"}).
+			AddRow(subTest.ID, subTest.ServiceName, subTest.UserId, subTest.Price, subTest.Currency, subTest.BillingPeriod, subTest.BillingInterval, subTest.StartDate, subTest.EndDate, subTest.TrialEndDate, subTest.Status, subTest.StatusChangedAt).
+			RowError(0, errors.New("network error")),
+		)
 
 	respSubs, err := repo.List(ctx, qc)
 
@@ -489,7 +480,7 @@ func TestUser_List_Success(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
 	qc := entities.QueryCriteria{
@@ -499,28 +490,173 @@ func TestUser_List_Success(t *testing.T) {
 	limit := qc.Pagination.Limit
 	offset := (qc.Pagination.Page - 1) * limit
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM subscription")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM subscription WHERE deleted_at IS NULL")).
 		WithoutArgs().
 		WillReturnRows(mock.NewRows([]string{"COUNT(*)"}).AddRow(uint64(3)))
 
-	//totalCount >
-	limit--
-	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf("SELECT id, service_name, user_id, price, start_date, end_date FROM subscription LIMIT %d OFFSET %d", limit, offset))).
-	WithoutArgs().
-	WillReturnRows(mock.NewRows([]string{"id", "service_name", "user_id", "price", "start_date", "end_date"}).
-		AddRow(subTest.ID, subTest.ServiceName, subTest.UserId, subTest.Price, subTest.StartDate, subTest.EndDate),
-	)
+	mock.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf("SELECT id, service_name, user_id, price, currency, billing_period, billing_interval, start_date, end_date, trial_end_date, status, status_changed_at, deleted_at, version, created_at, updated_at, service_id, category, "+tagsColumn+" FROM subscription WHERE deleted_at IS NULL LIMIT %d OFFSET %d", limit, offset))).
+		WithoutArgs().
+		WillReturnRows(mock.NewRows([]string{"id", "service_name", "user_id", "price", "currency", "billing_period", "billing_interval", "start_date", "end_date", "trial_end_date", "status", "status_changed_at"}).
+			AddRow(subTest.ID, subTest.ServiceName, subTest.UserId, subTest.Price, subTest.Currency, subTest.BillingPeriod, subTest.BillingInterval, subTest.StartDate, subTest.EndDate, subTest.TrialEndDate, subTest.Status, subTest.StatusChangedAt),
+		)
+
+	respSubs, err := repo.List(ctx, qc)
+
+	require.NoError(t, err)
+	require.Equal(t, 1, len(respSubs.Data))
+	assert.Equal(t, uint64(2), respSubs.Info.Page)
+	assert.Equal(t, uint16(2), respSubs.Info.PageSize)
+}
+
+func TestUser_List_PagePastEnd(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	qc := entities.QueryCriteria{
+		Pagination: entities.PaginationParams{Page: uint64(5), Limit: uint64(2)},
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM subscription WHERE deleted_at IS NULL")).
+		WithoutArgs().
+		WillReturnRows(mock.NewRows([]string{"COUNT(*)"}).AddRow(uint64(4)))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM subscription WHERE deleted_at IS NULL LIMIT 2 OFFSET 8")).
+		WithoutArgs().
+		WillReturnRows(mock.NewRows([]string{"id"}))
+
+	respSubs, err := repo.List(ctx, qc)
+
+	require.NoError(t, err)
+	assert.Empty(t, respSubs.Data)
+	assert.Equal(t, uint64(5), respSubs.Info.Page)
+	assert.Equal(t, uint16(2), respSubs.Info.PageSize)
+	assert.Equal(t, uint32(2), respSubs.Info.TotalPages)
+	assert.False(t, respSubs.Info.HasNext)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_List_Keyset(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	sort := []entities.SortParams{{SortBy: entities.SortTypeServiceName, SortOrder: entities.SortOrderTypeAsc}}
+	qc := entities.QueryCriteria{
+		Pagination: entities.PaginationParams{
+			Limit:        2,
+			Cursor:       &entities.Cursor{Sort: sort, Values: []string{"Netflix"}, ID: 7},
+			WithoutTotal: true,
+		},
+		Sort: []entities.SortParams{{SortBy: entities.SortTypeID, SortOrder: entities.SortOrderTypeDesc}},
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, service_name, user_id, price, currency, billing_period, billing_interval, start_date, end_date, trial_end_date, status, status_changed_at, deleted_at, version, created_at, updated_at, service_id, category, "+tagsColumn+" FROM subscription "+
+		"WHERE deleted_at IS NULL AND (service_name, id) > (CAST($1 AS text), $2) ORDER BY service_name ASC, id ASC LIMIT 3")).
+		WithArgs("Netflix", int64(7)).
+		WillReturnRows(mock.NewRows([]string{"id", "service_name"}).
+			AddRow(3, "Netflix").
+			AddRow(8, "Spotify").
+			AddRow(9, "Youtube"),
+		)
+
+	respSubs, err := repo.List(ctx, qc)
+
+	require.NoError(t, err)
+	require.Equal(t, 2, len(respSubs.Data))
+	assert.False(t, respSubs.Info.WithTotal)
+	assert.True(t, respSubs.Info.HasNext)
+	assert.Equal(t, &entities.Cursor{Sort: sort, Values: []string{"Spotify"}, ID: 8}, respSubs.Info.Next)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_List_Search(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	sort := []entities.SortParams{{SortBy: entities.SortTypeRelevance, SortOrder: entities.SortOrderTypeDesc}}
+	qc := entities.QueryCriteria{
+		Filter:     entities.FilterParams{Search: "Netf"},
+		Pagination: entities.PaginationParams{Page: 1, Limit: 1, WithoutTotal: true},
+		Sort:       sort,
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, service_name, user_id, price, currency, billing_period, billing_interval, start_date, end_date, trial_end_date, status, status_changed_at, deleted_at, version, created_at, updated_at, service_id, category, "+tagsColumn+", "+
+		"word_similarity($1, service_name_normalized) AS rank FROM subscription "+
+		"WHERE (service_name_normalized LIKE $2 OR $3 <% service_name_normalized) AND deleted_at IS NULL "+
+		"ORDER BY word_similarity($4, service_name_normalized) DESC, id DESC LIMIT 2 OFFSET 0")).
+		WithArgs("netf", "%netf%", "netf", "netf").
+		WillReturnRows(mock.NewRows([]string{"id", "service_name", "rank"}).
+			AddRow(3, "Netflix", 0.8).
+			AddRow(5, "Netflix Premium", 0.8),
+		)
+
+	respSubs, err := repo.List(ctx, qc)
+
+	require.NoError(t, err)
+	require.Equal(t, 1, len(respSubs.Data))
+	assert.Equal(t, float32(0.8), respSubs.Data[0].Rank)
+	assert.Equal(t, &entities.Cursor{Sort: sort, Values: []string{"0.8"}, ID: 3}, respSubs.Info.Next)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_List_WithoutTotalLastPage(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	qc := entities.QueryCriteria{
+		Pagination: entities.PaginationParams{Page: 3, Limit: 2, WithoutTotal: true},
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, service_name, user_id, price, currency, billing_period, billing_interval, start_date, end_date, trial_end_date, status, status_changed_at, deleted_at, version, created_at, updated_at, service_id, category, " + tagsColumn + " FROM subscription WHERE deleted_at IS NULL LIMIT 3 OFFSET 4")).
+		WithoutArgs().
+		WillReturnRows(mock.NewRows([]string{"id", "service_name"}).AddRow(5, "Netflix"))
 
 	respSubs, err := repo.List(ctx, qc)
 
 	require.NoError(t, err)
 	require.Equal(t, 1, len(respSubs.Data))
+	assert.Equal(t, uint64(3), respSubs.Info.Page)
+	assert.False(t, respSubs.Info.HasNext)
+	assert.Nil(t, respSubs.Info.Next)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 var fieldsUpdate = map[string]any{
 	"service_name": "Test service",
 }
 
+var serviceNormalized = "test service"
+
 func TestUser_Update_ValidateFalse(t *testing.T) {
 	mockDB, mock, err := sqlmock.New()
 	require.NoError(t, err, "create mock")
@@ -530,19 +666,20 @@ func TestUser_Update_ValidateFalse(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
-	logger.EXPECT().Error(gomock.Any(),gomock.Any())
+	logger.EXPECT().Error(gomock.Any(), gomock.Any())
 
-	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET service_name = $1, updated_at = $2 WHERE id = $3")).
-		WithArgs(fieldsUpdate["service_name"], sqlmock.AnyArg(), subTest.ID).
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET service_id = (SELECT id FROM services WHERE name_normalized = $1), "+
+		"service_name = COALESCE((SELECT name FROM services WHERE name_normalized = $2), $3), updated_at = $4, version = version + 1 WHERE id = $5")).
+		WithArgs(serviceNormalized, serviceNormalized, fieldsUpdate["service_name"], sqlmock.AnyArg(), subTest.ID).
 		WillReturnError(sql.ErrNoRows)
 
 	fieldsUpdateIncorrect := map[string]any{
 		"service_name": uint16(1000),
 	}
-	err = repo.Update(ctx, subTest.ID, fieldsUpdateIncorrect)
+	err = repo.Update(ctx, subTest.ID, fieldsUpdateIncorrect, 0)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "subscriptionRepositories.Update: validate")
@@ -557,15 +694,15 @@ func TestUser_Update_ErrorBuildQuery(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
-	mock.ExpectExec(regexp.QuoteMeta("UPDATE  SET service_name = $1, updated_at = $2 WHERE id = $3")).
-		WithArgs(fieldsUpdate["service_name"], sqlmock.AnyArg(), subTest.ID).
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE  SET service_name = $1, updated_at = $2, version = version + 1 WHERE id = $3")).
+		WithArgs(serviceNormalized, serviceNormalized, fieldsUpdate["service_name"], sqlmock.AnyArg(), subTest.ID).
 		WillReturnError(sql.ErrNoRows)
 
 	table = ""
-	err = repo.Update(ctx, subTest.ID, fieldsUpdate)
+	err = repo.Update(ctx, subTest.ID, fieldsUpdate, 0)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "subscriptionRepositories.Update: build query")
@@ -580,15 +717,16 @@ func TestUser_Update_ErrorExecQuery(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
-	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET service_name = $1, updated_at = $2 WHERE id = $3")).
-		WithArgs(fieldsUpdate["service_name"], sqlmock.AnyArg(), subTest.ID).
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET service_id = (SELECT id FROM services WHERE name_normalized = $1), "+
+		"service_name = COALESCE((SELECT name FROM services WHERE name_normalized = $2), $3), updated_at = $4, version = version + 1 WHERE id = $5")).
+		WithArgs(serviceNormalized, serviceNormalized, fieldsUpdate["service_name"], sqlmock.AnyArg(), subTest.ID).
 		WillReturnError(sql.ErrNoRows)
 
 	table = "subscription"
-	err = repo.Update(ctx, subTest.ID, fieldsUpdate)
+	err = repo.Update(ctx, subTest.ID, fieldsUpdate, 0)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "subscriptionRepositories.Update: exec query")
@@ -603,14 +741,15 @@ func TestUser_Update_ErrorAffectedRows(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
-	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET service_name = $1, updated_at = $2 WHERE id = $3")).
-		WithArgs(fieldsUpdate["service_name"], sqlmock.AnyArg(), subTest.ID).
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET service_id = (SELECT id FROM services WHERE name_normalized = $1), "+
+		"service_name = COALESCE((SELECT name FROM services WHERE name_normalized = $2), $3), updated_at = $4, version = version + 1 WHERE id = $5")).
+		WithArgs(serviceNormalized, serviceNormalized, fieldsUpdate["service_name"], sqlmock.AnyArg(), subTest.ID).
 		WillReturnResult(&ErrorResult{})
 
-	err = repo.Update(ctx, subTest.ID, fieldsUpdate)
+	err = repo.Update(ctx, subTest.ID, fieldsUpdate, 0)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "subscriptionRepositories.Update: get affected rows")
@@ -625,20 +764,45 @@ func TestUser_Update_ErrorNotEquilRowsAffected(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
-	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET service_name = $1, updated_at = $2 WHERE id = $3")).
-		WithArgs(fieldsUpdate["service_name"], sqlmock.AnyArg(), subTest.ID).
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET service_id = (SELECT id FROM services WHERE name_normalized = $1), "+
+		"service_name = COALESCE((SELECT name FROM services WHERE name_normalized = $2), $3), updated_at = $4, version = version + 1 WHERE id = $5")).
+		WithArgs(serviceNormalized, serviceNormalized, fieldsUpdate["service_name"], sqlmock.AnyArg(), subTest.ID).
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
-	err = repo.Update(ctx, subTest.ID, fieldsUpdate)
+	err = repo.Update(ctx, subTest.ID, fieldsUpdate, 0)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "subscriptionRepositories.Update: expected rowsAffected")
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestUser_Update_ErrorVersionChanged(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET service_id = (SELECT id FROM services WHERE name_normalized = $1), "+
+		"service_name = COALESCE((SELECT name FROM services WHERE name_normalized = $2), $3), updated_at = $4, version = version + 1 WHERE id = $5 AND version = $6")).
+		WithArgs(serviceNormalized, serviceNormalized, fieldsUpdate["service_name"], sqlmock.AnyArg(), subTest.ID, int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Update(ctx, subTest.ID, fieldsUpdate, 2)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestUser_Update_Success(t *testing.T) {
 	mockDB, mock, err := sqlmock.New()
 	require.NoError(t, err, "create mock")
@@ -648,14 +812,15 @@ func TestUser_Update_Success(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
-	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET service_name = $1, updated_at = $2 WHERE id = $3")).
-		WithArgs(fieldsUpdate["service_name"], sqlmock.AnyArg(), subTest.ID).
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET service_id = (SELECT id FROM services WHERE name_normalized = $1), "+
+		"service_name = COALESCE((SELECT name FROM services WHERE name_normalized = $2), $3), updated_at = $4, version = version + 1 WHERE id = $5")).
+		WithArgs(serviceNormalized, serviceNormalized, fieldsUpdate["service_name"], sqlmock.AnyArg(), subTest.ID).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
-	err = repo.Update(ctx, subTest.ID, fieldsUpdate)
+	err = repo.Update(ctx, subTest.ID, fieldsUpdate, 0)
 
 	require.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -670,15 +835,15 @@ func TestUser_Delete_ErrorBuildQuery(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
-	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM  WHERE id = $1")).
-		WithArgs(subTest.ID).
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE  SET deleted_at = $1, updated_at = $2, version = version + 1 WHERE deleted_at IS NULL AND id = $3")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), subTest.ID).
 		WillReturnError(sql.ErrNoRows)
 
 	table = ""
-	err = repo.Delete(ctx, subTest.ID)
+	err = repo.Delete(ctx, subTest.ID, 0)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "subscriptionRepositories.Delete: build query")
@@ -693,15 +858,15 @@ func TestUser_Delete_ErrorExecQuery(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
-	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM subscription WHERE id = $1")).
-		WithArgs(subTest.ID).
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET deleted_at = $1, updated_at = $2, version = version + 1 WHERE deleted_at IS NULL AND id = $3")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), subTest.ID).
 		WillReturnError(sql.ErrNoRows)
 
 	table = "subscription"
-	err = repo.Delete(ctx, subTest.ID)
+	err = repo.Delete(ctx, subTest.ID, 0)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "subscriptionRepositories.Delete: exec query")
@@ -717,14 +882,14 @@ func TestUser_Delete_ErrorAffectedRows(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
-	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM subscription WHERE id = $1")).
-		WithArgs(subTest.ID).
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET deleted_at = $1, updated_at = $2, version = version + 1 WHERE deleted_at IS NULL AND id = $3")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), subTest.ID).
 		WillReturnResult(&ErrorResult{})
 
-	err = repo.Delete(ctx, subTest.ID)
+	err = repo.Delete(ctx, subTest.ID, 0)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "subscriptionRepositories.Delete: get affected rows")
@@ -740,20 +905,43 @@ func TestUser_Delete_ErrorNotEquilRowsAffected(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
-	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM subscription WHERE id = $1")).
-		WithArgs(subTest.ID).
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET deleted_at = $1, updated_at = $2, version = version + 1 WHERE deleted_at IS NULL AND id = $3")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), subTest.ID).
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
-	err = repo.Delete(ctx, subTest.ID)
+	err = repo.Delete(ctx, subTest.ID, 0)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "subscriptionRepositories.Delete: expected rowsAffected")
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestUser_Delete_ErrorVersionChanged(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET deleted_at = $1, updated_at = $2, version = version + 1 WHERE deleted_at IS NULL AND id = $3 AND version = $4")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), subTest.ID, int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Delete(ctx, subTest.ID, 2)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestUser_Delete_Success(t *testing.T) {
 	mockDB, mock, err := sqlmock.New()
 	require.NoError(t, err, "create mock")
@@ -763,18 +951,35 @@ func TestUser_Delete_Success(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
-	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM subscription WHERE id = $1")).
-		WithArgs(subTest.ID).
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET deleted_at = $1, updated_at = $2, version = version + 1 WHERE deleted_at IS NULL AND id = $3")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), subTest.ID).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
-	err = repo.Delete(ctx, subTest.ID)
+	err = repo.Delete(ctx, subTest.ID, 0)
 	require.Nil(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+const costPriceMonthly = "CASE s.billing_period WHEN 'weekly' THEN COALESCE(p.price, s.price) * 52.0 / 12 WHEN 'quarterly' THEN COALESCE(p.price, s.price) / 3.0 " +
+	"WHEN 'yearly' THEN COALESCE(p.price, s.price) / 12.0 WHEN 'custom' THEN COALESCE(p.price, s.price)::numeric / s.billing_interval ELSE COALESCE(p.price, s.price) END"
+
+const costPriceJoin = "LEFT JOIN LATERAL (SELECT ph.price FROM subscription_price_history AS ph WHERE ph.subscription_id = s.id AND ph.effective_from <= m.month " +
+	"ORDER BY ph.effective_from DESC LIMIT 1) AS p ON true "
+
+const costConditions = "WHERE (s.trial_end_date IS NULL OR m.month >= date_trunc('month', s.trial_end_date::timestamp)) AND NOT EXISTS (SELECT 1 FROM subscription_status_history AS h WHERE h.subscription_id = s.id AND h.status = 'paused' " +
+	"AND h.started_at <= m.month AND (h.ended_at IS NULL OR h.ended_at >= m.month + interval '1 month')) AND s.deleted_at IS NULL "
+
+var (
+	costColumns = []string{"s.id", "s.service_name", "s.user_id", "s.price", "s.currency", "s.billing_period", "s.billing_interval", "COUNT(*) AS months", "ROUND(SUM(" + costPriceMonthly + "))::bigint AS cost"}
+	costQuery   = "SELECT s.id, s.service_name, s.user_id, s.price, s.currency, s.billing_period, s.billing_interval, COUNT(*) AS months, ROUND(SUM(" + costPriceMonthly + "))::bigint AS cost FROM subscription AS s " +
+		"CROSS JOIN LATERAL generate_series(date_trunc('month', s.start_date::timestamp), " +
+		"LEAST(date_trunc('month', COALESCE(s.end_date::timestamp, date_trunc('month', CURRENT_DATE::timestamp))), date_trunc('month', CURRENT_DATE::timestamp)), " +
+		"interval '1 month') AS m(month) " + costPriceJoin + costConditions + "GROUP BY s.id ORDER BY s.id"
+)
+
 func TestUser_GetCost_ErrorBuildQuery(t *testing.T) {
 	mockDB, mock, err := sqlmock.New()
 	require.NoError(t, err, "create mock")
@@ -784,22 +989,22 @@ func TestUser_GetCost_ErrorBuildQuery(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT  FROM subscription")).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT  FROM subscription AS s")).
 		WithoutArgs().
 		WillReturnError(errors.New("build query"))
 
 	columnsCost = []string{}
-	cost, err := repo.GetCost(ctx, entities.FilterParams{})
+	items, err := repo.GetCost(ctx, entities.FilterParams{})
 
 	require.Error(t, err)
-	require.Equal(t, int64(0), cost)
+	require.Nil(t, items)
 	assert.Contains(t, err.Error(), "subscriptionRepositories.GetCost: build query")
 }
 
-func TestUser_GetCost_ErrorScan(t *testing.T) {
+func TestUser_GetCost_ErrorGetQuery(t *testing.T) {
 	mockDB, mock, err := sqlmock.New()
 	require.NoError(t, err, "create mock")
 	defer mockDB.Close()
@@ -808,19 +1013,44 @@ func TestUser_GetCost_ErrorScan(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
-	params := entities.FilterParams{}
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(SUM(price) FROM subscription")).
+	mock.ExpectQuery(regexp.QuoteMeta(costQuery)).
 		WithoutArgs().
 		WillReturnError(sql.ErrNoRows)
 
-	columnsCost = []string{"COALESCE(SUM(price)"}
-	cost, err := repo.GetCost(ctx, params)
+	columnsCost = costColumns
+	items, err := repo.GetCost(ctx, entities.FilterParams{})
+
+	require.Error(t, err)
+	require.Nil(t, items)
+	assert.Contains(t, err.Error(), "subscriptionRepositories.GetCost: get query")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_GetCost_ErrorScan(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectQuery(regexp.QuoteMeta(costQuery)).
+		WithoutArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "service_name", "user_id", "price", "currency", "billing_period", "billing_interval", "months", "cost"}).
+			AddRow("", subTest.ServiceName, subTest.UserId, subTest.Price, subTest.Currency, subTest.BillingPeriod, subTest.BillingInterval, int64(12), int64(1200)),
+		)
+
+	items, err := repo.GetCost(ctx, entities.FilterParams{})
 
 	require.Error(t, err)
-	require.Equal(t, int64(0), cost)
+	require.Nil(t, items)
 	assert.Contains(t, err.Error(), "subscriptionRepositories.GetCost: scan query")
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
@@ -834,20 +1064,970 @@ func TestUser_GetCost_Success(t *testing.T) {
 	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
 	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
 	logger := mocks.NewMockLogger(ctrl)
-	repo := NewUserRepository(sqlxDB, builder, logger)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
 	ctx := context.Background()
 
-	params := entities.FilterParams{}
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(SUM(price) FROM subscription")).
+	mock.ExpectQuery(regexp.QuoteMeta(costQuery)).
 		WithoutArgs().
-		WillReturnRows(
-			sqlmock.NewRows([]string{"COALESCE(SUM(price)"}).
-				AddRow(int64(124)),
+		WillReturnRows(sqlmock.NewRows([]string{"id", "service_name", "user_id", "price", "currency", "billing_period", "billing_interval", "months", "cost"}).
+			AddRow(int64(1), subTest.ServiceName, subTest.UserId, subTest.Price, subTest.Currency, subTest.BillingPeriod, subTest.BillingInterval, int64(12), int64(1200)).
+			AddRow(int64(2), "yearly service", subTest.UserId, uint32(1200), "EUR", entities.BillingPeriodYearly, uint16(1), int64(3), int64(300)),
 		)
 
-	cost, err := repo.GetCost(ctx, params)
+	items, err := repo.GetCost(ctx, entities.FilterParams{})
 
-	require.Nil(t, err)
-	require.Equal(t, int64(124), cost)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(items))
+	assert.Equal(t, int64(12), items[0].Months)
+	assert.Equal(t, int64(1200), items[0].Cost)
+	assert.Equal(t, entities.BillingPeriodYearly, items[1].BillingPeriod)
+	assert.Equal(t, int64(300), items[1].Cost)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+var costMonthlyQuery = "SELECT m.month, s.service_name, s.currency, COUNT(DISTINCT s.id) AS subscriptions, ROUND(SUM(" + costPriceMonthly + "))::bigint AS cost FROM subscription AS s " +
+	"CROSS JOIN LATERAL generate_series(date_trunc('month', s.start_date::timestamp), " +
+	"LEAST(date_trunc('month', COALESCE(s.end_date::timestamp, date_trunc('month', CURRENT_DATE::timestamp))), date_trunc('month', CURRENT_DATE::timestamp)), " +
+	"interval '1 month') AS m(month) " + costPriceJoin + costConditions + "GROUP BY m.month, s.service_name, s.currency ORDER BY m.month, s.service_name, s.currency"
+
+func TestUser_GetCostMonthly_ErrorBuildQuery(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	columns := columnsCostMonthly
+	columnsCostMonthly = []string{}
+	defer func() { columnsCostMonthly = columns }()
+
+	services, err := repo.GetCostMonthly(ctx, entities.FilterParams{})
+
+	require.Error(t, err)
+	require.Nil(t, services)
+	assert.Contains(t, err.Error(), "subscriptionRepositories.GetCostMonthly: build query")
+}
+
+func TestUser_GetCostMonthly_ErrorGetQuery(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectQuery(regexp.QuoteMeta(costMonthlyQuery)).
+		WithoutArgs().
+		WillReturnError(sql.ErrConnDone)
+
+	services, err := repo.GetCostMonthly(ctx, entities.FilterParams{})
+
+	require.Error(t, err)
+	require.Nil(t, services)
+	assert.Contains(t, err.Error(), "subscriptionRepositories.GetCostMonthly: get query")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_GetCostMonthly_Success(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	month := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(costMonthlyQuery)).
+		WithoutArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"month", "service_name", "currency", "subscriptions", "cost"}).
+			AddRow(month, "A", "RUB", int64(2), int64(200)).
+			AddRow(month, "B", "USD", int64(1), int64(50)),
+		)
+
+	services, err := repo.GetCostMonthly(ctx, entities.FilterParams{})
+
+	require.NoError(t, err)
+	require.Equal(t, 2, len(services))
+	assert.Equal(t, month, services[0].Month)
+	assert.Equal(t, int64(2), services[0].Subscriptions)
+	assert.Equal(t, "USD", services[1].Currency)
+	assert.Equal(t, int64(50), services[1].Cost)
 	assert.NoError(t, mock.ExpectationsWereMet())
-}
\ No newline at end of file
+}
+
+var costGroupedQuery = "SELECT COUNT(DISTINCT s.id) AS subscriptions, ROUND(SUM(" + costPriceMonthly + "))::bigint AS cost, s.service_name, s.currency FROM subscription AS s " +
+	"CROSS JOIN LATERAL generate_series(date_trunc('month', s.start_date::timestamp), " +
+	"LEAST(date_trunc('month', COALESCE(s.end_date::timestamp, date_trunc('month', CURRENT_DATE::timestamp))), date_trunc('month', CURRENT_DATE::timestamp)), " +
+	"interval '1 month') AS m(month) " + costPriceJoin + costConditions + "GROUP BY s.service_name, s.currency ORDER BY cost DESC, s.service_name, s.currency"
+
+func TestUser_GetCostGrouped_ErrorGetQuery(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectQuery(regexp.QuoteMeta(costGroupedQuery)).
+		WithoutArgs().
+		WillReturnError(sql.ErrConnDone)
+
+	groups, err := repo.GetCostGrouped(ctx, entities.FilterParams{}, []entities.CostGroupType{entities.CostGroupTypeServiceName})
+
+	require.Error(t, err)
+	require.Nil(t, groups)
+	assert.Contains(t, err.Error(), "subscriptionRepositories.GetCostGrouped: get query")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_GetCostGrouped_Success(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectQuery(regexp.QuoteMeta(costGroupedQuery)).
+		WithoutArgs().
+		WillReturnRows(sqlmock.NewRows([]string{"subscriptions", "cost", "service_name", "currency"}).
+			AddRow(int64(3), int64(900), "A", "RUB").
+			AddRow(int64(1), int64(100), "B", "RUB"),
+		)
+
+	groups, err := repo.GetCostGrouped(ctx, entities.FilterParams{}, []entities.CostGroupType{entities.CostGroupTypeServiceName})
+
+	require.NoError(t, err)
+	require.Equal(t, 2, len(groups))
+	assert.Equal(t, "A", groups[0].ServiceName)
+	assert.Equal(t, int64(900), groups[0].Cost)
+	assert.Equal(t, "RUB", groups[0].Currency)
+	assert.Equal(t, uuid.Nil, groups[0].UserId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_ChangeStatus_ErrorExecQuery(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+	at := time.Now()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET status = $1, status_changed_at = $2, updated_at = $3, version = version + 1 WHERE id = $4 AND deleted_at IS NULL")).
+		WithArgs(entities.SubscriptionStatusPaused, at, at, subTest.ID).
+		WillReturnError(sql.ErrConnDone)
+
+	err = repo.ChangeStatus(ctx, subTest.ID, entities.SubscriptionStatusPaused, at)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "subscriptionRepositories.ChangeStatus: exec query")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_ChangeStatus_ErrorNotFound(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+	at := time.Now()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET status = $1, status_changed_at = $2, updated_at = $3, version = version + 1 WHERE id = $4 AND deleted_at IS NULL")).
+		WithArgs(entities.SubscriptionStatusPaused, at, at, subTest.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.ChangeStatus(ctx, subTest.ID, entities.SubscriptionStatusPaused, at)
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_ChangeStatus_ErrorOpenPeriod(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+	at := time.Now()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET status = $1, status_changed_at = $2, updated_at = $3, version = version + 1 WHERE id = $4 AND deleted_at IS NULL")).
+		WithArgs(entities.SubscriptionStatusPaused, at, at, subTest.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription_status_history SET ended_at = $1 WHERE ended_at IS NULL AND subscription_id = $2")).
+		WithArgs(at, subTest.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO subscription_status_history (started_at,status,subscription_id) VALUES ($1,$2,$3)")).
+		WithArgs(at, entities.SubscriptionStatusPaused, subTest.ID).
+		WillReturnError(sql.ErrConnDone)
+
+	err = repo.ChangeStatus(ctx, subTest.ID, entities.SubscriptionStatusPaused, at)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "subscriptionRepositories.ChangeStatus: exec query open period")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_ChangeStatus_Success(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+	at := time.Now()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET status = $1, status_changed_at = $2, updated_at = $3, version = version + 1 WHERE id = $4 AND deleted_at IS NULL")).
+		WithArgs(entities.SubscriptionStatusPaused, at, at, subTest.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription_status_history SET ended_at = $1 WHERE ended_at IS NULL AND subscription_id = $2")).
+		WithArgs(at, subTest.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO subscription_status_history (started_at,status,subscription_id) VALUES ($1,$2,$3)")).
+		WithArgs(at, entities.SubscriptionStatusPaused, subTest.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.ChangeStatus(ctx, subTest.ID, entities.SubscriptionStatusPaused, at)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_GetStatusHistory_ErrorGetQuery(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, subscription_id, status, started_at, ended_at FROM subscription_status_history WHERE subscription_id = $1 ORDER BY started_at, id")).
+		WithArgs(subTest.ID).
+		WillReturnError(sql.ErrConnDone)
+
+	history, err := repo.GetStatusHistory(ctx, subTest.ID)
+
+	require.Error(t, err)
+	require.Nil(t, history)
+	assert.Contains(t, err.Error(), "subscriptionRepositories.GetStatusHistory: get query")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_GetStatusHistory_Success(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+	pausedAt := time.Date(2025, time.January, 31, 10, 0, 0, 0, time.UTC)
+	resumedAt := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, subscription_id, status, started_at, ended_at FROM subscription_status_history WHERE subscription_id = $1 ORDER BY started_at, id")).
+		WithArgs(subTest.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subscription_id", "status", "started_at", "ended_at"}).
+			AddRow(int64(1), subTest.ID, entities.SubscriptionStatusPaused, pausedAt, resumedAt).
+			AddRow(int64(2), subTest.ID, entities.SubscriptionStatusActive, resumedAt, nil),
+		)
+
+	history, err := repo.GetStatusHistory(ctx, subTest.ID)
+
+	require.NoError(t, err)
+	require.Equal(t, 2, len(history))
+	assert.Equal(t, entities.SubscriptionStatusPaused, history[0].Status)
+	assert.Equal(t, resumedAt, history[0].EndedAt.Time)
+	assert.False(t, history[1].EndedAt.Valid)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_ListTrialsEnding_ErrorGetQuery(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+	at := time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, service_name, user_id, price, currency, billing_period, billing_interval, start_date, end_date, trial_end_date, status, status_changed_at, deleted_at, version, created_at, updated_at, service_id, category, "+tagsColumn+" FROM subscription WHERE status = $1 AND trial_end_date > $2 AND deleted_at IS NULL ORDER BY trial_end_date, id")).
+		WithArgs(entities.SubscriptionStatusTrial, at).
+		WillReturnError(sql.ErrConnDone)
+
+	subs, err := repo.ListTrialsEnding(ctx, entities.DateRange{}, at)
+
+	require.Error(t, err)
+	require.Nil(t, subs)
+	assert.Contains(t, err.Error(), "subscriptionRepositories.ListTrialsEnding: get query")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_ListTrialsEnding_Success(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+	from := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	trialEnd := from.AddDate(0, 0, 3)
+	at := from.Add(12 * time.Hour)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, service_name, user_id, price, currency, billing_period, billing_interval, start_date, end_date, trial_end_date, status, status_changed_at, deleted_at, version, created_at, updated_at, service_id, category, "+tagsColumn+" FROM subscription "+
+		"WHERE status = $1 AND trial_end_date > $2 AND deleted_at IS NULL AND trial_end_date >= $3 AND trial_end_date <= $4 ORDER BY trial_end_date, id")).
+		WithArgs(entities.SubscriptionStatusTrial, at, from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "service_name", "user_id", "price", "currency", "billing_period", "billing_interval", "start_date", "end_date", "trial_end_date", "status", "status_changed_at"}).
+			AddRow(subTest.ID, subTest.ServiceName, subTest.UserId, subTest.Price, subTest.Currency, subTest.BillingPeriod, subTest.BillingInterval, subTest.StartDate, subTest.EndDate, trialEnd, entities.SubscriptionStatusTrial, subTest.StatusChangedAt),
+		)
+
+	subs, err := repo.ListTrialsEnding(ctx, entities.DateRange{From: &from, To: &to}, at)
+
+	require.NoError(t, err)
+	require.Equal(t, 1, len(subs))
+	assert.Equal(t, entities.SubscriptionStatusTrial, subs[0].Status)
+	assert.Equal(t, trialEnd, subs[0].TrialEndDate.Time)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_AddPrice_ErrorExecQuery(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+	effectiveFrom := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO subscription_price_history (effective_from,price,subscription_id) VALUES ($1,$2,$3) "+
+		"ON CONFLICT (subscription_id, effective_from) DO UPDATE SET price = EXCLUDED.price, created_at = NOW()")).
+		WithArgs("2025-03-01", uint32(200), subTest.ID).
+		WillReturnError(sql.ErrConnDone)
+
+	err = repo.AddPrice(ctx, subTest.ID, 200, effectiveFrom)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "subscriptionRepositories.AddPrice: exec query")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_AddPrice_Success(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+	effectiveFrom := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO subscription_price_history (effective_from,price,subscription_id) VALUES ($1,$2,$3) "+
+		"ON CONFLICT (subscription_id, effective_from) DO UPDATE SET price = EXCLUDED.price, created_at = NOW()")).
+		WithArgs("2025-03-01", uint32(200), subTest.ID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.AddPrice(ctx, subTest.ID, 200, effectiveFrom)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_DeletePricesUntil_Success(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+	until := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM subscription_price_history WHERE subscription_id = $1 AND effective_from <= $2")).
+		WithArgs(subTest.ID, "2025-03-01").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err = repo.DeletePricesUntil(ctx, subTest.ID, until)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_GetPriceHistory_ErrorScan(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT price, effective_from, created_at FROM subscription_price_history WHERE subscription_id = $1 ORDER BY effective_from")).
+		WithArgs(subTest.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"price", "effective_from", "created_at"}).
+			AddRow("abc", time.Now(), time.Now()),
+		)
+
+	prices, err := repo.GetPriceHistory(ctx, subTest.ID)
+
+	require.Error(t, err)
+	require.Nil(t, prices)
+	assert.Contains(t, err.Error(), "subscriptionRepositories.GetPriceHistory: scan query")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_GetPriceHistory_Success(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+	january := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	march := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT price, effective_from, created_at FROM subscription_price_history WHERE subscription_id = $1 ORDER BY effective_from")).
+		WithArgs(subTest.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"price", "effective_from", "created_at"}).
+			AddRow(uint32(100), january, march).
+			AddRow(uint32(200), march, march),
+		)
+
+	prices, err := repo.GetPriceHistory(ctx, subTest.ID)
+
+	require.NoError(t, err)
+	require.Equal(t, []entities.PriceChange{
+		{Price: 100, EffectiveFrom: january, CreatedAt: march},
+		{Price: 200, EffectiveFrom: march, CreatedAt: march},
+	}, prices)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_GetByID_IncludeDeleted(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	deletedAt := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, service_name, user_id, price, currency, billing_period, billing_interval, start_date, end_date, trial_end_date, status, status_changed_at, deleted_at, version, created_at, updated_at, service_id, category, " + tagsColumn + " FROM subscription WHERE id = $1")).
+		WithArgs(subTest.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "service_name", "deleted_at"}).AddRow(subTest.ID, subTest.ServiceName, deletedAt))
+
+	sub, err := repo.GetByID(ctx, subTest.ID, true)
+
+	require.NoError(t, err)
+	require.Equal(t, sql.NullTime{Time: deletedAt, Valid: true}, sub.DeletedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_Restore_Success(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET deleted_at = $1, updated_at = $2, version = version + 1 WHERE id = $3 AND deleted_at IS NOT NULL")).
+		WithArgs(nil, sqlmock.AnyArg(), subTest.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.Restore(ctx, subTest.ID)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_Restore_ErrorNotFound(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET deleted_at = $1, updated_at = $2, version = version + 1 WHERE id = $3 AND deleted_at IS NOT NULL")).
+		WithArgs(nil, sqlmock.AnyArg(), subTest.ID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.Restore(ctx, subTest.ID)
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_Purge_Success(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	before := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM subscription WHERE deleted_at < $1")).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	purged, err := repo.Purge(ctx, before)
+
+	require.NoError(t, err)
+	require.Equal(t, int64(3), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_Purge_ErrorExecQuery(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM subscription WHERE deleted_at < $1")).
+		WillReturnError(errors.New("exec"))
+
+	purged, err := repo.Purge(ctx, time.Now())
+
+	require.Error(t, err)
+	require.Zero(t, purged)
+	assert.Contains(t, err.Error(), "subscriptionRepositories.Purge: exec query")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_Export_Success(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+	params := entities.QueryCriteria{
+		Filter: entities.FilterParams{UserId: subTest.UserId},
+		Sort:   []entities.SortParams{{SortBy: entities.SortTypeID, SortOrder: entities.SortOrderTypeAsc}},
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta("DECLARE subscription_export NO SCROLL CURSOR FOR SELECT id, service_name, user_id, price, currency, billing_period, billing_interval, start_date, end_date, trial_end_date, status, status_changed_at, deleted_at, version, created_at, updated_at, service_id, category, " + tagsColumn + " FROM subscription " +
+		"WHERE user_id = $1 AND deleted_at IS NULL ORDER BY id ASC")).
+		WithArgs(subTest.UserId).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows := sqlmock.NewRows([]string{"id", "service_name", "user_id", "price"})
+	for i := 1; i <= exportFetchSize; i++ {
+		rows.AddRow(i, subTest.ServiceName, subTest.UserId, subTest.Price)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta("FETCH FORWARD 500 FROM subscription_export")).WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta("FETCH FORWARD 500 FROM subscription_export")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "service_name", "user_id", "price"}).
+			AddRow(exportFetchSize+1, subTest.ServiceName, subTest.UserId, subTest.Price))
+	mock.ExpectExec(regexp.QuoteMeta("CLOSE subscription_export")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	var ids []int64
+	err = repo.Export(ctx, params, func(sub entities.Subscription) error {
+		ids = append(ids, sub.ID)
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Len(t, ids, exportFetchSize+1)
+	assert.Equal(t, int64(exportFetchSize+1), ids[exportFetchSize])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_Export_ErrorDeclare(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("DECLARE subscription_export NO SCROLL CURSOR FOR SELECT")).
+		WillReturnError(sql.ErrConnDone)
+
+	err = repo.Export(ctx, entities.QueryCriteria{}, func(sub entities.Subscription) error {
+		return nil
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "subscriptionRepositories.Export: declare cursor")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_Export_ErrorWriteRow(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("DECLARE subscription_export NO SCROLL CURSOR FOR SELECT")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("FETCH FORWARD 500 FROM subscription_export")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+
+	calls := 0
+	err = repo.Export(ctx, entities.QueryCriteria{}, func(sub entities.Subscription) error {
+		calls++
+		return errors.New("broken pipe")
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Contains(t, err.Error(), "subscriptionRepositories.Export: write row")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_SetTags_Success(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET updated_at = $1, version = version + 1 WHERE id = $2")).
+		WithArgs(sqlmock.AnyArg(), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM subscription_tags WHERE subscription_id = $1")).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO tags (name) VALUES ($1),($2) ON CONFLICT (name) DO NOTHING")).
+		WithArgs("dev tools", "streaming").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO subscription_tags (subscription_id,tag_id) SELECT CAST($1 AS bigint), id FROM tags WHERE name IN ($2,$3)")).
+		WithArgs(int64(1), "dev tools", "streaming").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err = repo.SetTags(ctx, 1, []string{"Streaming", "dev  tools", "streaming"})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_SetTags_Clear(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET updated_at = $1, version = version + 1 WHERE id = $2")).
+		WithArgs(sqlmock.AnyArg(), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM subscription_tags WHERE subscription_id = $1")).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err = repo.SetTags(ctx, 1, nil)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_SetTags_ErrorExec(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET updated_at = $1, version = version + 1 WHERE id = $2")).
+		WithArgs(sqlmock.AnyArg(), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM subscription_tags")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO tags")).
+		WillReturnError(errors.New("exec"))
+
+	err = repo.SetTags(ctx, 1, []string{"cloud"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "subscriptionRepositories.SetTags: exec tags query")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_SetTags_ErrorVersion(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET")).
+		WillReturnError(errors.New("exec"))
+
+	err = repo.SetTags(ctx, 1, []string{"cloud"})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "subscriptionRepositories.SetTags: exec version query")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_GetCost_PerUser(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	columnsCost = costColumns
+	mock.ExpectQuery(regexp.QuoteMeta("AS m(month) CROSS JOIN LATERAL (SELECT COALESCE(") + ".*" +
+		regexp.QuoteMeta(" GROUP BY sh.user_id) AS p WHERE p.user_id = $1 ")).
+		WithArgs(subTest.UserId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "service_name", "user_id", "price", "currency", "billing_period", "billing_interval", "months", "cost"}).
+			AddRow(int64(1), subTest.ServiceName, subTest.UserId, uint32(300), subTest.Currency, subTest.BillingPeriod, subTest.BillingInterval, int64(2), int64(200)),
+		)
+
+	items, err := repo.GetCost(ctx, entities.FilterParams{UserId: subTest.UserId})
+
+	require.NoError(t, err)
+	require.Equal(t, 1, len(items))
+	assert.Equal(t, int64(200), items[0].Cost)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_GetMembers_Success(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	userId := uuid.New()
+	createdAt := time.Now().UTC()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT subscription_id, user_id, share_type, share, created_at FROM subscription_members WHERE subscription_id = $1 ORDER BY created_at, user_id")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"subscription_id", "user_id", "share_type", "share", "created_at"}).
+			AddRow(int64(1), subTest.UserId, "fixed", 150.0, createdAt).
+			AddRow(int64(1), userId, "percent", 50.0, createdAt),
+		)
+
+	members, err := repo.GetMembers(ctx, 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, []entities.Member{
+		{SubscriptionID: 1, UserId: subTest.UserId, ShareType: entities.ShareTypeFixed, Share: 150, CreatedAt: createdAt},
+		{SubscriptionID: 1, UserId: userId, ShareType: entities.ShareTypePercent, Share: 50, CreatedAt: createdAt},
+	}, members)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_SetMembers_Success(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	userId := uuid.New()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET updated_at = $1, version = version + 1 WHERE id = $2")).
+		WithArgs(sqlmock.AnyArg(), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM subscription_members WHERE subscription_id = $1")).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO subscription_members (subscription_id,user_id,share_type,share) VALUES ($1,$2,$3,$4),($5,$6,$7,$8)")).
+		WithArgs(int64(1), subTest.UserId, entities.ShareTypePercent, 60.0, int64(1), userId, entities.ShareTypePercent, 40.0).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err = repo.SetMembers(ctx, 1, []entities.Member{
+		{UserId: subTest.UserId, ShareType: entities.ShareTypePercent, Share: 60},
+		{UserId: userId, ShareType: entities.ShareTypePercent, Share: 40},
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_SetMembers_Clear(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET updated_at = $1, version = version + 1 WHERE id = $2")).
+		WithArgs(sqlmock.AnyArg(), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM subscription_members WHERE subscription_id = $1")).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	err = repo.SetMembers(ctx, 1, nil)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUser_SetMembers_UnknownUser(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	builder := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewSubscriptionRepository(sqlxDB, builder, logger)
+	ctx := context.Background()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE subscription SET updated_at = $1, version = version + 1 WHERE id = $2")).
+		WithArgs(sqlmock.AnyArg(), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM subscription_members")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO subscription_members")).
+		WillReturnError(&pgconn.PgError{Code: codeForeignKeyViolation})
+
+	err = repo.SetMembers(ctx, 1, []entities.Member{{UserId: uuid.New(), ShareType: entities.ShareTypePercent, Share: 100}})
+
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
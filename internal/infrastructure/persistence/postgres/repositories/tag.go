@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	errs "github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/interfaces/repositories"
+)
+
+type tagRepository struct {
+	querier sqlx.ExtContext
+	builder sq.StatementBuilderType
+
+	logger observability.Logger
+}
+
+// NewTagRepository - Constructor TagRepository
+func NewTagRepository(querier sqlx.ExtContext, builder sq.StatementBuilderType, logger observability.Logger) repositories.TagRepository {
+	return &tagRepository{
+		querier: querier,
+		builder: builder,
+
+		logger: logger,
+	}
+}
+
+// columnsTag - tag with the number of not deleted subscriptions labeled with it
+var columnsTag = []string{"t.id", "t.name", "t.created_at", "COUNT(s.id) AS subscriptions"}
+
+// conn - returns the transaction carried by the context or the repository querier
+func (r *tagRepository) conn(ctx context.Context) sqlx.ExtContext {
+	return querierFromContext(ctx, r.querier)
+}
+
+// selectTags - SelectBuilder of the tags counting their subscriptions
+func (r *tagRepository) selectTags() sq.SelectBuilder {
+	return r.builder.Select(columnsTag...).
+		From(tableTags + " AS t").
+		LeftJoin(tableSubscriptionTags + " AS st ON st.tag_id = t.id").
+		LeftJoin(table + " AS s ON s.id = st.subscription_id AND s.deleted_at IS NULL").
+		GroupBy("t.id")
+}
+
+// GetByID - Returns tag by ID
+func (r *tagRepository) GetByID(ctx context.Context, id int64) (*entities.Tag, error) {
+	query, args, err := r.selectTags().
+		Where(sq.Eq{"t.id": id}).
+		ToSql()
+	if err != nil {
+		return nil, errs.Wrap(err, "tagRepositories.GetByID: build query")
+	}
+
+	tag := &entities.Tag{}
+	err = r.conn(ctx).QueryRowxContext(ctx, query, args...).StructScan(tag)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrNotFound
+		}
+		return nil, errs.Wrap(err, "tagRepositories.GetByID: scan query")
+	}
+
+	return tag, nil
+}
+
+// List - Returns tags by filter ordered by name
+func (r *tagRepository) List(ctx context.Context, filter entities.TagFilter) ([]entities.Tag, error) {
+	query := r.selectTags()
+	if name := entities.NormalizeTag(filter.Name); name != "" {
+		query = query.Where(sq.Like{"t.name": "%" + escapeLike(name) + "%"})
+	}
+	query = query.OrderBy("t.name")
+
+	if filter.Pagination.Limit > 0 {
+		query = query.Limit(filter.Pagination.Limit)
+		if filter.Pagination.Page > 1 {
+			query = query.Offset((filter.Pagination.Page - 1) * filter.Pagination.Limit)
+		}
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, errs.Wrap(err, "tagRepositories.List: build query")
+	}
+
+	rows, err := r.conn(ctx).QueryxContext(ctx, sql, args...)
+	if err != nil {
+		return nil, errs.Wrap(err, "tagRepositories.List: get query")
+	}
+	defer rows.Close()
+
+	tags := make([]entities.Tag, 0)
+	for rows.Next() {
+		var tag entities.Tag
+		err = rows.StructScan(&tag)
+		if err != nil {
+			return nil, errs.Wrap(err, "tagRepositories.List: scan query")
+		}
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errs.Wrap(err, "tagRepositories.List: iteration rows")
+	}
+
+	return tags, nil
+}
+
+// Rename - Sets the normalized name of the tag, the subscriptions keep it.
+// ErrAlreadyExists is returned when the name is the name of another tag.
+func (r *tagRepository) Rename(ctx context.Context, id int64, name string) error {
+	name = entities.NormalizeTag(name)
+
+	query, args, err := r.builder.Update(tableTags).
+		Set("name", name).
+		Where(sq.Eq{"id": id}).
+		Where("NOT EXISTS (SELECT 1 FROM tags AS o WHERE o.name = ? AND o.id <> ?)", name, id).
+		ToSql()
+	if err != nil {
+		return errs.Wrap(err, "tagRepositories.Rename: build query")
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return errs.Wrap(err, "tagRepositories.Rename: exec query")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return errs.Wrap(err, "tagRepositories.Rename: get affected rows")
+	}
+
+	if rowsAffected == 0 {
+		return errs.Wrap(errs.ErrAlreadyExists, "tagRepositories.Rename: name taken")
+	}
+
+	return nil
+}
+
+// Delete - Deletes the tag with the id, it is removed from the subscriptions
+func (r *tagRepository) Delete(ctx context.Context, id int64) error {
+	query, args, err := r.builder.Delete(tableTags).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return errs.Wrap(err, "tagRepositories.Delete: build query")
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return errs.Wrap(err, "tagRepositories.Delete: exec query")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return errs.Wrap(err, "tagRepositories.Delete: get affected rows")
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/mocks"
+)
+
+const tagSelect = "SELECT t.id, t.name, t.created_at, COUNT(s.id) AS subscriptions FROM tags AS t " +
+	"LEFT JOIN subscription_tags AS st ON st.tag_id = t.id " +
+	"LEFT JOIN subscription AS s ON s.id = st.subscription_id AND s.deleted_at IS NULL"
+
+func newTagRepository(t *testing.T) (*tagRepository, sqlmock.Sqlmock) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	t.Cleanup(func() { mockDB.Close() })
+
+	ctrl := gomock.NewController(t)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewTagRepository(sqlx.NewDb(mockDB, "sqlmock"), sq.StatementBuilder.PlaceholderFormat(sq.Dollar), logger)
+
+	return repo.(*tagRepository), mock
+}
+
+func TestTag_GetByID_Success(t *testing.T) {
+	repo, mock := newTagRepository(t)
+	createdAt := time.Now().UTC()
+
+	mock.ExpectQuery(regexp.QuoteMeta(tagSelect + " WHERE t.id = $1 GROUP BY t.id")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at", "subscriptions"}).
+			AddRow(1, "streaming", createdAt, 3))
+
+	tag, err := repo.GetByID(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, &entities.Tag{ID: 1, Name: "streaming", CreatedAt: createdAt, Subscriptions: 3}, tag)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTag_GetByID_NotFound(t *testing.T) {
+	repo, mock := newTagRepository(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(tagSelect + " WHERE t.id = $1 GROUP BY t.id")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	tag, err := repo.GetByID(context.Background(), 1)
+
+	require.Nil(t, tag)
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTag_List_Success(t *testing.T) {
+	repo, mock := newTagRepository(t)
+	createdAt := time.Now().UTC()
+
+	mock.ExpectQuery(regexp.QuoteMeta(tagSelect + " WHERE t.name LIKE $1 GROUP BY t.id ORDER BY t.name LIMIT 10 OFFSET 10")).
+		WithArgs("%dev tool%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "created_at", "subscriptions"}).
+			AddRow(2, "dev tools", createdAt, 0))
+
+	tags, err := repo.List(context.Background(), entities.TagFilter{
+		Name:       " Dev  Tool",
+		Pagination: entities.PaginationParams{Page: 2, Limit: 10},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []entities.Tag{{ID: 2, Name: "dev tools", CreatedAt: createdAt}}, tags)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTag_List_ErrorGetQuery(t *testing.T) {
+	repo, mock := newTagRepository(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta(tagSelect + " GROUP BY t.id ORDER BY t.name")).
+		WillReturnError(errors.New("query"))
+
+	tags, err := repo.List(context.Background(), entities.TagFilter{})
+
+	require.Nil(t, tags)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tagRepositories.List: get query")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTag_Rename_Success(t *testing.T) {
+	repo, mock := newTagRepository(t)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE tags SET name = $1 WHERE id = $2 AND NOT EXISTS (SELECT 1 FROM tags AS o WHERE o.name = $3 AND o.id <> $4)")).
+		WithArgs("video", int64(1), "video", int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.Rename(context.Background(), 1, " Video")
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTag_Rename_NameTaken(t *testing.T) {
+	repo, mock := newTagRepository(t)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE tags SET name = $1 WHERE id = $2")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.Rename(context.Background(), 1, "video")
+
+	assert.ErrorIs(t, err, errors.ErrAlreadyExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTag_Delete_Success(t *testing.T) {
+	repo, mock := newTagRepository(t)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM tags WHERE id = $1")).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.Delete(context.Background(), 1)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTag_Delete_NotFound(t *testing.T) {
+	repo, mock := newTagRepository(t)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM tags WHERE id = $1")).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.Delete(context.Background(), 1)
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
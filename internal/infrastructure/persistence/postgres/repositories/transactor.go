@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+
+	errs "github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/interfaces/repositories"
+)
+
+// txKey - context key of the running transaction
+type txKey struct{}
+
+type transactor struct {
+	db *sqlx.DB
+
+	logger observability.Logger
+}
+
+// NewTransactor - Constructor Transactor
+func NewTransactor(db *sqlx.DB, logger observability.Logger) repositories.Transactor {
+	return &transactor{
+		db: db,
+
+		logger: logger,
+	}
+}
+
+// WithinTransaction - runs fn in a transaction, commits it when fn succeeds and rolls it back otherwise
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errs.Wrap(err, "transactor.WithinTransaction: begin")
+	}
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		if errRollback := tx.Rollback(); errRollback != nil {
+			t.logger.Error("transactor.WithinTransaction: rollback", map[string]any{"err": errRollback})
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errs.Wrap(err, "transactor.WithinTransaction: commit")
+	}
+
+	return nil
+}
+
+// savepoint - name of the savepoint, nested savepoints shadow the outer one until released
+const savepoint = "sp_within"
+
+// WithinSavepoint - runs fn in a savepoint, releases it when fn succeeds and rolls back to it otherwise
+func (t *transactor) WithinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx, ok := ctx.Value(txKey{}).(*sqlx.Tx)
+	if !ok {
+		return t.WithinTransaction(ctx, fn)
+	}
+
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return errs.Wrap(err, "transactor.WithinSavepoint: savepoint")
+	}
+
+	if err := fn(ctx); err != nil {
+		if _, errRollback := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint); errRollback != nil {
+			t.logger.Error("transactor.WithinSavepoint: rollback", map[string]any{"err": errRollback})
+		}
+
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint); err != nil {
+		return errs.Wrap(err, "transactor.WithinSavepoint: release")
+	}
+
+	return nil
+}
+
+// querierFromContext - returns the transaction carried by the context or the default querier
+func querierFromContext(ctx context.Context, querier sqlx.ExtContext) sqlx.ExtContext {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+
+	return querier
+}
@@ -0,0 +1,200 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/mocks"
+)
+
+func TestTransactor_WithinTransaction_Commit(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	logger := mocks.NewMockLogger(ctrl)
+	tx := NewTransactor(sqlxDB, logger)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	err = tx.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		_, ok := querierFromContext(ctx, sqlxDB).(*sqlx.Tx)
+		require.True(t, ok)
+
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactor_WithinTransaction_Rollback(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	logger := mocks.NewMockLogger(ctrl)
+	tx := NewTransactor(sqlxDB, logger)
+	errFn := errors.New("error fn")
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	err = tx.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		return errFn
+	})
+
+	require.ErrorIs(t, err, errFn)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactor_WithinTransaction_ErrorBegin(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	logger := mocks.NewMockLogger(ctrl)
+	tx := NewTransactor(sqlxDB, logger)
+
+	mock.ExpectBegin().WillReturnError(sql.ErrConnDone)
+
+	err = tx.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		t.Fatal("fn must not be called")
+
+		return nil
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "transactor.WithinTransaction: begin")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactor_WithinTransaction_Nested(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	logger := mocks.NewMockLogger(ctrl)
+	tx := NewTransactor(sqlxDB, logger)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	err = tx.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		outer := querierFromContext(ctx, sqlxDB)
+
+		return tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			require.Equal(t, outer, querierFromContext(ctx, sqlxDB))
+
+			return nil
+		})
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactor_QuerierFromContext_Default(t *testing.T) {
+	mockDB, _, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+
+	require.Equal(t, sqlxDB, querierFromContext(context.Background(), sqlxDB))
+}
+
+func TestTransactor_WithinSavepoint_Release(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	logger := mocks.NewMockLogger(ctrl)
+	tx := NewTransactor(sqlxDB, logger)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT sp_within").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT sp_within").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err = tx.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		return tx.WithinSavepoint(ctx, func(ctx context.Context) error {
+			return nil
+		})
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactor_WithinSavepoint_Rollback(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	logger := mocks.NewMockLogger(ctrl)
+	tx := NewTransactor(sqlxDB, logger)
+	errFn := errors.New("error fn")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT sp_within").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_within").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err = tx.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		errSavepoint := tx.WithinSavepoint(ctx, func(ctx context.Context) error {
+			return errFn
+		})
+		require.ErrorIs(t, errSavepoint, errFn)
+
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactor_WithinSavepoint_WithoutTransaction(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	defer mockDB.Close()
+
+	ctrl := gomock.NewController(t)
+	sqlxDB := sqlx.NewDb(mockDB, "sqlmock")
+	logger := mocks.NewMockLogger(ctrl)
+	tx := NewTransactor(sqlxDB, logger)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	err = tx.WithinSavepoint(context.Background(), func(ctx context.Context) error {
+		_, ok := querierFromContext(ctx, sqlxDB).(*sqlx.Tx)
+		require.True(t, ok)
+
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
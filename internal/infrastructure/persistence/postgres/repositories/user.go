@@ -0,0 +1,236 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	errs "github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/interfaces/repositories"
+)
+
+type userRepository struct {
+	querier sqlx.ExtContext
+	builder sq.StatementBuilderType
+
+	logger observability.Logger
+}
+
+// NewUserRepository - Constructor UserRepository
+func NewUserRepository(querier sqlx.ExtContext, builder sq.StatementBuilderType, logger observability.Logger) repositories.UserRepository {
+	return &userRepository{
+		querier: querier,
+		builder: builder,
+
+		logger: logger,
+	}
+}
+
+var (
+	tableUsers   = "users"
+	columnsUsers = []string{"id", "name", "email", "created_at", "updated_at"}
+)
+
+// conn - returns the transaction carried by the context or the repository querier
+func (r *userRepository) conn(ctx context.Context) sqlx.ExtContext {
+	return querierFromContext(ctx, r.querier)
+}
+
+// Create - create new row, the ID is generated unless it is set.
+// ErrAlreadyExists is returned when the user with the ID or the email exists.
+func (r *userRepository) Create(ctx context.Context, user entities.User) (*entities.User, error) {
+	data := map[string]any{
+		"name":  user.Name,
+		"email": user.Email,
+	}
+	if user.ID != uuid.Nil {
+		data["id"] = user.ID
+	}
+
+	query, args, err := r.builder.Insert(tableUsers).
+		SetMap(data).
+		Suffix("ON CONFLICT DO NOTHING RETURNING " + strings.Join(columnsUsers, ", ")).
+		ToSql()
+	if err != nil {
+		return nil, errs.Wrap(err, "userRepositories.Create: build query")
+	}
+
+	created := &entities.User{}
+	err = r.conn(ctx).QueryRowxContext(ctx, query, args...).StructScan(created)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.Wrap(errs.ErrAlreadyExists, "userRepositories.Create")
+		}
+		return nil, errs.Wrap(err, "userRepositories.Create: exec query")
+	}
+
+	return created, nil
+}
+
+// GetByID - Returns user by ID
+func (r *userRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	query, args, err := r.builder.Select(columnsUsers...).
+		From(tableUsers).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, errs.Wrap(err, "userRepositories.GetByID: build query")
+	}
+
+	user := &entities.User{}
+	err = r.conn(ctx).QueryRowxContext(ctx, query, args...).StructScan(user)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errs.ErrNotFound
+		}
+		return nil, errs.Wrap(err, "userRepositories.GetByID: scan query")
+	}
+
+	return user, nil
+}
+
+// List - Returns users by filter ordered by name
+func (r *userRepository) List(ctx context.Context, filter entities.UserFilter) ([]entities.User, error) {
+	query := r.builder.Select(columnsUsers...).From(tableUsers)
+	if filter.Search != "" {
+		pattern := "%" + escapeLike(filter.Search) + "%"
+		query = query.Where(sq.Or{sq.ILike{"name": pattern}, sq.ILike{"email": pattern}})
+	}
+	query = query.OrderBy("name", "id")
+
+	if filter.Pagination.Limit > 0 {
+		query = query.Limit(filter.Pagination.Limit)
+		if filter.Pagination.Page > 1 {
+			query = query.Offset((filter.Pagination.Page - 1) * filter.Pagination.Limit)
+		}
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, errs.Wrap(err, "userRepositories.List: build query")
+	}
+
+	rows, err := r.conn(ctx).QueryxContext(ctx, sql, args...)
+	if err != nil {
+		return nil, errs.Wrap(err, "userRepositories.List: get query")
+	}
+	defer rows.Close()
+
+	users := make([]entities.User, 0)
+	for rows.Next() {
+		var user entities.User
+		err = rows.StructScan(&user)
+		if err != nil {
+			return nil, errs.Wrap(err, "userRepositories.List: scan query")
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, errs.Wrap(err, "userRepositories.List: iteration rows")
+	}
+
+	return users, nil
+}
+
+// Update - Updated fields of user by ID.
+// ErrNotFound is returned when there is no user with the id,
+// ErrAlreadyExists is returned when the new email is the email of another user.
+func (r *userRepository) Update(ctx context.Context, id uuid.UUID, fields map[string]any) error {
+	for key, value := range fields {
+		validator, ok := entities.UserUpdateFields[key]
+		if !ok || !validator(value) {
+			r.logger.Error("userRepositories.Update: validate fields", fields)
+
+			return fmt.Errorf("userRepositories.Update: invalid field %s", key)
+		}
+	}
+
+	set := make(map[string]any, len(fields)+1)
+	for column, value := range fields {
+		set[column] = value
+	}
+	set["updated_at"] = time.Now().UTC()
+
+	build := r.builder.Update(tableUsers).
+		Where(sq.Eq{"id": id}).
+		SetMap(set)
+	email, guarded := fields["email"].(string)
+	guarded = guarded && email != ""
+	if guarded {
+		build = build.Where("NOT EXISTS (SELECT 1 FROM users AS o WHERE lower(o.email) = lower(?) AND o.id <> ?)", email, id)
+	}
+
+	query, args, err := build.ToSql()
+	if err != nil {
+		return errs.Wrap(err, "userRepositories.Update: build query")
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		return errs.Wrap(err, "userRepositories.Update: exec query")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return errs.Wrap(err, "userRepositories.Update: get affected rows")
+	}
+
+	if rowsAffected == 0 {
+		return r.updateMissed(ctx, id, guarded)
+	}
+
+	return nil
+}
+
+// updateMissed - tells why no row was updated: the user does not exist or the email guard blocked the update
+func (r *userRepository) updateMissed(ctx context.Context, id uuid.UUID, guarded bool) error {
+	if !guarded {
+		return errs.Wrap(errs.ErrNotFound, "userRepositories.Update")
+	}
+
+	if _, err := r.GetByID(ctx, id); err != nil {
+		return errs.Wrap(err, "userRepositories.Update")
+	}
+
+	return errs.Wrap(errs.ErrAlreadyExists, "userRepositories.Update: email taken")
+}
+
+// Delete - Deletes the row with the id.
+// ErrConflict is returned while subscriptions or memberships of the user exist, soft-deleted subscriptions included.
+func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query, args, err := r.builder.Delete(tableUsers).
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return errs.Wrap(err, "userRepositories.Delete: build query")
+	}
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, args...)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return errs.Wrap(errs.ErrConflict, "userRepositories.Delete: user has subscriptions")
+		}
+		return errs.Wrap(err, "userRepositories.Delete: exec query")
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return errs.Wrap(err, "userRepositories.Delete: get affected rows")
+	}
+
+	if rowsAffected == 0 {
+		return errs.ErrNotFound
+	}
+
+	return nil
+}
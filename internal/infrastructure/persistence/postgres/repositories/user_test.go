@@ -0,0 +1,205 @@
+package repositories
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/mocks"
+)
+
+func newUserRepository(t *testing.T) (*userRepository, sqlmock.Sqlmock) {
+	mockDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "create mock")
+	t.Cleanup(func() { mockDB.Close() })
+
+	ctrl := gomock.NewController(t)
+	logger := mocks.NewMockLogger(ctrl)
+	repo := NewUserRepository(sqlx.NewDb(mockDB, "sqlmock"), sq.StatementBuilder.PlaceholderFormat(sq.Dollar), logger)
+
+	return repo.(*userRepository), mock
+}
+
+func TestUserRepository_Create_Success(t *testing.T) {
+	repo, mock := newUserRepository(t)
+	id := uuid.New()
+	now := time.Now().UTC()
+
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO users (email,id,name) VALUES ($1,$2,$3) ON CONFLICT DO NOTHING RETURNING id, name, email, created_at, updated_at")).
+		WithArgs("ann@example.com", id, "Ann").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at"}).
+			AddRow(id, "Ann", "ann@example.com", now, now))
+
+	user, err := repo.Create(context.Background(), entities.User{ID: id, Name: "Ann", Email: "ann@example.com"})
+
+	require.NoError(t, err)
+	assert.Equal(t, &entities.User{ID: id, Name: "Ann", Email: "ann@example.com", CreatedAt: now, UpdatedAt: now}, user)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_Create_AlreadyExists(t *testing.T) {
+	repo, mock := newUserRepository(t)
+
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO users (email,name) VALUES ($1,$2) ON CONFLICT DO NOTHING")).
+		WithArgs("ann@example.com", "Ann").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	user, err := repo.Create(context.Background(), entities.User{Name: "Ann", Email: "ann@example.com"})
+
+	require.Nil(t, user)
+	assert.ErrorIs(t, err, errors.ErrAlreadyExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_GetByID_NotFound(t *testing.T) {
+	repo, mock := newUserRepository(t)
+	id := uuid.New()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1")).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	user, err := repo.GetByID(context.Background(), id)
+
+	require.Nil(t, user)
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_List_Success(t *testing.T) {
+	repo, mock := newUserRepository(t)
+	id := uuid.New()
+	now := time.Now().UTC()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, created_at, updated_at FROM users WHERE (name ILIKE $1 OR email ILIKE $2) ORDER BY name, id LIMIT 10 OFFSET 10")).
+		WithArgs("%ann%", "%ann%").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at"}).
+			AddRow(id, "Ann", "ann@example.com", now, now))
+
+	users, err := repo.List(context.Background(), entities.UserFilter{
+		Search:     "ann",
+		Pagination: entities.PaginationParams{Page: 2, Limit: 10},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []entities.User{{ID: id, Name: "Ann", Email: "ann@example.com", CreatedAt: now, UpdatedAt: now}}, users)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_Update_InvalidField(t *testing.T) {
+	repo, mock := newUserRepository(t)
+	repo.logger.(*mocks.MockLogger).EXPECT().Error(gomock.Any(), gomock.Any())
+
+	err := repo.Update(context.Background(), uuid.New(), map[string]any{"price": 1})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "userRepositories.Update: invalid field price")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_Update_EmailTaken(t *testing.T) {
+	repo, mock := newUserRepository(t)
+	id := uuid.New()
+
+	now := time.Now().UTC()
+	fields := map[string]any{"email": "ann@example.com"}
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET email = $1, updated_at = $2 WHERE id = $3 AND NOT EXISTS (SELECT 1 FROM users AS o WHERE lower(o.email) = lower($4) AND o.id <> $5)")).
+		WithArgs("ann@example.com", sqlmock.AnyArg(), id, "ann@example.com", id).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1")).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "created_at", "updated_at"}).
+			AddRow(id, "Ann", "ann@other.com", now, now))
+
+	err := repo.Update(context.Background(), id, fields)
+
+	assert.ErrorIs(t, err, errors.ErrAlreadyExists)
+	assert.Equal(t, map[string]any{"email": "ann@example.com"}, fields)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_Update_NotFound(t *testing.T) {
+	repo, mock := newUserRepository(t)
+	id := uuid.New()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET email = $1, updated_at = $2 WHERE id = $3 AND NOT EXISTS")).
+		WithArgs("ann@example.com", sqlmock.AnyArg(), id, "ann@example.com", id).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, email, created_at, updated_at FROM users WHERE id = $1")).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	err := repo.Update(context.Background(), id, map[string]any{"email": "ann@example.com"})
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_Update_NotFoundWithoutEmail(t *testing.T) {
+	repo, mock := newUserRepository(t)
+	id := uuid.New()
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET name = $1, updated_at = $2 WHERE id = $3")).
+		WithArgs("Ann", sqlmock.AnyArg(), id).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.Update(context.Background(), id, map[string]any{"name": "Ann"})
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_Delete_Success(t *testing.T) {
+	repo, mock := newUserRepository(t)
+	id := uuid.New()
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM users WHERE id = $1")).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.Delete(context.Background(), id)
+
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_Delete_HasSubscriptions(t *testing.T) {
+	repo, mock := newUserRepository(t)
+	id := uuid.New()
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM users WHERE id = $1")).
+		WithArgs(id).
+		WillReturnError(&pgconn.PgError{Code: codeForeignKeyViolation})
+
+	err := repo.Delete(context.Background(), id)
+
+	assert.ErrorIs(t, err, errors.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepository_Delete_NotFound(t *testing.T) {
+	repo, mock := newUserRepository(t)
+	id := uuid.New()
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM users WHERE id = $1")).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.Delete(context.Background(), id)
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,138 @@
+package csvimport
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	errs "github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/usecases/subscription"
+)
+
+//go:generate mockgen -destination=./../../../mocks/mock_csvimport.go -package=mocks -source=./importer.go
+
+// BatchSize - number of rows created in one transaction
+const BatchSize = 100
+
+// MaxReportedErrors - max number of row errors in the report, the rest are only counted
+const MaxReportedErrors = 1000
+
+// SubscriptionCreator - creates subscriptions in bulk
+type SubscriptionCreator interface {
+	CreateBulk(ctx context.Context, subs []entities.Subscription, mode entities.BulkModeType) (*entities.BulkResult, error)
+}
+
+// RowError - error of the CSV line
+type RowError struct {
+	Line    int    `json:"line"`
+	Message string `json:"error"`
+}
+
+// Report - result of the import. Valid rows are not created in the dry run.
+type Report struct {
+	DryRun          bool       `json:"dry_run"`
+	Rows            int        `json:"rows"`
+	Valid           int        `json:"valid"`
+	Created         int        `json:"created"`
+	Failed          int        `json:"failed"`
+	Errors          []RowError `json:"errors"`
+	ErrorsTruncated bool       `json:"errors_truncated,omitempty"`
+}
+
+type Importer struct {
+	uc        SubscriptionCreator
+	validator *validator.Validate
+	logger    observability.Logger
+}
+
+// NewImporter - Constructor Importer
+func NewImporter(uc SubscriptionCreator, validator *validator.Validate, logger observability.Logger) *Importer {
+	return &Importer{uc: uc, validator: validator, logger: logger}
+}
+
+// Import - Reads subscriptions from CSV and creates the valid ones in batches of BatchSize,
+// every batch is created in its own transaction. The dry run only validates the rows.
+func (im *Importer) Import(ctx context.Context, r io.Reader, dryRun bool) (*Report, error) {
+	reader, err := NewReader(r, im.validator)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{DryRun: dryRun}
+	batch := make([]entities.Subscription, 0, BatchSize)
+	lines := make([]int, 0, BatchSize)
+
+	for {
+		row, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errs.Wrap(err, "csvimport.Import")
+		}
+
+		report.Rows++
+		if row.Err != nil {
+			report.fail(row.Line, row.Err.Error())
+			continue
+		}
+
+		report.Valid++
+		if dryRun {
+			continue
+		}
+
+		batch = append(batch, row.Sub)
+		lines = append(lines, row.Line)
+		if len(batch) < BatchSize {
+			continue
+		}
+
+		if err := im.flush(ctx, report, batch, lines); err != nil {
+			return nil, err
+		}
+		batch, lines = batch[:0], lines[:0]
+	}
+
+	if len(batch) > 0 {
+		if err := im.flush(ctx, report, batch, lines); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+// flush - creates the batch, failed rows are reported with the reason and the rest of the batch is kept
+func (im *Importer) flush(ctx context.Context, report *Report, batch []entities.Subscription, lines []int) error {
+	result, err := im.uc.CreateBulk(ctx, batch, entities.BulkModeBestEffort)
+	if err != nil {
+		return errs.Wrap(err, "csvimport.Import: create batch")
+	}
+
+	for i, item := range result.Items {
+		if item.Err != nil {
+			im.logger.Error("csvimport.Import: create row", map[string]any{"line": lines[i], "err": item.Err})
+			report.fail(lines[i], subscription.ItemErrorMessage(item.Err))
+			continue
+		}
+		report.Created++
+	}
+
+	return nil
+}
+
+// fail - counts the failed row, its error is reported until MaxReportedErrors
+func (r *Report) fail(line int, msg string) {
+	r.Failed++
+	if len(r.Errors) >= MaxReportedErrors {
+		r.ErrorsTruncated = true
+		return
+	}
+
+	r.Errors = append(r.Errors, RowError{Line: line, Message: msg})
+}
@@ -0,0 +1,117 @@
+package csvimport
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/mocks"
+)
+
+const csvRow = "Netflix,60601fee-2bf1-4721-ae6f-7636e79a0cba,400,RUB,monthly,01-2025,\n"
+
+func TestImporter_Import_DryRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCreator := mocks.NewMockSubscriptionCreator(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	importer := NewImporter(mockCreator, newValidator(), mockLogger)
+
+	data := csvHeader + csvRow + "Netflix,not-uuid,400,RUB,monthly,01-2025,\n"
+
+	report, err := importer.Import(context.Background(), strings.NewReader(data), true)
+
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 2, report.Rows)
+	assert.Equal(t, 1, report.Valid)
+	assert.Equal(t, 0, report.Created)
+	assert.Equal(t, 1, report.Failed)
+	require.Len(t, report.Errors, 1)
+	assert.Equal(t, 3, report.Errors[0].Line)
+}
+
+func TestImporter_Import_Batches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCreator := mocks.NewMockSubscriptionCreator(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	importer := NewImporter(mockCreator, newValidator(), mockLogger)
+	ctx := context.Background()
+
+	data := csvHeader + strings.Repeat(csvRow, BatchSize+1)
+
+	gomock.InOrder(
+		mockCreator.EXPECT().
+			CreateBulk(ctx, gomock.Len(BatchSize), entities.BulkModeBestEffort).
+			DoAndReturn(func(ctx context.Context, subs []entities.Subscription, mode entities.BulkModeType) (*entities.BulkResult, error) {
+				result := &entities.BulkResult{Mode: mode, Committed: true, Items: make([]entities.BulkItemResult, len(subs))}
+				result.Items[1].Err = errors.Wrap(errors.ErrInvalidInput, "unknown user")
+				result.Items[2].Err = errors.New("error repo")
+				return result, nil
+			}),
+		mockCreator.EXPECT().
+			CreateBulk(ctx, gomock.Len(1), entities.BulkModeBestEffort).
+			Return(&entities.BulkResult{Committed: true, Items: make([]entities.BulkItemResult, 1)}, nil),
+	)
+	mockLogger.EXPECT().Error(gomock.Any(), gomock.Any()).Times(2)
+
+	report, err := importer.Import(ctx, strings.NewReader(data), false)
+
+	require.NoError(t, err)
+	assert.Equal(t, BatchSize+1, report.Rows)
+	assert.Equal(t, BatchSize+1, report.Valid)
+	assert.Equal(t, BatchSize-1, report.Created)
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, []RowError{
+		{Line: 3, Message: "unknown user: invalid input"},
+		{Line: 4, Message: "Internal server error"},
+	}, report.Errors)
+}
+
+func TestImporter_Import_ErrorCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCreator := mocks.NewMockSubscriptionCreator(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	importer := NewImporter(mockCreator, newValidator(), mockLogger)
+
+	mockCreator.EXPECT().CreateBulk(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error tx"))
+
+	_, err := importer.Import(context.Background(), strings.NewReader(csvHeader+csvRow), false)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "csvimport.Import: create batch")
+}
+
+func TestImporter_Import_ErrorsTruncated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCreator := mocks.NewMockSubscriptionCreator(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	importer := NewImporter(mockCreator, newValidator(), mockLogger)
+
+	var data strings.Builder
+	data.WriteString(csvHeader)
+	for i := 0; i < MaxReportedErrors+5; i++ {
+		fmt.Fprintf(&data, "Netflix,not-uuid-%d,400,RUB,monthly,01-2025,\n", i)
+	}
+
+	report, err := importer.Import(context.Background(), strings.NewReader(data.String()), true)
+
+	require.NoError(t, err)
+	assert.Equal(t, MaxReportedErrors+5, report.Failed)
+	assert.Len(t, report.Errors, MaxReportedErrors)
+	assert.True(t, report.ErrorsTruncated)
+}
@@ -0,0 +1,180 @@
+package csvimport
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	errs "github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/usecases/subscription"
+)
+
+// tagSeparator - separates the tags in the tags column
+const tagSeparator = "|"
+
+// columnSetter - sets the value of the column onto the request, empty values are left to the validator
+type columnSetter func(req *subscription.CreateInput, value string) error
+
+// columns - CSV columns named as the JSON fields of the create request
+var columns = map[string]columnSetter{
+	"service_name": func(req *subscription.CreateInput, value string) error {
+		req.ServiceName = value
+		return nil
+	},
+	"user_id": func(req *subscription.CreateInput, value string) error {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return fmt.Errorf("user_id: invalid uuid %q", value)
+		}
+		req.UserId = id
+		return nil
+	},
+	"price": func(req *subscription.CreateInput, value string) error {
+		price, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return fmt.Errorf("price: invalid number %q", value)
+		}
+		req.Price = uint32(price)
+		return nil
+	},
+	"currency": func(req *subscription.CreateInput, value string) error {
+		req.Currency = strings.ToUpper(value)
+		return nil
+	},
+	"billing_period": func(req *subscription.CreateInput, value string) error {
+		req.BillingPeriod = strings.ToLower(value)
+		return nil
+	},
+	"billing_interval": func(req *subscription.CreateInput, value string) error {
+		interval, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return fmt.Errorf("billing_interval: invalid number %q", value)
+		}
+		req.BillingInterval = uint16(interval)
+		return nil
+	},
+	"start_date": func(req *subscription.CreateInput, value string) error {
+		req.StartDate = value
+		return nil
+	},
+	"end_date": func(req *subscription.CreateInput, value string) error {
+		req.EndDate = value
+		return nil
+	},
+	"trial_end_date": func(req *subscription.CreateInput, value string) error {
+		req.TrialEndDate = value
+		return nil
+	},
+	"category": func(req *subscription.CreateInput, value string) error {
+		req.Category = value
+		return nil
+	},
+	"tags": func(req *subscription.CreateInput, value string) error {
+		for _, tag := range strings.Split(value, tagSeparator) {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				req.Tags = append(req.Tags, tag)
+			}
+		}
+		return nil
+	},
+}
+
+// Row - subscription read from the CSV line, Err is set when the line is invalid
+type Row struct {
+	Line int
+	Sub  entities.Subscription
+	Err  error
+}
+
+// Reader - reads subscriptions from CSV one line at a time.
+// The first line is the header with the column names.
+type Reader struct {
+	csv       *csv.Reader
+	setters   []columnSetter
+	validator *validator.Validate
+}
+
+// NewReader - Constructor Reader, reads and checks the header
+func NewReader(r io.Reader, validator *validator.Validate) (*Reader, error) {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errs.Wrap(errs.ErrInvalidInput, "csvimport.NewReader: empty file")
+		}
+		return nil, errs.Wrap(errs.ErrInvalidInput, fmt.Sprintf("csvimport.NewReader: read header: %s", err))
+	}
+
+	setters := make([]columnSetter, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+
+		setter, ok := columns[name]
+		if !ok {
+			return nil, errs.Wrap(errs.ErrInvalidInput, fmt.Sprintf("csvimport.NewReader: unknown column %q", name))
+		}
+		if seen[name] {
+			return nil, errs.Wrap(errs.ErrInvalidInput, fmt.Sprintf("csvimport.NewReader: duplicate column %q", name))
+		}
+		seen[name] = true
+
+		setters[i] = setter
+	}
+
+	return &Reader{csv: reader, setters: setters, validator: validator}, nil
+}
+
+// Next - Returns the next row, io.EOF is returned after the last one.
+// Invalid lines are returned as rows with Err, other errors stop reading.
+func (r *Reader) Next() (Row, error) {
+	record, err := r.csv.Read()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			if errors.Is(parseErr.Err, csv.ErrFieldCount) {
+				return Row{Line: parseErr.StartLine, Err: fmt.Errorf("expected %d fields, got %d", len(r.setters), len(record))}, nil
+			}
+			return Row{Line: parseErr.StartLine, Err: parseErr.Err}, nil
+		}
+		if errors.Is(err, io.EOF) {
+			return Row{}, io.EOF
+		}
+		return Row{}, errs.Wrap(err, "csvimport.Next: read line")
+	}
+
+	line, _ := r.csv.FieldPos(0)
+	row := Row{Line: line}
+
+	var req subscription.CreateInput
+	for i, value := range record {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if err := r.setters[i](&req, value); err != nil {
+			row.Err = err
+			return row, nil
+		}
+	}
+
+	if err := r.validator.Struct(req); err != nil {
+		row.Err = err
+		return row, nil
+	}
+
+	row.Sub, row.Err = req.ToEntity()
+
+	return row, nil
+}
@@ -0,0 +1,110 @@
+package csvimport
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+)
+
+const csvHeader = "service_name,user_id,price,currency,billing_period,start_date,end_date\n"
+
+func newValidator() *validator.Validate {
+	return validator.New(validator.WithRequiredStructEnabled())
+}
+
+func TestReader_Next_Success(t *testing.T) {
+	data := csvHeader + "Netflix,60601fee-2bf1-4721-ae6f-7636e79a0cba,400,usd,yearly,01-2025,\n"
+
+	reader, err := NewReader(strings.NewReader(data), newValidator())
+	require.NoError(t, err)
+
+	row, err := reader.Next()
+	require.NoError(t, err)
+	require.NoError(t, row.Err)
+	assert.Equal(t, 2, row.Line)
+	assert.Equal(t, "Netflix", row.Sub.ServiceName)
+	assert.Equal(t, uint32(400), row.Sub.Price)
+	assert.Equal(t, "USD", row.Sub.Currency)
+	assert.Equal(t, entities.BillingPeriodYearly, row.Sub.BillingPeriod)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), row.Sub.StartDate)
+	assert.False(t, row.Sub.EndDate.Valid)
+
+	_, err = reader.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestReader_Next_CategoryTags(t *testing.T) {
+	data := "service_name,user_id,price,start_date,category,tags\n" +
+		"Netflix,60601fee-2bf1-4721-ae6f-7636e79a0cba,400,01-2025,Streaming,family | video|\n"
+
+	reader, err := NewReader(strings.NewReader(data), newValidator())
+	require.NoError(t, err)
+
+	row, err := reader.Next()
+	require.NoError(t, err)
+	require.NoError(t, row.Err)
+	assert.Equal(t, "Streaming", row.Sub.Category)
+	assert.Equal(t, entities.TagList{"family", "video"}, row.Sub.Tags)
+}
+
+func TestReader_Next_InvalidRows(t *testing.T) {
+	data := csvHeader +
+		",60601fee-2bf1-4721-ae6f-7636e79a0cba,400,RUB,monthly,01-2025,\n" +
+		"Netflix,not-uuid,400,RUB,monthly,01-2025,\n" +
+		"Netflix,60601fee-2bf1-4721-ae6f-7636e79a0cba,abc,RUB,monthly,01-2025,\n" +
+		"Netflix,60601fee-2bf1-4721-ae6f-7636e79a0cba,400\n" +
+		"Net\"flix,60601fee-2bf1-4721-ae6f-7636e79a0cba,400,RUB,monthly,01-2025,\n"
+
+	reader, err := NewReader(strings.NewReader(data), newValidator())
+	require.NoError(t, err)
+
+	for _, expected := range []struct {
+		line int
+		err  string
+	}{
+		{line: 2, err: "ServiceName"},
+		{line: 3, err: "user_id"},
+		{line: 4, err: "price"},
+		{line: 5, err: "expected 7 fields"},
+		{line: 6, err: "bare \""},
+	} {
+		row, err := reader.Next()
+		require.NoError(t, err)
+		assert.Equal(t, expected.line, row.Line)
+		require.Error(t, row.Err)
+		assert.Contains(t, row.Err.Error(), expected.err)
+	}
+
+	_, err = reader.Next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestReader_NewReader_ErrorHeader(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{name: "empty", data: "", err: "empty file"},
+		{name: "unknown column", data: "service_name,color\n", err: "unknown column"},
+		{name: "duplicate column", data: "price,price\n", err: "duplicate column"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewReader(strings.NewReader(tt.data), newValidator())
+
+			require.Error(t, err)
+			assert.ErrorIs(t, err, errors.ErrInvalidInput)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package exchangerate
+
+import (
+	"context"
+)
+
+//go:generate mockgen -destination=./../../../mocks/mock_exchange_rate_provider.go -package=mocks -source=./provider.go
+
+// ExchangeRateProvider - source of currency exchange rates
+type ExchangeRateProvider interface {
+	// Rate - returns amount of currency `to` for one unit of currency `from`
+	Rate(ctx context.Context, from, to string) (float64, error)
+}
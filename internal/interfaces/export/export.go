@@ -0,0 +1,250 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	errs "github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/convert"
+)
+
+// FormatType - format of the export file
+type FormatType string
+
+const (
+	FormatCSV   FormatType = "csv"
+	FormatJSONL FormatType = "jsonl"
+	FormatXLSX  FormatType = "xlsx"
+)
+
+// ContentTypes - content type of the file by format
+var ContentTypes = map[FormatType]string{
+	FormatCSV:   "text/csv; charset=utf-8",
+	FormatJSONL: "application/x-ndjson",
+	FormatXLSX:  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// xlsxSheet - name of the sheet with subscriptions
+const xlsxSheet = "Subscriptions"
+
+// xlsxNumeric - columns written into XLSX as numbers, so they can be summed in the spreadsheet
+var xlsxNumeric = map[string]bool{"id": true, "price": true, "converted_price": true, "billing_interval": true}
+
+// header - columns of the CSV and XLSX files
+var header = []string{"id", "service_name", "user_id", "price", "currency", "converted_price", "converted_currency", "billing_period", "billing_interval", "start_date", "end_date", "trial_end_date", "status", "status_changed_at", "deleted_at", "created_at", "updated_at", "category", "tags"}
+
+// Writer - writes subscriptions into the file, Close completes the file.
+// Discard releases the writer when the export failed and the file is not completed.
+type Writer interface {
+	Write(sub entities.Subscription) error
+	Close() error
+	Discard()
+}
+
+// NewWriter - Constructor Writer of the format, the header is written at once
+func NewWriter(format FormatType, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatJSONL:
+		return &jsonlWriter{w: bufio.NewWriter(w)}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	default:
+		return nil, errs.Wrap(errs.ErrInvalidInput, fmt.Sprintf("export.NewWriter: unknown format %q", format))
+	}
+}
+
+// record - values of the columns in the order of header
+func record(sub entities.Subscription) []string {
+	resp := convert.SubscriptionEntityToResponse(sub)
+
+	var convertedPrice, convertedCurrency string
+	if resp.ConvertedPrice != nil {
+		convertedPrice = strconv.FormatFloat(resp.ConvertedPrice.Amount, 'f', 2, 64)
+		convertedCurrency = resp.ConvertedPrice.Currency
+	}
+
+	return []string{
+		strconv.FormatInt(resp.ID, 10),
+		resp.ServiceName,
+		resp.UserId.String(),
+		strconv.FormatUint(uint64(resp.Price), 10),
+		resp.Currency,
+		convertedPrice,
+		convertedCurrency,
+		resp.BillingPeriod,
+		strconv.FormatUint(uint64(resp.BillingInterval), 10),
+		resp.StartDate,
+		resp.EndDate,
+		resp.TrialEndDate,
+		resp.Status,
+		resp.StatusChangedAt,
+		resp.DeletedAt,
+		resp.CreatedAt,
+		resp.UpdatedAt,
+		resp.Category,
+		strings.Join(resp.Tags, ","),
+	}
+}
+
+type csvWriter struct {
+	csv *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	writer := &csvWriter{csv: csv.NewWriter(w)}
+	if err := writer.csv.Write(header); err != nil {
+		return nil, errs.Wrap(err, "export.NewWriter: write header")
+	}
+
+	return writer, nil
+}
+
+// Write - writes the subscription as CSV line
+func (cw *csvWriter) Write(sub entities.Subscription) error {
+	if err := cw.csv.Write(record(sub)); err != nil {
+		return errs.Wrap(err, "export.Write: write line")
+	}
+
+	return nil
+}
+
+// Close - flushes buffered lines
+func (cw *csvWriter) Close() error {
+	cw.csv.Flush()
+	if err := cw.csv.Error(); err != nil {
+		return errs.Wrap(err, "export.Close: flush")
+	}
+
+	return nil
+}
+
+// Discard - lines are already written
+func (cw *csvWriter) Discard() {}
+
+type jsonlWriter struct {
+	w *bufio.Writer
+}
+
+// Write - writes the subscription as JSON line in the format of the API response
+func (jw *jsonlWriter) Write(sub entities.Subscription) error {
+	line, err := json.Marshal(convert.SubscriptionEntityToResponse(sub))
+	if err != nil {
+		return errs.Wrap(err, "export.Write: marshal")
+	}
+
+	if _, err := jw.w.Write(append(line, '\n')); err != nil {
+		return errs.Wrap(err, "export.Write: write line")
+	}
+
+	return nil
+}
+
+// Close - flushes buffered lines
+func (jw *jsonlWriter) Close() error {
+	if err := jw.w.Flush(); err != nil {
+		return errs.Wrap(err, "export.Close: flush")
+	}
+
+	return nil
+}
+
+// Discard - lines are already written
+func (jw *jsonlWriter) Discard() {}
+
+// xlsxWriter - rows are kept by the stream writer of excelize, it moves them to a temporary file
+// when they do not fit in memory. The workbook is written on Close because XLSX is a zip archive.
+type xlsxWriter struct {
+	w      io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+	if err := file.SetSheetName(file.GetSheetName(0), xlsxSheet); err != nil {
+		return nil, errs.Wrap(err, "export.NewWriter: rename sheet")
+	}
+
+	stream, err := file.NewStreamWriter(xlsxSheet)
+	if err != nil {
+		return nil, errs.Wrap(err, "export.NewWriter: stream writer")
+	}
+
+	row := make([]any, len(header))
+	for i, name := range header {
+		row[i] = name
+	}
+
+	writer := &xlsxWriter{w: w, file: file, stream: stream}
+	if err := writer.writeRow(row); err != nil {
+		return nil, errs.Wrap(err, "export.NewWriter: write header")
+	}
+
+	return writer, nil
+}
+
+// Write - writes the subscription as row of the sheet
+func (xw *xlsxWriter) Write(sub entities.Subscription) error {
+	values := record(sub)
+
+	row := make([]any, len(values))
+	for i, value := range values {
+		row[i] = value
+		if !xlsxNumeric[header[i]] || value == "" {
+			continue
+		}
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			row[i] = number
+		}
+	}
+
+	if err := xw.writeRow(row); err != nil {
+		return errs.Wrap(err, "export.Write: write row")
+	}
+
+	return nil
+}
+
+// Close - writes the workbook and removes its temporary files
+func (xw *xlsxWriter) Close() error {
+	defer func() {
+		_ = xw.file.Close()
+	}()
+
+	if err := xw.stream.Flush(); err != nil {
+		return errs.Wrap(err, "export.Close: flush")
+	}
+
+	if err := xw.file.Write(xw.w); err != nil {
+		return errs.Wrap(err, "export.Close: write workbook")
+	}
+
+	return nil
+}
+
+// Discard - removes temporary files of the workbook without writing it
+func (xw *xlsxWriter) Discard() {
+	_ = xw.file.Close()
+}
+
+// writeRow - writes values into the next row of the sheet
+func (xw *xlsxWriter) writeRow(row []any) error {
+	xw.row++
+	cell, err := excelize.CoordinatesToCellName(1, xw.row)
+	if err != nil {
+		return err
+	}
+
+	return xw.stream.SetRow(cell, row)
+}
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/dto"
+)
+
+var subTest = entities.Subscription{
+	ID:              7,
+	ServiceName:     "Netflix, Premium",
+	UserId:          uuid.MustParse("60601fee-2bf1-4721-ae6f-7636e79a0cba"),
+	Price:           400,
+	Currency:        "USD",
+	ConvertedPrice:  &entities.Converted{Amount: 36200, Currency: "RUB", Rate: 90.5},
+	BillingPeriod:   entities.BillingPeriodMonthly,
+	BillingInterval: 1,
+	StartDate:       time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+	EndDate:         sql.NullTime{Time: time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+	Status:          entities.SubscriptionStatusActive,
+	StatusChangedAt: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+	Category:        "streaming",
+	Tags:            entities.TagList{"family", "video"},
+}
+
+func TestWriter_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(FormatCSV, &buf)
+	require.NoError(t, err)
+
+	require.NoError(t, writer.Write(subTest))
+	require.NoError(t, writer.Close())
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, header, records[0])
+	assert.Equal(t, "7", records[1][0])
+	assert.Equal(t, "Netflix, Premium", records[1][1])
+	assert.Equal(t, "36200.00", records[1][5])
+	assert.Equal(t, "RUB", records[1][6])
+	assert.Equal(t, "01-2025", records[1][9])
+	assert.Equal(t, "12-2025", records[1][10])
+	assert.Equal(t, "", records[1][11])
+	assert.Equal(t, "streaming", records[1][17])
+	assert.Equal(t, "family,video", records[1][18])
+}
+
+func TestWriter_JSONL(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(FormatJSONL, &buf)
+	require.NoError(t, err)
+
+	require.NoError(t, writer.Write(subTest))
+	require.NoError(t, writer.Write(entities.Subscription{ID: 8}))
+	require.NoError(t, writer.Close())
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+
+	var resp dto.SubscriptionResp
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &resp))
+	assert.Equal(t, int64(7), resp.ID)
+	assert.Equal(t, "Netflix, Premium", resp.ServiceName)
+	assert.Equal(t, 36200.0, resp.ConvertedPrice.Amount)
+}
+
+func TestWriter_XLSX(t *testing.T) {
+	var buf bytes.Buffer
+	writer, err := NewWriter(FormatXLSX, &buf)
+	require.NoError(t, err)
+
+	require.NoError(t, writer.Write(subTest))
+	require.NoError(t, writer.Close())
+
+	file, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer file.Close()
+
+	rows, err := file.GetRows(xlsxSheet)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, header, rows[0])
+	assert.Equal(t, "Netflix, Premium", rows[1][1])
+
+	cellType, err := file.GetCellType(xlsxSheet, "D2")
+	require.NoError(t, err)
+	assert.NotEqual(t, excelize.CellTypeSharedString, cellType)
+	assert.NotEqual(t, excelize.CellTypeInlineString, cellType)
+}
+
+func TestWriter_UnknownFormat(t *testing.T) {
+	_, err := NewWriter("pdf", &bytes.Buffer{})
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+}
@@ -0,0 +1,66 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/convert"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/dto"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/response"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/middleware"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/usecases/audit"
+)
+
+type HandlerAudit struct {
+	uc audit.AuditUsecase
+
+	logger observability.Logger
+}
+
+func NewAuditHandler(apiV1Group fiber.Router, uc audit.AuditUsecase, logger observability.Logger) {
+	router := HandlerAudit{
+		uc:     uc,
+		logger: logger,
+	}
+
+	apiV1Group.Get("/audit", middleware.ValidatedQueryParamsAuditMiddleware(logger), router.list)
+}
+
+// @Summary     get audit log
+// @Description Returns changes of subscriptions with the actor and before/after diff, the latest go first
+// @ID          AuditList
+// @Tags  	    Audit
+// @Accept      json
+// @Produce     json
+// @Param       query query dto.QueryParamAudit true "Filter params"
+// @Success     200 {array} dto.AuditResp
+// @Failure     400 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /audit [get]
+func (h *HandlerAudit) list(ctx *fiber.Ctx) error {
+	params, ok := ctx.Locals("query_audit").(dto.QueryParamAudit)
+	if !ok {
+		h.logger.Error("auditV1.List: get query_audit", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	filter, err := convert.AuditQueryParamsToFilter(params)
+	if err != nil {
+		h.logger.Error("auditV1.List: convert", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	entries, err := h.uc.List(ctx.UserContext(), filter)
+	if err != nil {
+		h.logger.Error("auditV1.List: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(convert.AuditEntriesToResponse(entries))
+}
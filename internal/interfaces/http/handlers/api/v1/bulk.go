@@ -0,0 +1,267 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/convert"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/dto"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/response"
+	uc "github.com/mathbdw/subscription-service/internal/usecases/subscription"
+)
+
+// bulkMaxItems - max number of items in one bulk request
+const bulkMaxItems = 100
+
+// @Summary     bulk create subscriptions
+// @Description Creates subscriptions in one transaction. In atomic mode all of them are created or none,
+// @Description in best_effort mode valid items are created and failed ones are skipped.
+// @ID          SubscriptionCreateBulk
+// @Tags  	    Subscription
+// @Accept      json
+// @Produce     json
+// @Param       mode query string false "Bulk mode" Enums(atomic, best_effort) default(atomic)
+// @Param       request body []dto.SubscriptionReq true "Data subscriptions"
+// @Success     200 {object} dto.BulkResp
+// @Success     207 {object} dto.BulkResp
+// @Failure     400 {object} response.Error
+// @Failure     422 {object} dto.BulkResp
+// @Failure     500 {object} response.Error
+// @Router      /subscription/bulk [post]
+func (h *HandlerSubscription) createBulk(ctx *fiber.Ctx) error {
+	mode, err := bulkMode(ctx)
+	if err != nil {
+		h.logger.Error("subscriptionV1.CreateBulk: parse mode", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	var body []dto.SubscriptionReq
+	if err := ctx.BodyParser(&body); err != nil {
+		h.logger.Error("subscriptionV1.CreateBulk: parse body", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := validateBulkSize(len(body)); err != nil {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	items := newBulkItems(len(body))
+	subs := make([]entities.Subscription, 0, len(body))
+	indices := make([]int, 0, len(body))
+	for i, req := range body {
+		if err := h.validator.Struct(req); err != nil {
+			failBulkItem(&items[i], err.Error())
+			continue
+		}
+
+		sub, err := convert.SubscriptionRequestToEntity(req)
+		if err != nil {
+			failBulkItem(&items[i], err.Error())
+			continue
+		}
+
+		subs = append(subs, sub)
+		indices = append(indices, i)
+	}
+
+	return h.runBulk(ctx, "subscriptionV1.CreateBulk", mode, items, indices, func(ctx context.Context) (*entities.BulkResult, error) {
+		return h.uc.CreateBulk(ctx, subs, mode)
+	})
+}
+
+// @Summary     bulk update subscriptions
+// @Description Updates subscriptions by ids in one transaction. In atomic mode all of them are updated or none,
+// @Description in best_effort mode valid items are updated and failed ones are skipped.
+// @Description Item with version is updated only if the subscription was not changed since.
+// @ID          SubscriptionUpdateBulk
+// @Tags  	    Subscription
+// @Accept      json
+// @Produce     json
+// @Param       mode query string false "Bulk mode" Enums(atomic, best_effort) default(atomic)
+// @Param       request body []dto.SubscriptionBulkUpdateReq true "Data subscriptions"
+// @Success     200 {object} dto.BulkResp
+// @Success     207 {object} dto.BulkResp
+// @Failure     400 {object} response.Error
+// @Failure     422 {object} dto.BulkResp
+// @Failure     500 {object} response.Error
+// @Router      /subscription/bulk [patch]
+func (h *HandlerSubscription) updateBulk(ctx *fiber.Ctx) error {
+	mode, err := bulkMode(ctx)
+	if err != nil {
+		h.logger.Error("subscriptionV1.UpdateBulk: parse mode", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	var body []dto.SubscriptionBulkUpdateReq
+	if err := ctx.BodyParser(&body); err != nil {
+		h.logger.Error("subscriptionV1.UpdateBulk: parse body", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := validateBulkSize(len(body)); err != nil {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	items := newBulkItems(len(body))
+	updates := make([]entities.BulkUpdate, 0, len(body))
+	indices := make([]int, 0, len(body))
+	for i, req := range body {
+		items[i].ID = req.ID
+		if err := h.validator.Struct(req); err != nil {
+			failBulkItem(&items[i], err.Error())
+			continue
+		}
+
+		fields, err := convert.SubscriptionRequestToMap(req.SubscriptionUpdateReq)
+		if err != nil {
+			failBulkItem(&items[i], err.Error())
+			continue
+		}
+
+		updates = append(updates, entities.BulkUpdate{ID: req.ID, Fields: fields, Version: req.Version})
+		indices = append(indices, i)
+	}
+
+	return h.runBulk(ctx, "subscriptionV1.UpdateBulk", mode, items, indices, func(ctx context.Context) (*entities.BulkResult, error) {
+		return h.uc.UpdateBulk(ctx, updates, mode)
+	})
+}
+
+// @Summary     bulk delete subscriptions
+// @Description Soft deletes subscriptions by ids in one transaction. In atomic mode all of them are deleted or none,
+// @Description in best_effort mode found subscriptions are deleted and failed ones are skipped.
+// @ID          SubscriptionDeleteBulk
+// @Tags  	    Subscription
+// @Accept      json
+// @Produce     json
+// @Param       mode query string false "Bulk mode" Enums(atomic, best_effort) default(atomic)
+// @Param       request body []int64 true "Subscription IDs"
+// @Success     200 {object} dto.BulkResp
+// @Success     207 {object} dto.BulkResp
+// @Failure     400 {object} response.Error
+// @Failure     422 {object} dto.BulkResp
+// @Failure     500 {object} response.Error
+// @Router      /subscription/bulk [delete]
+func (h *HandlerSubscription) deleteBulk(ctx *fiber.Ctx) error {
+	mode, err := bulkMode(ctx)
+	if err != nil {
+		h.logger.Error("subscriptionV1.DeleteBulk: parse mode", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	var body []int64
+	if err := ctx.BodyParser(&body); err != nil {
+		h.logger.Error("subscriptionV1.DeleteBulk: parse body", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := validateBulkSize(len(body)); err != nil {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	items := newBulkItems(len(body))
+	ids := make([]int64, 0, len(body))
+	indices := make([]int, 0, len(body))
+	for i, id := range body {
+		items[i].ID = id
+		if id < 1 {
+			failBulkItem(&items[i], "invalid id")
+			continue
+		}
+
+		ids = append(ids, id)
+		indices = append(indices, i)
+	}
+
+	return h.runBulk(ctx, "subscriptionV1.DeleteBulk", mode, items, indices, func(ctx context.Context) (*entities.BulkResult, error) {
+		return h.uc.DeleteBulk(ctx, ids, mode)
+	})
+}
+
+// runBulk - runs the valid items of the bulk and responds with the result of every item.
+// The atomic bulk with invalid items is not run.
+func (h *HandlerSubscription) runBulk(ctx *fiber.Ctx, op string, mode entities.BulkModeType, items []dto.BulkItemResp, indices []int,
+	run func(ctx context.Context) (*entities.BulkResult, error)) error {
+	if len(indices) < len(items) && mode == entities.BulkModeAtomic || len(indices) == 0 {
+		h.logger.Error(op+": invalid items", map[string]any{"invalid": len(items) - len(indices)})
+
+		return ctx.Status(http.StatusUnprocessableEntity).JSON(convert.BulkItemsToResponse(mode, false, items))
+	}
+
+	result, err := run(ctx.UserContext())
+	if err != nil {
+		h.logger.Error(op+": usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	for j, itemResult := range result.Items {
+		item := &items[indices[j]]
+		if itemResult.ID != 0 {
+			item.ID = itemResult.ID
+		}
+
+		switch {
+		case itemResult.Err != nil:
+			h.logger.Error(op+": item", map[string]any{"index": item.Index, "err": itemResult.Err})
+			failBulkItem(item, uc.ItemErrorMessage(itemResult.Err))
+		case result.Committed:
+			item.Status = dto.BulkItemStatusOK
+		}
+	}
+
+	resp := convert.BulkItemsToResponse(mode, result.Committed, items)
+	switch {
+	case !result.Committed:
+		return ctx.Status(http.StatusUnprocessableEntity).JSON(resp)
+	case resp.Failed > 0:
+		return ctx.Status(http.StatusMultiStatus).JSON(resp)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(resp)
+}
+
+// bulkMode - returns the bulk mode from query, atomic by default
+func bulkMode(ctx *fiber.Ctx) (entities.BulkModeType, error) {
+	mode := entities.BulkModeType(ctx.Query("mode", string(entities.BulkModeAtomic)))
+	if mode != entities.BulkModeAtomic && mode != entities.BulkModeBestEffort {
+		return "", fmt.Errorf("invalid mode %q, expected %s or %s", mode, entities.BulkModeAtomic, entities.BulkModeBestEffort)
+	}
+
+	return mode, nil
+}
+
+// validateBulkSize - the bulk must have from 1 to bulkMaxItems items
+func validateBulkSize(count int) error {
+	if count == 0 || count > bulkMaxItems {
+		return fmt.Errorf("bulk must have from 1 to %d items", bulkMaxItems)
+	}
+
+	return nil
+}
+
+// newBulkItems - items of the bulk are skipped until they are run
+func newBulkItems(count int) []dto.BulkItemResp {
+	items := make([]dto.BulkItemResp, count)
+	for i := range items {
+		items[i] = dto.BulkItemResp{Index: i, Status: dto.BulkItemStatusSkipped}
+	}
+
+	return items
+}
+
+// failBulkItem - marks the bulk item failed with the message
+func failBulkItem(item *dto.BulkItemResp, msg string) {
+	item.Status = dto.BulkItemStatusFailed
+	item.Error = msg
+}
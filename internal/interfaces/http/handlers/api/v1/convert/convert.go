@@ -1,54 +1,77 @@
 package convert
 
 import (
-	"database/sql"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/mathbdw/subscription-service/internal/domain/entities"
 	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/dto"
+	"github.com/mathbdw/subscription-service/internal/usecases/subscription"
 )
 
+// SubscriptionRequestToEntity - the request is converted by the same rules as the CSV import
 func SubscriptionRequestToEntity(req dto.SubscriptionReq) (entities.Subscription, error) {
-	sub := entities.Subscription{
-		ServiceName: req.ServiceName,
-		UserId:      req.UserId,
-		Price:       req.Price,
+	input := subscription.CreateInput{
+		ServiceName:     req.ServiceName,
+		UserId:          req.UserId,
+		Price:           req.Price,
+		Currency:        req.Currency,
+		BillingPeriod:   req.BillingPeriod,
+		BillingInterval: req.BillingInterval,
+		StartDate:       req.StartDate,
+		EndDate:         req.EndDate,
+		TrialEndDate:    req.TrialEndDate,
+		Category:        req.Category,
+		Tags:            req.Tags,
 	}
-	tmpDate, err := time.Parse("01-2006", req.StartDate)
-	if err != nil {
-		return entities.Subscription{}, fmt.Errorf("StartDate parse - %s", req.StartDate)
+
+	return input.ToEntity()
+}
+
+// SubscriptionRequestToMap - fields present in the request, the rest of the subscription is not changed
+func SubscriptionRequestToMap(req dto.SubscriptionUpdateReq) (map[string]any, error) {
+	dataMap := make(map[string]any)
+
+	if req.ServiceName != nil {
+		dataMap["service_name"] = *req.ServiceName
 	}
-	sub.StartDate = tmpDate
 
-	if req.EndDate != "" {
-		tmpDate, err := time.Parse("01-2006", req.EndDate)
-		if err != nil {
-			return entities.Subscription{}, fmt.Errorf("EndDate parse - %s", req.EndDate)
-		}
+	if req.UserId != nil {
+		dataMap["user_id"] = *req.UserId
+	}
 
-		sub.EndDate = sql.NullTime{
-			Time:  tmpDate,
-			Valid: true,
-		}
+	if req.Price != nil {
+		dataMap["price"] = *req.Price
 	}
 
-	return sub, nil
-}
+	if req.Currency != "" {
+		dataMap["currency"] = req.Currency
+	}
 
-func SubscriptionRequestToMap(req dto.SubscriptionUpdateReq) (map[string]any, error) {
-	dataMap := map[string]any{
-		"service_name": req.ServiceName,
-		"user_id":      req.UserId,
-		"price":        req.Price,
+	if req.Category != "" {
+		dataMap["category"] = req.Category
 	}
-	tmpDate, err := time.Parse("01-2006", req.StartDate)
-	if err != nil {
-		return nil, fmt.Errorf("StartDate parse - %s", req.StartDate)
+
+	if req.BillingPeriod != "" {
+		dataMap["billing_period"] = entities.BillingPeriodType(req.BillingPeriod)
+		dataMap["billing_interval"] = uint16(1)
+		if req.BillingPeriod == string(entities.BillingPeriodCustom) {
+			dataMap["billing_interval"] = req.BillingInterval
+		}
+	}
+
+	if req.StartDate != nil {
+		tmpDate, err := time.Parse("01-2006", *req.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("StartDate parse - %s", *req.StartDate)
+		}
+		dataMap["start_date"] = tmpDate
 	}
-	dataMap["start_date"] = tmpDate
 
 	if req.EndDate != "" {
 		tmpDate, err := time.Parse("01-2006", req.EndDate)
@@ -58,22 +81,107 @@ func SubscriptionRequestToMap(req dto.SubscriptionUpdateReq) (map[string]any, er
 		dataMap["end_date"] = tmpDate
 	}
 
+	if req.TrialEndDate != "" {
+		tmpDate, err := time.Parse("2006-01-02", req.TrialEndDate)
+		if err != nil {
+			return nil, fmt.Errorf("TrialEndDate parse - %s", req.TrialEndDate)
+		}
+		dataMap["trial_end_date"] = tmpDate
+	}
+
 	return dataMap, nil
 }
 
 func SubscriptionEntityToResponse(entity entities.Subscription) dto.SubscriptionResp {
 
 	resp := dto.SubscriptionResp{
-		ServiceName: entity.ServiceName,
-		UserId:      entity.UserId,
-		Price:       entity.Price,
-		StartDate:   entity.StartDate.Format("01-2006"),
+		ID:              entity.ID,
+		ServiceName:     entity.ServiceName,
+		UserId:          entity.UserId,
+		Price:           entity.Price,
+		Currency:        entity.Currency,
+		ConvertedPrice:  ConvertedToResponse(entity.ConvertedPrice),
+		BillingPeriod:   string(entity.BillingPeriod),
+		BillingInterval: entity.BillingInterval,
+		StartDate:       entity.StartDate.Format("01-2006"),
+		Status:          string(entity.Status),
+		StatusChangedAt: entity.StatusChangedAt.Format(time.RFC3339),
+		Version:         entity.Version,
+		CreatedAt:       entity.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       entity.UpdatedAt.Format(time.RFC3339),
+		Category:        entity.Category,
+		Tags:            []string(entity.Tags),
+	}
+
+	if resp.Tags == nil {
+		resp.Tags = []string{}
 	}
 
 	if entity.EndDate.Valid {
 		resp.EndDate = entity.EndDate.Time.Format("01-2006")
 	}
 
+	if entity.TrialEndDate.Valid {
+		resp.TrialEndDate = entity.TrialEndDate.Time.Format("2006-01-02")
+	}
+
+	if entity.DeletedAt.Valid {
+		resp.DeletedAt = entity.DeletedAt.Time.Format(time.RFC3339)
+	}
+
+	if entity.ServiceID.Valid {
+		resp.ServiceID = &entity.ServiceID.Int64
+	}
+
+	return resp
+}
+
+// BulkItemsToResponse - counts succeeded and failed items of the bulk
+func BulkItemsToResponse(mode entities.BulkModeType, committed bool, items []dto.BulkItemResp) dto.BulkResp {
+	resp := dto.BulkResp{
+		Mode:      string(mode),
+		Committed: committed,
+		Items:     items,
+	}
+
+	for _, item := range items {
+		switch item.Status {
+		case dto.BulkItemStatusOK:
+			resp.Succeeded++
+		case dto.BulkItemStatusFailed:
+			resp.Failed++
+		}
+	}
+
+	return resp
+}
+
+func SubscriptionStatusHistoryToResponse(history []entities.SubscriptionStatusChange) []dto.SubscriptionStatusResp {
+	resp := make([]dto.SubscriptionStatusResp, 0, len(history))
+	for _, change := range history {
+		item := dto.SubscriptionStatusResp{
+			Status:    string(change.Status),
+			StartedAt: change.StartedAt.Format(time.RFC3339),
+		}
+		if change.EndedAt.Valid {
+			item.EndedAt = change.EndedAt.Time.Format(time.RFC3339)
+		}
+		resp = append(resp, item)
+	}
+
+	return resp
+}
+
+func PriceHistoryToResponse(prices []entities.PriceChange) []dto.PriceResp {
+	resp := make([]dto.PriceResp, 0, len(prices))
+	for _, price := range prices {
+		resp = append(resp, dto.PriceResp{
+			Price:         price.Price,
+			EffectiveFrom: price.EffectiveFrom.Format("01-2006"),
+			CreatedAt:     price.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
 	return resp
 }
 
@@ -92,16 +200,14 @@ func SubscriptionQueryParamsToQueryCriteria(params dto.QueryParamList) (*entitie
 		tmpUUID       uuid.UUID
 		err           error
 	)
-	if params.SortBy == "" {
-		queryCriteria.Sort.SortBy = entities.SortTypeID
-	} else {
-		queryCriteria.Sort.SortBy = entities.SortType(params.SortBy)
+	sortBy := params.SortBy
+	if sortBy == "" && params.Q != "" {
+		// search results go from the most similar service name
+		sortBy = fmt.Sprintf("%s:%s", entities.SortTypeRelevance, entities.SortOrderTypeDesc)
 	}
-
-	if params.SortOrder == "" {
-		queryCriteria.Sort.SortOrder = entities.SortOrderTypeAsc
-	} else {
-		queryCriteria.Sort.SortOrder = entities.SortOrderType(params.SortOrder)
+	queryCriteria.Sort, err = ParseSort(sortBy, params.SortOrder)
+	if err != nil {
+		return nil, err
 	}
 
 	if params.Page == 0 {
@@ -120,6 +226,9 @@ func SubscriptionQueryParamsToQueryCriteria(params dto.QueryParamList) (*entitie
 		queryCriteria.Filter.ServiceName = params.ServiceName
 	}
 
+	queryCriteria.Currency = params.Currency
+	queryCriteria.Filter.IncludeDeleted = params.IncludeDeleted
+
 	if params.UserId != "" {
 		tmpUUID, err = uuid.Parse(params.UserId)
 		if err != nil {
@@ -146,20 +255,92 @@ func SubscriptionQueryParamsToQueryCriteria(params dto.QueryParamList) (*entitie
 		queryCriteria.Filter.StartDate.To = &tmpTime
 	}
 
+	if err := richQueryParamsToFilter(params, &queryCriteria.Filter); err != nil {
+		return nil, err
+	}
+
+	if params.Cursor != "" {
+		queryCriteria.Pagination.Cursor, err = DecodeCursor(params.Cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if params.WithTotal != "" {
+		withTotal, err := strconv.ParseBool(params.WithTotal)
+		if err != nil {
+			return nil, fmt.Errorf("WithTotal parse - %s", params.WithTotal)
+		}
+
+		queryCriteria.Pagination.WithoutTotal = !withTotal
+	}
+
 	return &queryCriteria, nil
 }
 
+// richQueryParamsToFilter - sets filters of the list by lists, ranges and matching of the service name
+func richQueryParamsToFilter(params dto.QueryParamList, filter *entities.FilterParams) error {
+	filter.ServiceNames = params.ServiceNames
+	filter.ServiceNamePrefix = params.ServiceNamePrefix
+	filter.ServiceNameContains = params.ServiceNameContains
+	filter.NoEndDate = params.NoEndDate
+	filter.Search = params.Q
+	filter.Tag = params.Tag
+	filter.Category = params.Category
+
+	for _, userId := range params.UserIds {
+		tmpUUID, err := uuid.Parse(userId)
+		if err != nil {
+			return fmt.Errorf("UUID parse - %s", userId)
+		}
+		filter.UserIds = append(filter.UserIds, tmpUUID)
+	}
+
+	if params.PriceMin != 0 {
+		filter.Price.Min = &params.PriceMin
+	}
+	if params.PriceMax != 0 {
+		filter.Price.Max = &params.PriceMax
+	}
+
+	for _, date := range []struct {
+		value  string
+		name   string
+		target **time.Time
+	}{
+		{value: params.EndDateFrom, name: "EndDateFrom", target: &filter.EndDate.From},
+		{value: params.EndDateTo, name: "EndDateTo", target: &filter.EndDate.To},
+		{value: params.ActiveAt, name: "ActiveAt", target: &filter.ActiveAt},
+	} {
+		if date.value == "" {
+			continue
+		}
+
+		tmpTime, err := time.Parse("01-2006", date.value)
+		if err != nil {
+			return fmt.Errorf("%s parse - %s", date.name, date.value)
+		}
+		*date.target = &tmpTime
+	}
+
+	return nil
+}
+
 func SubscriptionQueryParamsCostToFilterParam(params dto.QueryParamCost) (entities.FilterParams, error) {
 	var (
-		filter entities.FilterParams
-		tmpUUID       uuid.UUID
-		err           error
+		filter  entities.FilterParams
+		tmpUUID uuid.UUID
+		err     error
 	)
 
 	if params.ServiceName != "" {
 		filter.ServiceName = params.ServiceName
 	}
 
+	filter.IncludeDeleted = params.IncludeDeleted
+	filter.Tag = params.Tag
+	filter.Category = params.Category
+
 	if params.UserId != "" {
 		tmpUUID, err = uuid.Parse(params.UserId)
 		if err != nil {
@@ -175,7 +356,7 @@ func SubscriptionQueryParamsCostToFilterParam(params dto.QueryParamCost) (entiti
 			return entities.FilterParams{}, fmt.Errorf("StartDate parse - %s", params.StartDate)
 		}
 
-		filter.StartDate.From = &tmpTime
+		filter.Period.From = &tmpTime
 	}
 	if params.EndDate != "" {
 		tmpTime, err := time.Parse("01-2006", params.EndDate)
@@ -183,8 +364,198 @@ func SubscriptionQueryParamsCostToFilterParam(params dto.QueryParamCost) (entiti
 			return entities.FilterParams{}, fmt.Errorf("EndDate parse - %s", params.EndDate)
 		}
 
-		filter.StartDate.To = &tmpTime
+		filter.Period.To = &tmpTime
 	}
 
 	return filter, nil
 }
+
+func CostTotalsToResponse(totals []entities.CostTotal) []dto.CostTotalResp {
+	resp := make([]dto.CostTotalResp, 0, len(totals))
+	for _, total := range totals {
+		resp = append(resp, dto.CostTotalResp{Currency: total.Currency, Cost: total.Cost})
+	}
+
+	return resp
+}
+
+func CostEntityToResponse(cost entities.Cost) dto.CostResp {
+	resp := dto.CostResp{
+		Totals:         CostTotalsToResponse(cost.Totals),
+		TotalConverted: ConvertedToResponse(cost.TotalConverted),
+		Items:          make([]dto.CostItemResp, 0, len(cost.Items)),
+	}
+
+	for _, item := range cost.Items {
+		resp.Items = append(resp.Items, dto.CostItemResp{
+			ID:              item.SubscriptionID,
+			ServiceName:     item.ServiceName,
+			UserId:          item.UserId,
+			Price:           item.Price,
+			Currency:        item.Currency,
+			BillingPeriod:   string(item.BillingPeriod),
+			BillingInterval: item.BillingInterval,
+			Months:          item.Months,
+			Cost:            item.Cost,
+			ConvertedCost:   ConvertedToResponse(item.ConvertedCost),
+		})
+	}
+
+	return resp
+}
+
+func CostMonthlyToResponse(months []entities.CostMonth) []dto.CostMonthResp {
+	resp := make([]dto.CostMonthResp, 0, len(months))
+	for _, month := range months {
+		services := make([]dto.CostMonthServiceResp, 0, len(month.Services))
+		for _, service := range month.Services {
+			services = append(services, dto.CostMonthServiceResp{
+				ServiceName:   service.ServiceName,
+				Currency:      service.Currency,
+				Subscriptions: service.Subscriptions,
+				Cost:          service.Cost,
+			})
+		}
+
+		resp = append(resp, dto.CostMonthResp{
+			Month:         month.Month.Format("01-2006"),
+			Subscriptions: month.Subscriptions,
+			Totals:        CostTotalsToResponse(month.Totals),
+			Services:      services,
+		})
+	}
+
+	return resp
+}
+
+func SubscriptionQueryParamsCostGroupedToFilterParam(params dto.QueryParamCostGrouped) (entities.FilterParams, []entities.CostGroupType, error) {
+	filter, err := SubscriptionQueryParamsCostToFilterParam(dto.QueryParamCost{
+		ServiceName: params.ServiceName,
+		UserId:      params.UserId,
+		StartDate:   params.StartDate,
+		EndDate:     params.EndDate,
+		Tag:         params.Tag,
+		Category:    params.Category,
+
+		IncludeDeleted: params.IncludeDeleted,
+	})
+	if err != nil {
+		return entities.FilterParams{}, nil, err
+	}
+
+	groupBy := make([]entities.CostGroupType, 0, 2)
+	seen := make(map[entities.CostGroupType]bool)
+	for _, val := range strings.Split(params.GroupBy, ",") {
+		group := entities.CostGroupType(strings.TrimSpace(val))
+		if _, ok := entities.CostGroupTypes[string(group)]; !ok {
+			return entities.FilterParams{}, nil, fmt.Errorf("GroupBy parse - %s", val)
+		}
+
+		if seen[group] {
+			continue
+		}
+		seen[group] = true
+		groupBy = append(groupBy, group)
+	}
+
+	return filter, groupBy, nil
+}
+
+// CostGroupedToResponse - category and tag are set when grouped by them, the empty ones group uncategorized and untagged spend
+func CostGroupedToResponse(groups []entities.CostGroup, groupBy []entities.CostGroupType) []dto.CostGroupResp {
+	resp := make([]dto.CostGroupResp, 0, len(groups))
+	for _, group := range groups {
+		item := dto.CostGroupResp{
+			ServiceName:   group.ServiceName,
+			Currency:      group.Currency,
+			Subscriptions: group.Subscriptions,
+			Cost:          group.Cost,
+		}
+
+		if group.UserId != uuid.Nil {
+			userId := group.UserId
+			item.UserId = &userId
+		}
+
+		if slices.Contains(groupBy, entities.CostGroupTypeCategory) {
+			category := group.Category
+			item.Category = &category
+		}
+
+		if slices.Contains(groupBy, entities.CostGroupTypeTag) {
+			tag := group.Tag
+			item.Tag = &tag
+		}
+
+		resp = append(resp, item)
+	}
+
+	return resp
+}
+
+func ConvertedToResponse(converted *entities.Converted) *dto.ConvertedResp {
+	if converted == nil {
+		return nil
+	}
+
+	return &dto.ConvertedResp{
+		Amount:   converted.Amount,
+		Currency: converted.Currency,
+		Rate:     converted.Rate,
+	}
+}
+
+func AuditQueryParamsToFilter(params dto.QueryParamAudit) (entities.AuditFilter, error) {
+	filter := entities.AuditFilter{
+		EntityType: entities.AuditEntitySubscription,
+		EntityID:   params.EntityID,
+		Actor:      params.Actor,
+		Pagination: entities.PaginationParams{Page: 1, Limit: 20},
+	}
+
+	if params.Page != 0 {
+		filter.Pagination.Page = uint64(params.Page)
+	}
+
+	if params.Limit != 0 {
+		filter.Pagination.Limit = uint64(params.Limit)
+	}
+
+	if params.From != "" {
+		tmpTime, err := time.Parse(time.RFC3339, params.From)
+		if err != nil {
+			return entities.AuditFilter{}, fmt.Errorf("From parse - %s", params.From)
+		}
+
+		filter.Period.From = &tmpTime
+	}
+	if params.To != "" {
+		tmpTime, err := time.Parse(time.RFC3339, params.To)
+		if err != nil {
+			return entities.AuditFilter{}, fmt.Errorf("To parse - %s", params.To)
+		}
+
+		filter.Period.To = &tmpTime
+	}
+
+	return filter, nil
+}
+
+func AuditEntriesToResponse(entries []entities.AuditEntry) []dto.AuditResp {
+	resp := make([]dto.AuditResp, 0, len(entries))
+	for _, entry := range entries {
+		resp = append(resp, dto.AuditResp{
+			ID:         entry.ID,
+			EntityType: entry.EntityType,
+			EntityID:   entry.EntityID,
+			Action:     string(entry.Action),
+			Actor:      entry.Actor,
+			Before:     entry.Before,
+			After:      entry.After,
+			Diff:       entry.Diff,
+			CreatedAt:  entry.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return resp
+}
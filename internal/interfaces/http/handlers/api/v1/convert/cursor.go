@@ -0,0 +1,59 @@
+package convert
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+)
+
+// cursorToken - content of the opaque cursor token
+type cursorToken struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v,omitempty"`
+	ID     int64    `json:"i"`
+}
+
+// EncodeCursor - encodes the cursor into the opaque URL-safe token
+func EncodeCursor(cursor entities.Cursor) string {
+	data, _ := json.Marshal(cursorToken{
+		Sort:   FormatSort(cursor.Sort),
+		Values: cursor.Values,
+		ID:     cursor.ID,
+	})
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor - decodes the token of EncodeCursor, there must be a value for every sort key before id
+func DecodeCursor(token string) (*entities.Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("cursor decode - %s", token)
+	}
+
+	var tmp cursorToken
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return nil, fmt.Errorf("cursor unmarshal - %s", token)
+	}
+
+	sort, err := ParseSort(tmp.Sort, "")
+	if err != nil {
+		return nil, fmt.Errorf("cursor sort - %s", tmp.Sort)
+	}
+
+	keys := 0
+	for keys < len(sort) && sort[keys].SortBy != entities.SortTypeID {
+		keys++
+	}
+	if len(tmp.Values) != keys {
+		return nil, fmt.Errorf("cursor values - %d for %d keys", len(tmp.Values), keys)
+	}
+
+	return &entities.Cursor{
+		Sort:   sort,
+		Values: tmp.Values,
+		ID:     tmp.ID,
+	}, nil
+}
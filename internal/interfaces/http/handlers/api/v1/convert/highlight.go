@@ -0,0 +1,99 @@
+package convert
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	highlightOpen  = "<mark>"
+	highlightClose = "</mark>"
+	// minFuzzyPrefix - shortest common prefix marked for the word without the exact match
+	minFuzzyPrefix = 3
+)
+
+// HighlightServiceName - marks the matches of the search words in the service name by <mark>, case-insensitive.
+// The word without the exact match marks its longest common prefix with a word of the name,
+// as the fuzzy search finds "Netflix" by "netflx". The rest of the name is HTML-escaped.
+func HighlightServiceName(name, search string) string {
+	runes := []rune(name)
+	lower := lowerRunes(name)
+	marked := make([]bool, len(runes))
+
+	for _, word := range strings.Fields(search) {
+		if !markMatches(lower, lowerRunes(word), marked) {
+			markPrefix(lower, lowerRunes(word), marked)
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(runes); {
+		j := i
+		for j < len(runes) && marked[j] == marked[i] {
+			j++
+		}
+
+		text := html.EscapeString(string(runes[i:j]))
+		if marked[i] {
+			text = highlightOpen + text + highlightClose
+		}
+		b.WriteString(text)
+		i = j
+	}
+
+	return b.String()
+}
+
+// lowerRunes - runes of the lower-cased string, one for every rune of it
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+
+	return runes
+}
+
+// markMatches - marks every occurrence of the word in the name, reports whether there was any
+func markMatches(name, word []rune, marked []bool) bool {
+	found := false
+	for i := 0; i+len(word) <= len(name); i++ {
+		if string(name[i:i+len(word)]) != string(word) {
+			continue
+		}
+
+		for k := i; k < i+len(word); k++ {
+			marked[k] = true
+		}
+		found = true
+	}
+
+	return found
+}
+
+// markPrefix - marks the longest common prefix of the word and a word of the name
+func markPrefix(name, word []rune, marked []bool) {
+	start, length := 0, 0
+	for i := range name {
+		if i > 0 && (unicode.IsLetter(name[i-1]) || unicode.IsDigit(name[i-1])) {
+			continue
+		}
+
+		n := 0
+		for n < len(word) && i+n < len(name) && name[i+n] == word[n] {
+			n++
+		}
+		if n > length {
+			start, length = i, n
+		}
+	}
+
+	if length < minFuzzyPrefix {
+		return
+	}
+
+	for k := start; k < start+length; k++ {
+		marked[k] = true
+	}
+}
@@ -0,0 +1,35 @@
+package convert
+
+import (
+	"time"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/dto"
+)
+
+func MembersRequestToEntity(req dto.SubscriptionMembersReq) []entities.Member {
+	members := make([]entities.Member, 0, len(req.Members))
+	for _, member := range req.Members {
+		members = append(members, entities.Member{
+			UserId:    member.UserId,
+			ShareType: entities.ShareType(member.ShareType),
+			Share:     member.Share,
+		})
+	}
+
+	return members
+}
+
+func MembersToResponse(members []entities.Member) []dto.MemberResp {
+	resp := make([]dto.MemberResp, 0, len(members))
+	for _, member := range members {
+		resp = append(resp, dto.MemberResp{
+			UserId:    member.UserId,
+			ShareType: string(member.ShareType),
+			Share:     member.Share,
+			CreatedAt: member.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return resp
+}
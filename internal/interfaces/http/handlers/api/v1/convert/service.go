@@ -0,0 +1,97 @@
+package convert
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/dto"
+)
+
+func ServiceRequestToEntity(req dto.ServiceReq) entities.Service {
+	service := entities.Service{
+		Name:      req.Name,
+		Category:  req.Category,
+		VendorURL: req.VendorURL,
+		Currency:  req.Currency,
+	}
+
+	if req.DefaultPrice != 0 {
+		service.DefaultPrice = sql.NullInt64{Int64: int64(req.DefaultPrice), Valid: true}
+	}
+
+	return service
+}
+
+// ServiceRequestToMap - fields of the service set in the request
+func ServiceRequestToMap(req dto.ServiceUpdateReq) map[string]any {
+	dataMap := map[string]any{}
+
+	if req.Name != "" {
+		dataMap["name"] = req.Name
+	}
+
+	if req.Category != "" {
+		dataMap["category"] = req.Category
+	}
+
+	if req.VendorURL != "" {
+		dataMap["vendor_url"] = req.VendorURL
+	}
+
+	if req.DefaultPrice != 0 {
+		dataMap["default_price"] = req.DefaultPrice
+	}
+
+	if req.Currency != "" {
+		dataMap["currency"] = req.Currency
+	}
+
+	return dataMap
+}
+
+func ServiceEntityToResponse(entity entities.Service) dto.ServiceResp {
+	resp := dto.ServiceResp{
+		ID:        entity.ID,
+		Name:      entity.Name,
+		Category:  entity.Category,
+		VendorURL: entity.VendorURL,
+		Currency:  entity.Currency,
+		CreatedAt: entity.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: entity.UpdatedAt.Format(time.RFC3339),
+	}
+
+	if entity.DefaultPrice.Valid {
+		price := uint32(entity.DefaultPrice.Int64)
+		resp.DefaultPrice = &price
+	}
+
+	return resp
+}
+
+func ServicesToResponse(services []entities.Service) []dto.ServiceResp {
+	resp := make([]dto.ServiceResp, 0, len(services))
+	for _, service := range services {
+		resp = append(resp, ServiceEntityToResponse(service))
+	}
+
+	return resp
+}
+
+func ServiceQueryParamsToFilter(params dto.QueryParamServices) entities.ServiceFilter {
+	filter := entities.ServiceFilter{
+		Name:       params.Name,
+		Category:   params.Category,
+		Pagination: entities.PaginationParams{Page: 1, Limit: 20},
+	}
+
+	if params.Page != 0 {
+		filter.Pagination.Page = uint64(params.Page)
+	}
+
+	if params.Limit != 0 {
+		filter.Pagination.Limit = uint64(params.Limit)
+	}
+
+	return filter
+}
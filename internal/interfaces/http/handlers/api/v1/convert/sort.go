@@ -0,0 +1,58 @@
+package convert
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+)
+
+// ParseSort - parses keys of the sort `key[:order],...` in the order of priority,
+// keys without the order are sorted in defaultOrder. Without keys rows are sorted by id.
+func ParseSort(sort, defaultOrder string) ([]entities.SortParams, error) {
+	if defaultOrder == "" {
+		defaultOrder = string(entities.SortOrderTypeAsc)
+	}
+	defaultOrder = strings.ToUpper(defaultOrder)
+	if !entities.SortOrderTypes[defaultOrder] {
+		return nil, fmt.Errorf("SortOrder parse - %s", defaultOrder)
+	}
+
+	if sort == "" {
+		return []entities.SortParams{{SortBy: entities.SortTypeID, SortOrder: entities.SortOrderType(defaultOrder)}}, nil
+	}
+
+	parts := strings.Split(sort, ",")
+	if len(parts) > len(entities.SortByTypes) {
+		return nil, fmt.Errorf("SortBy parse - too many keys %d", len(parts))
+	}
+
+	params := make([]entities.SortParams, 0, len(parts))
+	seen := make(map[string]bool, len(parts))
+	for _, part := range parts {
+		key, order, found := strings.Cut(strings.TrimSpace(part), ":")
+		order = strings.ToUpper(order)
+		if !found {
+			order = defaultOrder
+		}
+
+		if !entities.SortByTypes[key] || !entities.SortOrderTypes[order] || seen[key] {
+			return nil, fmt.Errorf("SortBy parse - %s", part)
+		}
+		seen[key] = true
+
+		params = append(params, entities.SortParams{SortBy: entities.SortType(key), SortOrder: entities.SortOrderType(order)})
+	}
+
+	return params, nil
+}
+
+// FormatSort - formats the sort as parsed by ParseSort
+func FormatSort(params []entities.SortParams) string {
+	keys := make([]string, 0, len(params))
+	for _, key := range params {
+		keys = append(keys, fmt.Sprintf("%s:%s", key.SortBy, key.SortOrder))
+	}
+
+	return strings.Join(keys, ",")
+}
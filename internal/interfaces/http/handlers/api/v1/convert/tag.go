@@ -0,0 +1,43 @@
+package convert
+
+import (
+	"time"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/dto"
+)
+
+func TagEntityToResponse(entity entities.Tag) dto.TagResp {
+	return dto.TagResp{
+		ID:            entity.ID,
+		Name:          entity.Name,
+		Subscriptions: entity.Subscriptions,
+		CreatedAt:     entity.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func TagsToResponse(tags []entities.Tag) []dto.TagResp {
+	resp := make([]dto.TagResp, 0, len(tags))
+	for _, tag := range tags {
+		resp = append(resp, TagEntityToResponse(tag))
+	}
+
+	return resp
+}
+
+func TagQueryParamsToFilter(params dto.QueryParamTags) entities.TagFilter {
+	filter := entities.TagFilter{
+		Name:       params.Name,
+		Pagination: entities.PaginationParams{Page: 1, Limit: 20},
+	}
+
+	if params.Page != 0 {
+		filter.Pagination.Page = uint64(params.Page)
+	}
+
+	if params.Limit != 0 {
+		filter.Pagination.Limit = uint64(params.Limit)
+	}
+
+	return filter
+}
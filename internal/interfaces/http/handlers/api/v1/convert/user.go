@@ -0,0 +1,68 @@
+package convert
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/dto"
+)
+
+func UserRequestToEntity(req dto.UserReq) entities.User {
+	return entities.User{
+		ID:    req.ID,
+		Name:  strings.TrimSpace(req.Name),
+		Email: strings.TrimSpace(req.Email),
+	}
+}
+
+// UserRequestToMap - fields of the user set in the request
+func UserRequestToMap(req dto.UserUpdateReq) map[string]any {
+	dataMap := map[string]any{}
+
+	if name := strings.TrimSpace(req.Name); name != "" {
+		dataMap["name"] = name
+	}
+
+	if email := strings.TrimSpace(req.Email); email != "" {
+		dataMap["email"] = email
+	}
+
+	return dataMap
+}
+
+func UserEntityToResponse(entity entities.User) dto.UserResp {
+	return dto.UserResp{
+		ID:        entity.ID,
+		Name:      entity.Name,
+		Email:     entity.Email,
+		CreatedAt: entity.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: entity.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func UsersToResponse(users []entities.User) []dto.UserResp {
+	resp := make([]dto.UserResp, 0, len(users))
+	for _, user := range users {
+		resp = append(resp, UserEntityToResponse(user))
+	}
+
+	return resp
+}
+
+func UserQueryParamsToFilter(params dto.QueryParamUsers) entities.UserFilter {
+	filter := entities.UserFilter{
+		Search:     strings.TrimSpace(params.Q),
+		Pagination: entities.PaginationParams{Page: 1, Limit: 20},
+	}
+
+	if params.Page != 0 {
+		filter.Pagination.Page = uint64(params.Page)
+	}
+
+	if params.Limit != 0 {
+		filter.Pagination.Limit = uint64(params.Limit)
+	}
+
+	return filter
+}
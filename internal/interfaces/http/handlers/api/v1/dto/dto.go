@@ -1,35 +1,194 @@
 package dto
 
 import (
+	"encoding/json"
+
 	"github.com/google/uuid"
 )
 
 type SubscriptionReq struct {
-	ServiceName string    `json:"service_name" validate:"required" example:"TestService"`
-	UserId      uuid.UUID `json:"user_id"  validate:"required,uuid" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
-	Price       uint32    `json:"price"  validate:"required,gte=1,lte=4294967295" example:"100"`
-	StartDate   string    `json:"start_date"  validate:"required,datetime=01-2006" example:"12-2001"`
-	EndDate     string    `json:"end_date"  validate:"omitempty,datetime=01-2006" example:"12-2002"`
+	ServiceName     string    `json:"service_name" validate:"required" example:"TestService"`
+	UserId          uuid.UUID `json:"user_id"  validate:"required,uuid" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	Price           uint32    `json:"price"  validate:"required,gte=1,lte=4294967295" example:"100"`
+	Currency        string    `json:"currency" validate:"omitempty,iso4217" example:"RUB"`
+	BillingPeriod   string    `json:"billing_period" validate:"omitempty,oneof=weekly monthly quarterly yearly custom" example:"monthly"`
+	BillingInterval uint16    `json:"billing_interval" validate:"required_if=BillingPeriod custom,omitempty,gte=1,lte=120" example:"1"`
+	StartDate       string    `json:"start_date"  validate:"required,datetime=01-2006" example:"12-2001"`
+	EndDate         string    `json:"end_date"  validate:"omitempty,datetime=01-2006" example:"12-2002"`
+	TrialEndDate    string    `json:"trial_end_date" validate:"omitempty,datetime=2006-01-02" example:"2002-01-31"`
+	Category        string    `json:"category" validate:"omitempty,max=100" example:"streaming"`
+	Tags            []string  `json:"tags" validate:"omitempty,max=20,dive,min=1,max=50" example:"family,video"`
 }
 
+// SubscriptionUpdateReq - fields left out of the request are not changed
 type SubscriptionUpdateReq struct {
-	ServiceName string    `json:"service_name" validate:"omitempty" example:"TestService"`
-	UserId      uuid.UUID `json:"user_id"  validate:"omitempty,uuid" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
-	Price       uint32    `json:"price"  validate:"omitempty,gte=1,lte=4294967295" example:"100"`
-	StartDate   string    `json:"start_date"  validate:"omitempty,datetime=01-2006" example:"12-2001"`
-	EndDate     string    `json:"end_date"  validate:"omitempty,datetime=01-2006" example:"12-2002"`
+	ServiceName     *string    `json:"service_name" validate:"omitempty,min=1" example:"TestService"`
+	UserId          *uuid.UUID `json:"user_id"  validate:"omitempty,uuid" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	Price           *uint32    `json:"price"  validate:"omitempty,gte=1,lte=4294967295" example:"100"`
+	Currency        string     `json:"currency" validate:"omitempty,iso4217" example:"RUB"`
+	BillingPeriod   string     `json:"billing_period" validate:"omitempty,oneof=weekly monthly quarterly yearly custom" example:"monthly"`
+	BillingInterval uint16     `json:"billing_interval" validate:"required_if=BillingPeriod custom,omitempty,gte=1,lte=120" example:"1"`
+	StartDate       *string    `json:"start_date"  validate:"omitempty,datetime=01-2006" example:"12-2001"`
+	EndDate         string     `json:"end_date"  validate:"omitempty,datetime=01-2006" example:"12-2002"`
+	TrialEndDate    string     `json:"trial_end_date" validate:"omitempty,datetime=2006-01-02" example:"2002-01-31"`
+	Category        string     `json:"category" validate:"omitempty,max=100" example:"streaming"`
+}
+
+type SubscriptionBulkUpdateReq struct {
+	ID      int64 `json:"id" validate:"required,gte=1" example:"1"`
+	Version int64 `json:"version" validate:"omitempty,gte=1" example:"3"`
+	SubscriptionUpdateReq
 }
 
 type SubscriptionResp struct {
-	ServiceName string    `json:"service_name"`
-	UserId      uuid.UUID `json:"user_id"`
-	Price       uint32    `json:"price"`
-	StartDate   string    `json:"start_date"`
-	EndDate     string    `json:"end_date"`
+	ID              int64          `json:"id" example:"1"`
+	ServiceName     string         `json:"service_name"`
+	ServiceID       *int64         `json:"service_id,omitempty" example:"1"`
+	UserId          uuid.UUID      `json:"user_id"`
+	Price           uint32         `json:"price"`
+	Currency        string         `json:"currency"`
+	ConvertedPrice  *ConvertedResp `json:"converted_price,omitempty"`
+	BillingPeriod   string         `json:"billing_period"`
+	BillingInterval uint16         `json:"billing_interval"`
+	StartDate       string         `json:"start_date"`
+	EndDate         string         `json:"end_date"`
+	TrialEndDate    string         `json:"trial_end_date" example:"2002-01-31"`
+	Status          string         `json:"status" example:"active"`
+	StatusChangedAt string         `json:"status_changed_at" example:"2025-01-31T10:00:00Z"`
+	DeletedAt       string         `json:"deleted_at,omitempty" example:"2025-02-01T10:00:00Z"`
+	Version         int64          `json:"version" example:"3"`
+	CreatedAt       string         `json:"created_at" example:"2025-01-31T10:00:00Z"`
+	UpdatedAt       string         `json:"updated_at" example:"2025-01-31T10:00:00Z"`
+	Category        string         `json:"category,omitempty" example:"streaming"`
+	Tags            []string       `json:"tags" example:"family,video"`
+	// Highlight - service name with the matches of the search marked by <mark>, set only by the search
+	Highlight string `json:"highlight,omitempty" example:"<mark>Netf</mark>lix"`
+}
+
+// SubscriptionListResp - page of subscriptions, NextCursor is the cursor of the next page and is set when it exists
+type SubscriptionListResp struct {
+	Data       []SubscriptionResp `json:"data"`
+	NextCursor string             `json:"next_cursor,omitempty" example:"eyJpZCI6MTB9"`
+}
+
+type SubscriptionStatusResp struct {
+	Status    string `json:"status" example:"paused"`
+	StartedAt string `json:"started_at" example:"2025-01-31T10:00:00Z"`
+	EndedAt   string `json:"ended_at,omitempty" example:"2025-03-01T10:00:00Z"`
+}
+
+type PriceResp struct {
+	Price         uint32 `json:"price" example:"100"`
+	EffectiveFrom string `json:"effective_from" example:"01-2025"`
+	CreatedAt     string `json:"created_at" example:"2025-01-31T10:00:00Z"`
+}
+
+// Statuses of the bulk item, skipped items are not saved because the atomic bulk is rolled back
+const (
+	BulkItemStatusOK      = "ok"
+	BulkItemStatusFailed  = "failed"
+	BulkItemStatusSkipped = "skipped"
+)
+
+type BulkItemResp struct {
+	Index  int    `json:"index" example:"0"`
+	ID     int64  `json:"id,omitempty" example:"1"`
+	Status string `json:"status" example:"ok"`
+	Error  string `json:"error,omitempty" example:"Not found"`
+}
+
+type BulkResp struct {
+	Mode      string         `json:"mode" example:"atomic"`
+	Committed bool           `json:"committed" example:"true"`
+	Succeeded int            `json:"succeeded" example:"1"`
+	Failed    int            `json:"failed" example:"0"`
+	Items     []BulkItemResp `json:"items"`
+}
+
+type ImportRowErrorResp struct {
+	Line  int    `json:"line" example:"2"`
+	Error string `json:"error" example:"price: invalid number \"abc\""`
+}
+
+type ImportResp struct {
+	DryRun          bool                 `json:"dry_run" example:"false"`
+	Rows            int                  `json:"rows" example:"2"`
+	Valid           int                  `json:"valid" example:"1"`
+	Created         int                  `json:"created" example:"1"`
+	Failed          int                  `json:"failed" example:"1"`
+	Errors          []ImportRowErrorResp `json:"errors"`
+	ErrorsTruncated bool                 `json:"errors_truncated,omitempty" example:"false"`
+}
+
+type AuditResp struct {
+	ID         int64           `json:"id" example:"1"`
+	EntityType string          `json:"entity_type" example:"subscription"`
+	EntityID   int64           `json:"entity_id" example:"1"`
+	Action     string          `json:"action" example:"update"`
+	Actor      string          `json:"actor" example:"admin"`
+	Before     json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After      json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	Diff       json.RawMessage `json:"diff,omitempty" swaggertype:"object"`
+	CreatedAt  string          `json:"created_at" example:"2025-01-31T10:00:00Z"`
+}
+
+type ConvertedResp struct {
+	Amount   float64 `json:"amount" example:"1.25"`
+	Currency string  `json:"currency" example:"USD"`
+	Rate     float64 `json:"rate,omitempty" example:"0.0125"`
+}
+
+type CostItemResp struct {
+	ID              int64          `json:"id"`
+	ServiceName     string         `json:"service_name"`
+	UserId          uuid.UUID      `json:"user_id"`
+	Price           uint32         `json:"price"`
+	Currency        string         `json:"currency"`
+	BillingPeriod   string         `json:"billing_period"`
+	BillingInterval uint16         `json:"billing_interval"`
+	Months          int64          `json:"months"`
+	Cost            int64          `json:"cost"`
+	ConvertedCost   *ConvertedResp `json:"converted_cost,omitempty"`
+}
+
+type CostTotalResp struct {
+	Currency string `json:"currency" example:"RUB"`
+	Cost     int64  `json:"cost"`
+}
+
+type CostResp struct {
+	Totals         []CostTotalResp `json:"totals"`
+	TotalConverted *ConvertedResp  `json:"total_converted,omitempty"`
+	Items          []CostItemResp  `json:"items"`
+}
+
+type CostMonthServiceResp struct {
+	ServiceName   string `json:"service_name"`
+	Currency      string `json:"currency" example:"RUB"`
+	Subscriptions int64  `json:"subscriptions"`
+	Cost          int64  `json:"cost"`
+}
+
+type CostMonthResp struct {
+	Month         string                 `json:"month" example:"01-2000"`
+	Subscriptions int64                  `json:"subscriptions"`
+	Totals        []CostTotalResp        `json:"totals"`
+	Services      []CostMonthServiceResp `json:"services"`
+}
+
+type CostGroupResp struct {
+	ServiceName   string     `json:"service_name,omitempty"`
+	UserId        *uuid.UUID `json:"user_id,omitempty"`
+	Category      *string    `json:"category,omitempty" example:"streaming"`
+	Tag           *string    `json:"tag,omitempty" example:"video"`
+	Currency      string     `json:"currency" example:"RUB"`
+	Subscriptions int64      `json:"subscriptions"`
+	Cost          int64      `json:"cost"`
 }
 
 type QueryParamList struct {
-	SortBy    string `form:"sort" query:"sort" validate:"omitempty,sort_by" example:"id"`
+	// SortBy - keys `key[:asc|desc]` separated by commas, keys without the order use SortOrder
+	SortBy    string `form:"sort" query:"sort" validate:"omitempty,max=255,sort_by" example:"price:desc,start_date:asc"`
 	SortOrder string `form:"order" query:"order" validate:"omitempty,sort_order" example:"asc"`
 
 	ServiceName string `form:"service_name" query:"service_name" validate:"omitempty,min=1,max=255" example:"TestService"`
@@ -37,8 +196,32 @@ type QueryParamList struct {
 	StartDate   string `form:"start_date" query:"start_date" validate:"omitempty,datetime=01-2006" example:"01-2000"`
 	EndDate     string `form:"end_date" query:"end_date" validate:"omitempty,datetime=01-2006" example:"01-2000"`
 
+	// ServiceNames, UserIds - repeated params, subscription matches any of them
+	ServiceNames        []string `form:"service_names" query:"service_names" validate:"omitempty,max=100,dive,min=1,max=255" collectionFormat:"multi" example:"Netflix"`
+	UserIds             []string `form:"user_ids" query:"user_ids" validate:"omitempty,max=100,dive,uuid" collectionFormat:"multi" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	ServiceNamePrefix   string   `form:"service_name_prefix" query:"service_name_prefix" validate:"omitempty,min=1,max=255" example:"net"`
+	ServiceNameContains string   `form:"service_name_contains" query:"service_name_contains" validate:"omitempty,min=1,max=255" example:"flix"`
+	PriceMin            uint32   `form:"price_min" query:"price_min" validate:"omitempty,gte=1" example:"100"`
+	PriceMax            uint32   `form:"price_max" query:"price_max" validate:"omitempty,gte=1,gtefield=PriceMin" example:"500"`
+	EndDateFrom         string   `form:"end_date_from" query:"end_date_from" validate:"omitempty,datetime=01-2006" example:"01-2025"`
+	EndDateTo           string   `form:"end_date_to" query:"end_date_to" validate:"omitempty,datetime=01-2006" example:"12-2025"`
+	ActiveAt            string   `form:"active_at" query:"active_at" validate:"omitempty,datetime=01-2006" example:"03-2025"`
+	NoEndDate           bool     `form:"no_end_date" query:"no_end_date" example:"false"`
+	// Q - fuzzy search of the service name, results are sorted by relevance unless sort is set
+	Q string `form:"q" query:"q" validate:"omitempty,min=1,max=255" example:"netf"`
+
+	Tag      string `form:"tag" query:"tag" validate:"omitempty,max=50" example:"video"`
+	Category string `form:"category" query:"category" validate:"omitempty,max=100" example:"streaming"`
+
+	Currency string `form:"currency" query:"currency" validate:"omitempty,iso4217" example:"USD"`
+
+	IncludeDeleted bool `form:"include_deleted" query:"include_deleted" example:"false"`
+
 	Page  int `form:"page" query:"page" validate:"omitempty,gte=1"`
 	Limit int `form:"page_size" query:"page_size" validate:"omitempty,gte=1,lte=100"`
+	// Cursor - token of next_cursor (X-Next-Cursor), the next page is returned after it in the sort of the cursor
+	Cursor    string `form:"cursor" query:"cursor" validate:"omitempty,max=1024,cursor"`
+	WithTotal string `form:"with_total" query:"with_total" validate:"omitempty,boolean" example:"true"`
 }
 
 type QueryParamCost struct {
@@ -46,4 +229,144 @@ type QueryParamCost struct {
 	UserId      string `form:"user_id" query:"user_id" validate:"omitempty,uuid" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
 	StartDate   string `form:"start_date" query:"start_date" validate:"omitempty,datetime=01-2006" example:"01-2000"`
 	EndDate     string `form:"end_date" query:"end_date" validate:"omitempty,datetime=01-2006" example:"01-2000"`
+	Tag         string `form:"tag" query:"tag" validate:"omitempty,max=50" example:"video"`
+	Category    string `form:"category" query:"category" validate:"omitempty,max=100" example:"streaming"`
+
+	Currency string `form:"currency" query:"currency" validate:"omitempty,iso4217" example:"USD"`
+
+	IncludeDeleted bool `form:"include_deleted" query:"include_deleted" example:"false"`
+}
+
+type QueryParamTrials struct {
+	Days int `form:"days" query:"days" validate:"omitempty,gte=1,lte=365" example:"7"`
+}
+
+type ServiceReq struct {
+	Name         string `json:"name" validate:"required,max=255" example:"Spotify"`
+	Category     string `json:"category" validate:"omitempty,max=100" example:"music"`
+	VendorURL    string `json:"vendor_url" validate:"omitempty,url,max=2048" example:"https://www.spotify.com"`
+	DefaultPrice uint32 `json:"default_price" validate:"omitempty,gte=1" example:"299"`
+	Currency     string `json:"currency" validate:"omitempty,iso4217" example:"RUB"`
+}
+
+type ServiceUpdateReq struct {
+	Name         string `json:"name" validate:"omitempty,max=255" example:"Spotify"`
+	Category     string `json:"category" validate:"omitempty,max=100" example:"music"`
+	VendorURL    string `json:"vendor_url" validate:"omitempty,url,max=2048" example:"https://www.spotify.com"`
+	DefaultPrice uint32 `json:"default_price" validate:"omitempty,gte=1" example:"299"`
+	Currency     string `json:"currency" validate:"omitempty,iso4217" example:"RUB"`
+}
+
+type ServiceResp struct {
+	ID           int64   `json:"id" example:"1"`
+	Name         string  `json:"name" example:"Spotify"`
+	Category     string  `json:"category,omitempty" example:"music"`
+	VendorURL    string  `json:"vendor_url,omitempty" example:"https://www.spotify.com"`
+	DefaultPrice *uint32 `json:"default_price,omitempty" example:"299"`
+	Currency     string  `json:"currency" example:"RUB"`
+	CreatedAt    string  `json:"created_at" example:"2025-01-31T10:00:00Z"`
+	UpdatedAt    string  `json:"updated_at" example:"2025-01-31T10:00:00Z"`
+}
+
+type QueryParamServices struct {
+	// Name - part of the name, case-insensitive
+	Name     string `form:"name" query:"name" validate:"omitempty,max=255" example:"spot"`
+	Category string `form:"category" query:"category" validate:"omitempty,max=100" example:"music"`
+
+	Page  int `form:"page" query:"page" validate:"omitempty,gte=1"`
+	Limit int `form:"page_size" query:"page_size" validate:"omitempty,gte=1,lte=100"`
+}
+
+type TagResp struct {
+	ID            int64  `json:"id" example:"1"`
+	Name          string `json:"name" example:"video"`
+	Subscriptions int64  `json:"subscriptions" example:"3"`
+	CreatedAt     string `json:"created_at" example:"2025-01-31T10:00:00Z"`
+}
+
+type TagRenameReq struct {
+	Name string `json:"name" validate:"required,max=50" example:"video"`
+}
+
+type SubscriptionTagsReq struct {
+	// Tags - replace all tags of the subscription, empty list removes them
+	Tags []string `json:"tags" validate:"max=20,dive,min=1,max=50" example:"family,video"`
+}
+
+type MemberReq struct {
+	UserId    uuid.UUID `json:"user_id" validate:"required,uuid" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	ShareType string    `json:"share_type" validate:"required,oneof=percent fixed" example:"percent"`
+	// Share - percent of the price for percent, amount of the current price in the currency of the subscription for fixed
+	Share float64 `json:"share" validate:"required,gt=0" example:"50"`
+}
+
+type SubscriptionMembersReq struct {
+	// Members - replace all members of the subscription, shares sum to 100% of the price, empty list leaves it to its user
+	Members []MemberReq `json:"members" validate:"max=50,dive"`
+}
+
+type MemberResp struct {
+	UserId    uuid.UUID `json:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	ShareType string    `json:"share_type" example:"percent"`
+	Share     float64   `json:"share" example:"50"`
+	CreatedAt string    `json:"created_at" example:"2025-01-31T10:00:00Z"`
+}
+
+type QueryParamTags struct {
+	// Name - part of the name, case-insensitive
+	Name string `form:"name" query:"name" validate:"omitempty,max=50" example:"vid"`
+
+	Page  int `form:"page" query:"page" validate:"omitempty,gte=1"`
+	Limit int `form:"page_size" query:"page_size" validate:"omitempty,gte=1,lte=100"`
+}
+
+type UserReq struct {
+	// ID - ID of the existing subscriptions of the user, generated when empty
+	ID    uuid.UUID `json:"id" validate:"omitempty,uuid" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	Name  string    `json:"name" validate:"required,max=255" example:"Ann"`
+	Email string    `json:"email" validate:"omitempty,email,max=255" example:"ann@example.com"`
+}
+
+type UserUpdateReq struct {
+	Name  string `json:"name" validate:"omitempty,max=255" example:"Ann"`
+	Email string `json:"email" validate:"omitempty,email,max=255" example:"ann@example.com"`
+}
+
+type UserResp struct {
+	ID        uuid.UUID `json:"id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	Name      string    `json:"name" example:"Ann"`
+	Email     string    `json:"email,omitempty" example:"ann@example.com"`
+	CreatedAt string    `json:"created_at" example:"2025-01-31T10:00:00Z"`
+	UpdatedAt string    `json:"updated_at" example:"2025-01-31T10:00:00Z"`
+}
+
+type QueryParamUsers struct {
+	// Q - part of the name or email, case-insensitive
+	Q string `form:"q" query:"q" validate:"omitempty,max=255" example:"ann"`
+
+	Page  int `form:"page" query:"page" validate:"omitempty,gte=1"`
+	Limit int `form:"page_size" query:"page_size" validate:"omitempty,gte=1,lte=100"`
+}
+
+type QueryParamAudit struct {
+	EntityID int64  `form:"entity_id" query:"entity_id" validate:"omitempty,gte=1" example:"1"`
+	Actor    string `form:"actor" query:"actor" validate:"omitempty,max=255" example:"admin"`
+	From     string `form:"from" query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00" example:"2025-01-01T00:00:00Z"`
+	To       string `form:"to" query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00" example:"2025-02-01T00:00:00Z"`
+
+	Page  int `form:"page" query:"page" validate:"omitempty,gte=1"`
+	Limit int `form:"page_size" query:"page_size" validate:"omitempty,gte=1,lte=100"`
+}
+
+type QueryParamCostGrouped struct {
+	GroupBy string `form:"group_by" query:"group_by" validate:"required,group_by" example:"category,tag"`
+
+	ServiceName string `form:"service_name" query:"service_name" validate:"omitempty,min=1,max=255" example:"TestService"`
+	UserId      string `form:"user_id" query:"user_id" validate:"omitempty,uuid" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	StartDate   string `form:"start_date" query:"start_date" validate:"omitempty,datetime=01-2006" example:"01-2000"`
+	EndDate     string `form:"end_date" query:"end_date" validate:"omitempty,datetime=01-2006" example:"01-2000"`
+	Tag         string `form:"tag" query:"tag" validate:"omitempty,max=50" example:"video"`
+	Category    string `form:"category" query:"category" validate:"omitempty,max=100" example:"streaming"`
+
+	IncludeDeleted bool `form:"include_deleted" query:"include_deleted" example:"false"`
 }
@@ -0,0 +1,82 @@
+package v1
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/interfaces/export"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/convert"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/dto"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/response"
+)
+
+// @Summary     export subscriptions
+// @Description Returns all subscriptions matching the filters of the list as file, pagination params are ignored.
+// @Description Rows are streamed from the database, an error in the middle of the export truncates the file.
+// @ID          SubscriptionExport
+// @Tags  	    Subscription
+// @Produce     text/csv
+// @Produce     application/x-ndjson
+// @Produce     application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param       format query string false "Format of the file" Enums(csv, jsonl, xlsx) default(csv)
+// @Param       query query dto.QueryParamList true "Query Criteria"
+// @Success     200 {file} file
+// @Header      200 {string} Content-Disposition "attachment; filename=subscriptions-2025-01-31.csv"
+// @Failure     400 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /subscription/export [get]
+func (h *HandlerSubscription) export(ctx *fiber.Ctx) error {
+	format := export.FormatType(ctx.Query("format", string(export.FormatCSV)))
+	contentType, ok := export.ContentTypes[format]
+	if !ok {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, fmt.Sprintf("unknown format %q", format))
+	}
+
+	params, ok := ctx.Locals("query_params").(dto.QueryParamList)
+	if !ok {
+		h.logger.Error("subscriptionV1.Export: get query_params", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	queryCriteria, err := convert.SubscriptionQueryParamsToQueryCriteria(params)
+	if err != nil {
+		h.logger.Error("subscriptionV1.Export: convert", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	ctx.Attachment(fmt.Sprintf("subscriptions-%s.%s", time.Now().UTC().Format(time.DateOnly), format))
+	ctx.Set(fiber.HeaderContentType, contentType)
+
+	// the body is written after the handler returns, so the fiber context must not be used in the writer
+	userCtx := ctx.UserContext()
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		writer, err := export.NewWriter(format, w)
+		if err != nil {
+			h.logger.Error("subscriptionV1.Export: new writer", map[string]any{"err": err})
+			return
+		}
+
+		err = h.uc.Export(userCtx, *queryCriteria, func(sub entities.Subscription) error {
+			return writer.Write(sub)
+		})
+		if err != nil {
+			h.logger.Error("subscriptionV1.Export: usecase exec", map[string]any{"err": err})
+			writer.Discard()
+			return
+		}
+
+		if err := writer.Close(); err != nil {
+			h.logger.Error("subscriptionV1.Export: close writer", map[string]any{"err": err})
+		}
+	})
+
+	return nil
+}
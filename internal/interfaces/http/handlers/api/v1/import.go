@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	errs "github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/csvimport"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/dto"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/response"
+)
+
+// ImportPath - path of the CSV import, the request body is read by the handler as a stream with its own limit
+const ImportPath = "/api/v1/subscription/import"
+
+// DefaultImportLimit - max size of the CSV import in bytes when the limit is not configured
+const DefaultImportLimit = 100 << 20
+
+// @Summary     import subscriptions from CSV
+// @Description Creates subscriptions from CSV, the header names the columns as the fields of the create request.
+// @Description The file is read as a stream and created in batches, invalid rows are reported with their line.
+// @Description With dry_run rows are only validated.
+// @ID          SubscriptionImport
+// @Tags  	    Subscription
+// @Accept      text/csv
+// @Produce     json
+// @Param       dry_run query bool false "Validate rows without creating subscriptions"
+// @Param       request body string true "CSV with header: service_name,user_id,price,currency,billing_period,billing_interval,start_date,end_date,trial_end_date,category,tags (tags separated by |)"
+// @Success     200 {object} dto.ImportResp
+// @Failure     411 {object} response.Error
+// @Failure     413 {object} response.Error
+// @Failure     415 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /subscription/import [post]
+func (h *HandlerSubscription) importCSV(ctx *fiber.Ctx) error {
+	if !ctx.Is("csv") {
+		return response.ErrorResponse(ctx, http.StatusUnsupportedMediaType, "content type must be text/csv")
+	}
+
+	dryRun := ctx.QueryBool("dry_run")
+
+	var body io.Reader = ctx.Context().RequestBodyStream()
+	if body == nil {
+		body = bytes.NewReader(ctx.Body())
+	}
+
+	report, err := csvimport.NewImporter(&h.uc, h.validator, h.logger).Import(ctx.UserContext(), body, dryRun)
+	if err != nil {
+		h.logger.Error("subscriptionV1.ImportCSV: usecase exec", map[string]any{"err": err})
+
+		if errors.Is(err, errs.ErrInvalidInput) {
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+		}
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(importReportToResponse(report))
+}
+
+// importReportToResponse - converts the report of the import
+func importReportToResponse(report *csvimport.Report) dto.ImportResp {
+	resp := dto.ImportResp{
+		DryRun:          report.DryRun,
+		Rows:            report.Rows,
+		Valid:           report.Valid,
+		Created:         report.Created,
+		Failed:          report.Failed,
+		Errors:          make([]dto.ImportRowErrorResp, 0, len(report.Errors)),
+		ErrorsTruncated: report.ErrorsTruncated,
+	}
+
+	for _, rowErr := range report.Errors {
+		resp.Errors = append(resp.Errors, dto.ImportRowErrorResp{Line: rowErr.Line, Error: rowErr.Message})
+	}
+
+	return resp
+}
@@ -0,0 +1,248 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	errs "github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/convert"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/dto"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/response"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/middleware"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/usecases/service"
+)
+
+type HandlerService struct {
+	validator *validator.Validate
+	uc        service.ServiceUsecase
+
+	logger observability.Logger
+}
+
+func NewServiceHandler(apiV1Group fiber.Router, validator *validator.Validate, uc service.ServiceUsecase, logger observability.Logger) {
+	router := HandlerService{
+		validator: validator,
+		uc:        uc,
+		logger:    logger,
+	}
+
+	serviceGroup := apiV1Group.Group("/services")
+	{
+		serviceGroup.Post("/", router.create)
+		serviceGroup.Get("/", middleware.ValidatedQueryParamsServicesMiddleware(logger), router.list)
+		serviceGroup.Get("/:id", middleware.ValidatedQueryIdMiddleware(logger), router.getId)
+		serviceGroup.Patch("/:id", middleware.ValidatedQueryIdMiddleware(logger), router.update)
+		serviceGroup.Delete("/:id", middleware.ValidatedQueryIdMiddleware(logger), router.delete)
+	}
+}
+
+// @Summary     Create service
+// @Description Adds the service to the catalog, subscriptions with the same name ignoring case and spaces are linked to it
+// @ID          ServiceCreate
+// @Tags  	    Service
+// @Accept      json
+// @Produce     json
+// @Param       request body dto.ServiceReq true "Data service"
+// @Success     201 {object} dto.ServiceResp
+// @Header      201 {string} Location "URL of the created service"
+// @Failure     400 {object} response.Error
+// @Failure     409 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /services [post]
+func (h *HandlerService) create(ctx *fiber.Ctx) error {
+	var body dto.ServiceReq
+	if err := ctx.BodyParser(&body); err != nil {
+		h.logger.Error("serviceV1.Create: parse body", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(body); err != nil {
+		h.logger.Error("serviceV1.Create: validate struct", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	created, err := h.uc.Create(ctx.UserContext(), convert.ServiceRequestToEntity(body))
+	if err != nil {
+		if errors.Is(err, errs.ErrAlreadyExists) {
+			h.logger.Error("serviceV1.Create: name taken", map[string]any{"name": body.Name})
+
+			return response.ErrorResponse(ctx, http.StatusConflict, "Service already exists")
+		}
+		h.logger.Error("serviceV1.Create: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	ctx.Location(fmt.Sprintf("/api/v1/services/%d", created.ID))
+
+	return ctx.Status(http.StatusCreated).JSON(convert.ServiceEntityToResponse(*created))
+}
+
+// @Summary     get services
+// @Description Returns services of the catalog ordered by name
+// @ID          ServiceList
+// @Tags  	    Service
+// @Accept      json
+// @Produce     json
+// @Param       query query dto.QueryParamServices true "Filter params"
+// @Success     200 {array} dto.ServiceResp
+// @Failure     400 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /services [get]
+func (h *HandlerService) list(ctx *fiber.Ctx) error {
+	params, ok := ctx.Locals("query_services").(dto.QueryParamServices)
+	if !ok {
+		h.logger.Error("serviceV1.List: get query_services", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	services, err := h.uc.List(ctx.UserContext(), convert.ServiceQueryParamsToFilter(params))
+	if err != nil {
+		h.logger.Error("serviceV1.List: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(convert.ServicesToResponse(services))
+}
+
+// @Summary     get service by ID
+// @Description Returns service of the catalog by ID
+// @ID          ServiceGetByID
+// @Tags  	    Service
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Service ID"
+// @Success     200 {object} dto.ServiceResp
+// @Failure     400 {object} response.Error
+// @Failure     404 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /services/{id} [get]
+func (h *HandlerService) getId(ctx *fiber.Ctx) error {
+	serviceID, ok := ctx.Locals("query_id").(int64)
+	if !ok {
+		h.logger.Error("serviceV1.GetId: get query_id", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	found, err := h.uc.GetByID(ctx.UserContext(), serviceID)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			h.logger.Error("serviceV1.GetId: not found row", map[string]any{"id": serviceID})
+
+			return response.ErrorResponse(ctx, http.StatusNotFound, "Not found")
+		}
+		h.logger.Error("serviceV1.GetId: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(convert.ServiceEntityToResponse(*found))
+}
+
+// @Summary     update service by ID
+// @Description Updates fields of the service set in the request, the new name is given to the linked subscriptions
+// @ID          ServiceUpdate
+// @Tags  	    Service
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Service ID"
+// @Param       request body dto.ServiceUpdateReq true "Data service"
+// @Success     200
+// @Failure     400 {object} response.Error
+// @Failure     404 {object} response.Error
+// @Failure     409 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /services/{id} [patch]
+func (h *HandlerService) update(ctx *fiber.Ctx) error {
+	serviceID, ok := ctx.Locals("query_id").(int64)
+	if !ok {
+		h.logger.Error("serviceV1.Update: get query_id", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	var body dto.ServiceUpdateReq
+	if err := ctx.BodyParser(&body); err != nil {
+		h.logger.Error("serviceV1.Update: parse body", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(body); err != nil {
+		h.logger.Error("serviceV1.Update: validate struct", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	fields := convert.ServiceRequestToMap(body)
+	if len(fields) == 0 {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "no fields to update")
+	}
+
+	if err := h.uc.Update(ctx.UserContext(), serviceID, fields); err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			h.logger.Error("serviceV1.Update: not found row", map[string]any{"id": serviceID})
+
+			return response.ErrorResponse(ctx, http.StatusNotFound, "Not found")
+		}
+		if errors.Is(err, errs.ErrAlreadyExists) {
+			h.logger.Error("serviceV1.Update: name taken", map[string]any{"name": body.Name})
+
+			return response.ErrorResponse(ctx, http.StatusConflict, "Service already exists")
+		}
+		h.logger.Error("serviceV1.Update: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.SendStatus(http.StatusOK)
+}
+
+// @Summary     delete service by ID
+// @Description Deletes the service from the catalog, linked subscriptions keep their names without the link
+// @ID          ServiceDelete
+// @Tags  	    Service
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Service ID"
+// @Success     204
+// @Failure     400 {object} response.Error
+// @Failure     404 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /services/{id} [delete]
+func (h *HandlerService) delete(ctx *fiber.Ctx) error {
+	serviceID, ok := ctx.Locals("query_id").(int64)
+	if !ok {
+		h.logger.Error("serviceV1.Delete: get query_id", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	if err := h.uc.Delete(ctx.UserContext(), serviceID); err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			h.logger.Error("serviceV1.Delete: not found row", map[string]any{"id": serviceID})
+
+			return response.ErrorResponse(ctx, http.StatusNotFound, "Not found")
+		}
+		h.logger.Error("serviceV1.Delete: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
@@ -1,9 +1,12 @@
 package v1
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -15,6 +18,7 @@ import (
 	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/response"
 	"github.com/mathbdw/subscription-service/internal/interfaces/http/middleware"
 	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/usecases/idempotency"
 	uc "github.com/mathbdw/subscription-service/internal/usecases/subscription"
 )
 
@@ -26,7 +30,11 @@ type HandlerSubscription struct {
 	logger observability.Logger
 }
 
-func NewHandler(apiV1Group fiber.Router, validator *validator.Validate, uc uc.SubscriptionUsecase, logger observability.Logger) {
+func NewHandler(apiV1Group fiber.Router, validator *validator.Validate, uc uc.SubscriptionUsecase, ucIdempotency idempotency.IdempotencyUsecase, importLimit int, logger observability.Logger) {
+	if importLimit <= 0 {
+		importLimit = DefaultImportLimit
+	}
+
 	router := HandlerSubscription{
 		uc:        uc,
 		validator: validator,
@@ -35,13 +43,31 @@ func NewHandler(apiV1Group fiber.Router, validator *validator.Validate, uc uc.Su
 
 	subscriptionGroup := apiV1Group.Group("/subscription")
 	{
-		subscriptionGroup.Post("/create", router.create)
+		subscriptionGroup.Post("/create", middleware.Idempotency(ucIdempotency, logger), router.create)
 		subscriptionGroup.Get("/list", middleware.ValidatedQueryParamsMiddleware(logger), router.list)
 		subscriptionGroup.Get("/cost", middleware.ValidatedQueryParamsCostMiddleware(logger), router.cost)
+		subscriptionGroup.Get("/cost/monthly", middleware.ValidatedQueryParamsCostMiddleware(logger), router.costMonthly)
+		subscriptionGroup.Get("/cost/grouped", middleware.ValidatedQueryParamsCostGroupedMiddleware(logger), router.costGrouped)
+		subscriptionGroup.Get("/trials/upcoming", middleware.ValidatedQueryParamsTrialsMiddleware(logger), router.trialsUpcoming)
+		subscriptionGroup.Get("/export", middleware.ValidatedQueryParamsMiddleware(logger), router.export)
+
+		subscriptionGroup.Post("/bulk", router.createBulk)
+		subscriptionGroup.Patch("/bulk", router.updateBulk)
+		subscriptionGroup.Delete("/bulk", router.deleteBulk)
+		subscriptionGroup.Post("/import", middleware.StreamLimit(importLimit, logger), router.importCSV)
 
 		subscriptionGroup.Get("/:id", middleware.ValidatedQueryIdMiddleware(logger), router.getId)
 		subscriptionGroup.Delete("/:id", middleware.ValidatedQueryIdMiddleware(logger), router.delete)
 		subscriptionGroup.Patch("/:id", middleware.ValidatedQueryIdMiddleware(logger), router.update)
+		subscriptionGroup.Post("/:id/pause", middleware.ValidatedQueryIdMiddleware(logger), router.pause)
+		subscriptionGroup.Post("/:id/resume", middleware.ValidatedQueryIdMiddleware(logger), router.resume)
+		subscriptionGroup.Post("/:id/cancel", middleware.ValidatedQueryIdMiddleware(logger), router.cancel)
+		subscriptionGroup.Post("/:id/restore", middleware.RequireAdmin(logger), middleware.ValidatedQueryIdMiddleware(logger), router.restore)
+		subscriptionGroup.Get("/:id/statuses", middleware.ValidatedQueryIdMiddleware(logger), router.statuses)
+		subscriptionGroup.Get("/:id/prices", middleware.ValidatedQueryIdMiddleware(logger), router.prices)
+		subscriptionGroup.Put("/:id/tags", middleware.ValidatedQueryIdMiddleware(logger), router.setTags)
+		subscriptionGroup.Get("/:id/members", middleware.ValidatedQueryIdMiddleware(logger), router.members)
+		subscriptionGroup.Put("/:id/members", middleware.ValidatedQueryIdMiddleware(logger), router.setMembers)
 	}
 
 }
@@ -53,7 +79,10 @@ func NewHandler(apiV1Group fiber.Router, validator *validator.Validate, uc uc.Su
 // @Accept      json
 // @Produce     json
 // @Param       request body dto.SubscriptionReq true "Data subscription"
-// @Success     201
+// @Param       Idempotency-Key header string false "Client key of the request, the response is replayed for the retries with the key"
+// @Success     201 {object} dto.SubscriptionResp
+// @Header      201 {string} Location "URL of the created subscription"
+// @Header      201 {string} ETag "Version of subscription"
 // @Failure     400 {object} response.Error
 // @Failure     422 {object} response.Error
 // @Failure     500 {object} response.Error
@@ -79,13 +108,22 @@ func (h *HandlerSubscription) create(ctx *fiber.Ctx) error {
 		return response.ErrorResponse(ctx, http.StatusInternalServerError, err.Error())
 	}
 
-	if err := h.uc.Create(ctx.UserContext(), sub); err != nil {
+	created, err := h.uc.Create(ctx.UserContext(), sub)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidInput) {
+			h.logger.Error("subscriptionV1.Create: unknown user", map[string]any{"user_id": body.UserId})
+
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "unknown user_id")
+		}
 		h.logger.Error("subscriptionV1.Create: usecase exec", map[string]any{"err": err})
 
 		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
 	}
 
-	return ctx.SendStatus(http.StatusCreated)
+	ctx.Location(fmt.Sprintf("/api/v1/subscription/%d", created.ID))
+	setETag(ctx, created.Version)
+
+	return ctx.Status(http.StatusCreated).JSON(convert.SubscriptionEntityToResponse(*created))
 }
 
 // @Summary     get subscription by ID
@@ -95,7 +133,9 @@ func (h *HandlerSubscription) create(ctx *fiber.Ctx) error {
 // @Accept      json
 // @Produce     json
 // @Param       id   path      int  true  "Subscription ID"
-// @Success     204
+// @Param       include_deleted query bool false "Return soft-deleted subscription, only for the admin"
+// @Success     200 {object} dto.SubscriptionResp
+// @Header      200 {string} ETag "Version of subscription"
 // @Failure     400 {object} response.Error
 // @Failure     422 {object} response.Error
 // @Failure     500 {object} response.Error
@@ -108,7 +148,7 @@ func (h *HandlerSubscription) getId(ctx *fiber.Ctx) error {
 		return response.ErrorResponse(ctx, http.StatusInternalServerError, "invalid request body")
 	}
 
-	sub, err := h.uc.GetByID(ctx.UserContext(), subID)
+	sub, err := h.uc.GetByID(ctx.UserContext(), subID, ctx.QueryBool("include_deleted"))
 	if err != nil {
 		if errors.Is(err, errs.ErrNotFound) {
 			h.logger.Error("subscriptionV1.GetId: not found row", map[string]any{"id": subID})
@@ -121,17 +161,23 @@ func (h *HandlerSubscription) getId(ctx *fiber.Ctx) error {
 	}
 
 	subResp := convert.SubscriptionEntityToResponse(*sub)
+	setETag(ctx, sub.Version)
+
 	return ctx.Status(http.StatusOK).JSON(subResp)
 }
 
 // @Summary     get list subscriptions
-// @Description Returns list subscriptions
+// @Description Returns list subscriptions by page (offset) or after the cursor of next_cursor (keyset),
+// @Description the cursor keeps the sort of the page it was returned with.
+// @Description With q service names are searched fuzzily, results are sorted by relevance and have the highlight of the matches.
 // @ID          SubscriptionList
 // @Tags  	    Subscription
 // @Accept      json
 // @Produce     json
 // @Param       query query dto.QueryParamList true "Query Criteria"
-// @Success     204
+// @Success     200 {object} dto.SubscriptionListResp
+// @Header      200 {string} X-Next-Cursor "Cursor of the next page, set when it exists"
+// @Header      200 {integer} X-Total-Count "Total count, not set with with_total=false"
 // @Failure     400 {object} response.Error
 // @Failure     422 {object} response.Error
 // @Failure     500 {object} response.Error
@@ -146,37 +192,60 @@ func (h *HandlerSubscription) list(ctx *fiber.Ctx) error {
 		})
 	}
 
+	return listSubscriptions(ctx, &h.uc, h.logger, "subscriptionV1.List", params)
+}
+
+// listSubscriptions - responds with the page of subscriptions by the query params, op prefixes the logs
+func listSubscriptions(ctx *fiber.Ctx, uc *uc.SubscriptionUsecase, logger observability.Logger, op string, params dto.QueryParamList) error {
 	queryCriteria, err := convert.SubscriptionQueryParamsToQueryCriteria(params)
 	if err != nil {
-		h.logger.Error("subscriptionV1.List: convert", map[string]any{"err": err})
+		logger.Error(op+": convert", map[string]any{"err": err})
 
 		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Internal server error",
 		})
 	}
 
-	pespList, err := h.uc.List(ctx.UserContext(), *queryCriteria)
+	pespList, err := uc.List(ctx.UserContext(), *queryCriteria)
 	if err != nil {
-		h.logger.Error("subscriptionV1.List: usecase exec", map[string]any{"err": err})
+		if errors.Is(err, errs.ErrInvalidInput) {
+			logger.Error(op+": invalid currency", map[string]any{"err": err})
+
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+		}
+		logger.Error(op+": usecase exec", map[string]any{"err": err})
 
 		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
 	}
 
 	subsResp := convert.SubscriptionListToResponse(pespList.Data)
+	if params.Q != "" {
+		for i := range subsResp {
+			subsResp[i].Highlight = convert.HighlightServiceName(subsResp[i].ServiceName, params.Q)
+		}
+	}
 	addPaginationHeaders(ctx, pespList.Info)
 
-	return ctx.Status(http.StatusOK).JSON(subsResp)
+	resp := dto.SubscriptionListResp{Data: subsResp}
+	if pespList.Info.Next != nil {
+		resp.NextCursor = convert.EncodeCursor(*pespList.Info.Next)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(resp)
 }
 
 // @Summary     delete subscription by ID
-// @Description delete subscription by ID
+// @Description soft delete subscription by ID, it can be restored until purge
 // @ID          SubscriptionDelete
 // @Tags  	    Subscription
 // @Accept      json
 // @Produce     json
 // @Success     204
 // @Param       id   path      int  true  "Subscription ID"
+// @Param       If-Match header string false "ETag of subscription, the subscription is deleted only if it was not changed since"
 // @Failure     400 {object} response.Error
+// @Failure     404 {object} response.Error
+// @Failure     412 {object} response.Error
 // @Failure     422 {object} response.Error
 // @Failure     500 {object} response.Error
 // @Router      /subscription/{id} [delete]
@@ -188,13 +257,25 @@ func (h *HandlerSubscription) delete(ctx *fiber.Ctx) error {
 		return response.ErrorResponse(ctx, http.StatusInternalServerError, "invalid request body")
 	}
 
-	err := h.uc.Delete(ctx.UserContext(), subID)
+	version, err := ifMatchVersion(ctx)
+	if err != nil {
+		h.logger.Error("subscriptionV1.Delete: parse If-Match", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "invalid If-Match header")
+	}
+
+	err = h.uc.Delete(ctx.UserContext(), subID, version)
 	if err != nil {
 		if errors.Is(err, errs.ErrNotFound) {
 			h.logger.Error("subscriptionV1.GetId: not found row", map[string]any{"id": subID})
 
 			return response.ErrorResponse(ctx, http.StatusNotFound, "Not found")
 		}
+		if errors.Is(err, errs.ErrConflict) {
+			h.logger.Error("subscriptionV1.Delete: version changed", map[string]any{"err": err})
+
+			return response.ErrorResponse(ctx, http.StatusPreconditionFailed, "Precondition failed")
+		}
 		h.logger.Error("subscriptionV1.Delete: usecase exec", map[string]any{"err": err})
 
 		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
@@ -211,8 +292,11 @@ func (h *HandlerSubscription) delete(ctx *fiber.Ctx) error {
 // @Produce     json
 // @Param       id   path      int  true  "Subscription ID"
 // @Param       request body dto.SubscriptionUpdateReq true "Data subscription"
+// @Param       If-Match header string false "ETag of subscription, the subscription is updated only if it was not changed since"
 // @Success     200
 // @Failure     400 {object} response.Error
+// @Failure     404 {object} response.Error
+// @Failure     412 {object} response.Error
 // @Failure     422 {object} response.Error
 // @Failure     500 {object} response.Error
 // @Router      /subscription/{id} [patch]
@@ -224,6 +308,13 @@ func (h *HandlerSubscription) update(ctx *fiber.Ctx) error {
 		return response.ErrorResponse(ctx, http.StatusInternalServerError, "invalid request body")
 	}
 
+	version, err := ifMatchVersion(ctx)
+	if err != nil {
+		h.logger.Error("subscriptionV1.Update: parse If-Match", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "invalid If-Match header")
+	}
+
 	var body dto.SubscriptionUpdateReq
 	if err := ctx.BodyParser(&body); err != nil {
 		h.logger.Error("subscriptionV1.Update: parse body", map[string]any{"err": err})
@@ -244,12 +335,22 @@ func (h *HandlerSubscription) update(ctx *fiber.Ctx) error {
 		return response.ErrorResponse(ctx, http.StatusInternalServerError, err.Error())
 	}
 
-	if err := h.uc.Update(ctx.UserContext(), subID, fieldsMap); err != nil {
+	if err := h.uc.Update(ctx.UserContext(), subID, fieldsMap, version); err != nil {
 		if errors.Is(err, errs.ErrNotFound) {
 			h.logger.Error("subscriptionV1.GetId: not found row", map[string]any{"id": subID})
 
 			return response.ErrorResponse(ctx, http.StatusNotFound, "Not found")
 		}
+		if errors.Is(err, errs.ErrConflict) {
+			h.logger.Error("subscriptionV1.Update: version changed", map[string]any{"err": err})
+
+			return response.ErrorResponse(ctx, http.StatusPreconditionFailed, "Precondition failed")
+		}
+		if errors.Is(err, errs.ErrInvalidInput) {
+			h.logger.Error("subscriptionV1.Update: unknown user or broken shares", map[string]any{"err": err})
+
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+		}
 		h.logger.Error("subscriptionV1.Update: usecase exec", map[string]any{"err": err})
 
 		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
@@ -259,13 +360,14 @@ func (h *HandlerSubscription) update(ctx *fiber.Ctx) error {
 }
 
 // @Summary     get cost subscriptions
-// @Description Returns cost subscriptions
+// @Description Returns cost subscriptions billed within the period with breakdown by subscription.
+// @Description Months the subscription stayed paused for entirely are not billed, a month paused partially is billed in full
 // @ID          SubscriptionCost
 // @Tags  	    Subscription
 // @Accept      json
 // @Produce     json
 // @Param       query query dto.QueryParamCost true "Filter params"
-// @Success     204
+// @Success     200 {object} dto.CostResp
 // @Failure     400 {object} response.Error
 // @Failure     422 {object} response.Error
 // @Failure     500 {object} response.Error
@@ -280,35 +382,492 @@ func (h *HandlerSubscription) cost(ctx *fiber.Ctx) error {
 		})
 	}
 
+	return costSubscriptions(ctx, &h.uc, h.logger, "subscriptionV1.Cost", params)
+}
+
+// costSubscriptions - responds with the cost of subscriptions by the query params, op prefixes the logs
+func costSubscriptions(ctx *fiber.Ctx, uc *uc.SubscriptionUsecase, logger observability.Logger, op string, params dto.QueryParamCost) error {
 	filter, err := convert.SubscriptionQueryParamsCostToFilterParam(params)
 	if err != nil {
-		h.logger.Error("subscriptionV1.Cost: convert", map[string]any{"err": err})
+		logger.Error(op+": convert", map[string]any{"err": err})
 
 		return ctx.Status(http.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Internal server error",
 		})
 	}
 
-	cost, err := h.uc.GetCost(ctx.UserContext(), filter)
+	cost, err := uc.GetCost(ctx.UserContext(), filter, params.Currency)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidInput) {
+			logger.Error(op+": invalid currency", map[string]any{"err": err})
+
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+		}
+		logger.Error(op+": usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(convert.CostEntityToResponse(*cost))
+}
+
+// @Summary     get monthly cost subscriptions
+// @Description Returns spend for every calendar month of the period with the number of active subscriptions and split by service
+// @ID          SubscriptionCostMonthly
+// @Tags  	    Subscription
+// @Accept      json
+// @Produce     json
+// @Param       query query dto.QueryParamCost true "Filter params"
+// @Success     200 {array} dto.CostMonthResp
+// @Failure     400 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /subscription/cost/monthly [get]
+func (h *HandlerSubscription) costMonthly(ctx *fiber.Ctx) error {
+	params, ok := ctx.Locals("query_cost").(dto.QueryParamCost)
+	if !ok {
+		h.logger.Error("subscriptionV1.CostMonthly: get query_cost", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	filter, err := convert.SubscriptionQueryParamsCostToFilterParam(params)
 	if err != nil {
-		h.logger.Error("subscriptionV1.Cost: usecase exec", map[string]any{"err": err})
+		h.logger.Error("subscriptionV1.CostMonthly: convert", map[string]any{"err": err})
 
 		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
 	}
 
-	return ctx.Status(http.StatusOK).JSON(cost)
+	months, err := h.uc.GetCostMonthly(ctx.UserContext(), filter)
+	if err != nil {
+		h.logger.Error("subscriptionV1.CostMonthly: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(convert.CostMonthlyToResponse(months))
 }
 
-// addPaginationHeaders - sets response headers pagination params for list
+// @Summary     get grouped cost subscriptions
+// @Description Returns cost subscriptions grouped by service, user or both, the most expensive groups go first
+// @ID          SubscriptionCostGrouped
+// @Tags  	    Subscription
+// @Accept      json
+// @Produce     json
+// @Param       query query dto.QueryParamCostGrouped true "Filter params"
+// @Success     200 {array} dto.CostGroupResp
+// @Failure     400 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /subscription/cost/grouped [get]
+func (h *HandlerSubscription) costGrouped(ctx *fiber.Ctx) error {
+	params, ok := ctx.Locals("query_cost_grouped").(dto.QueryParamCostGrouped)
+	if !ok {
+		h.logger.Error("subscriptionV1.CostGrouped: get query_cost_grouped", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	filter, groupBy, err := convert.SubscriptionQueryParamsCostGroupedToFilterParam(params)
+	if err != nil {
+		h.logger.Error("subscriptionV1.CostGrouped: convert", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	groups, err := h.uc.GetCostGrouped(ctx.UserContext(), filter, groupBy)
+	if err != nil {
+		h.logger.Error("subscriptionV1.CostGrouped: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(convert.CostGroupedToResponse(groups, groupBy))
+}
+
+// @Summary     get upcoming trial conversions
+// @Description Returns subscriptions whose free trial ends within the next days, 7 by default
+// @ID          SubscriptionTrialsUpcoming
+// @Tags  	    Subscription
+// @Accept      json
+// @Produce     json
+// @Param       query query dto.QueryParamTrials true "Query params"
+// @Success     200 {array} dto.SubscriptionResp
+// @Failure     400 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /subscription/trials/upcoming [get]
+func (h *HandlerSubscription) trialsUpcoming(ctx *fiber.Ctx) error {
+	params, ok := ctx.Locals("query_trials").(dto.QueryParamTrials)
+	if !ok {
+		h.logger.Error("subscriptionV1.TrialsUpcoming: get query_trials", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	subs, err := h.uc.UpcomingConversions(ctx.UserContext(), params.Days)
+	if err != nil {
+		h.logger.Error("subscriptionV1.TrialsUpcoming: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(convert.SubscriptionListToResponse(subs))
+}
+
+// @Summary     pause subscription by ID
+// @Description Pauses active subscription, months it stays paused for entirely are excluded from spend
+// @ID          SubscriptionPause
+// @Tags  	    Subscription
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Subscription ID"
+// @Success     200 {object} dto.SubscriptionResp
+// @Failure     400 {object} response.Error
+// @Failure     404 {object} response.Error
+// @Failure     409 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /subscription/{id}/pause [post]
+func (h *HandlerSubscription) pause(ctx *fiber.Ctx) error {
+	return h.changeStatus(ctx, "subscriptionV1.Pause", h.uc.Pause)
+}
+
+// @Summary     resume subscription by ID
+// @Description Resumes paused subscription
+// @ID          SubscriptionResume
+// @Tags  	    Subscription
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Subscription ID"
+// @Success     200 {object} dto.SubscriptionResp
+// @Failure     400 {object} response.Error
+// @Failure     404 {object} response.Error
+// @Failure     409 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /subscription/{id}/resume [post]
+func (h *HandlerSubscription) resume(ctx *fiber.Ctx) error {
+	return h.changeStatus(ctx, "subscriptionV1.Resume", h.uc.Resume)
+}
+
+// @Summary     cancel subscription by ID
+// @Description Cancels subscription, it is billed up to the current month
+// @ID          SubscriptionCancel
+// @Tags  	    Subscription
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Subscription ID"
+// @Success     200 {object} dto.SubscriptionResp
+// @Failure     400 {object} response.Error
+// @Failure     404 {object} response.Error
+// @Failure     409 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /subscription/{id}/cancel [post]
+func (h *HandlerSubscription) cancel(ctx *fiber.Ctx) error {
+	return h.changeStatus(ctx, "subscriptionV1.Cancel", h.uc.Cancel)
+}
+
+// @Summary     restore subscription by ID
+// @Description Restores soft-deleted subscription, only for the admin
+// @ID          SubscriptionRestore
+// @Tags  	    Subscription
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Subscription ID"
+// @Param       X-Admin-Token header string true "Token of the admin"
+// @Success     200 {object} dto.SubscriptionResp
+// @Failure     400 {object} response.Error
+// @Failure     403 {object} response.Error
+// @Failure     404 {object} response.Error
+// @Failure     409 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /subscription/{id}/restore [post]
+func (h *HandlerSubscription) restore(ctx *fiber.Ctx) error {
+	return h.changeStatus(ctx, "subscriptionV1.Restore", h.uc.Restore)
+}
+
+// changeStatus - runs the status transition of subscription from path
+func (h *HandlerSubscription) changeStatus(ctx *fiber.Ctx, op string, transition func(ctx context.Context, id int64) (*entities.Subscription, error)) error {
+	subID, ok := ctx.Locals("query_id").(int64)
+	if !ok {
+		h.logger.Error(op+": get query_id", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "invalid request body")
+	}
+
+	sub, err := transition(ctx.UserContext(), subID)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			h.logger.Error(op+": not found row", map[string]any{"id": subID})
+
+			return response.ErrorResponse(ctx, http.StatusNotFound, "Not found")
+		}
+		if errors.Is(err, errs.ErrInvalidTransition) {
+			h.logger.Error(op+": invalid transition", map[string]any{"err": err})
+
+			return response.ErrorResponse(ctx, http.StatusConflict, err.Error())
+		}
+		h.logger.Error(op+": usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	setETag(ctx, sub.Version)
+
+	return ctx.Status(http.StatusOK).JSON(convert.SubscriptionEntityToResponse(*sub))
+}
+
+// @Summary     get status history of subscription by ID
+// @Description Returns periods the subscription stayed in every status
+// @ID          SubscriptionStatuses
+// @Tags  	    Subscription
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Subscription ID"
+// @Success     200 {array} dto.SubscriptionStatusResp
+// @Failure     400 {object} response.Error
+// @Failure     404 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /subscription/{id}/statuses [get]
+func (h *HandlerSubscription) statuses(ctx *fiber.Ctx) error {
+	subID, ok := ctx.Locals("query_id").(int64)
+	if !ok {
+		h.logger.Error("subscriptionV1.Statuses: get query_id", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "invalid request body")
+	}
+
+	history, err := h.uc.StatusHistory(ctx.UserContext(), subID)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			h.logger.Error("subscriptionV1.Statuses: not found row", map[string]any{"id": subID})
+
+			return response.ErrorResponse(ctx, http.StatusNotFound, "Not found")
+		}
+		h.logger.Error("subscriptionV1.Statuses: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(convert.SubscriptionStatusHistoryToResponse(history))
+}
+
+// @Summary     get price history of subscription by ID
+// @Description Returns prices of subscription with the month every price is effective from
+// @ID          SubscriptionPrices
+// @Tags  	    Subscription
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Subscription ID"
+// @Success     200 {array} dto.PriceResp
+// @Failure     400 {object} response.Error
+// @Failure     404 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /subscription/{id}/prices [get]
+func (h *HandlerSubscription) prices(ctx *fiber.Ctx) error {
+	subID, ok := ctx.Locals("query_id").(int64)
+	if !ok {
+		h.logger.Error("subscriptionV1.Prices: get query_id", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "invalid request body")
+	}
+
+	prices, err := h.uc.Prices(ctx.UserContext(), subID)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			h.logger.Error("subscriptionV1.Prices: not found row", map[string]any{"id": subID})
+
+			return response.ErrorResponse(ctx, http.StatusNotFound, "Not found")
+		}
+		h.logger.Error("subscriptionV1.Prices: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(convert.PriceHistoryToResponse(prices))
+}
+
+// @Summary     set tags of subscription by ID
+// @Description Replaces all tags of the subscription, tags are kept in lower case and missing ones are created
+// @ID          SubscriptionSetTags
+// @Tags  	    Subscription
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Subscription ID"
+// @Param       request body dto.SubscriptionTagsReq true "Tags"
+// @Success     200 {object} dto.SubscriptionResp
+// @Failure     400 {object} response.Error
+// @Failure     404 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /subscription/{id}/tags [put]
+func (h *HandlerSubscription) setTags(ctx *fiber.Ctx) error {
+	subID, ok := ctx.Locals("query_id").(int64)
+	if !ok {
+		h.logger.Error("subscriptionV1.SetTags: get query_id", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "invalid request body")
+	}
+
+	var body dto.SubscriptionTagsReq
+	if err := ctx.BodyParser(&body); err != nil {
+		h.logger.Error("subscriptionV1.SetTags: parse body", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(body); err != nil {
+		h.logger.Error("subscriptionV1.SetTags: validate struct", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	sub, err := h.uc.SetTags(ctx.UserContext(), subID, body.Tags)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			h.logger.Error("subscriptionV1.SetTags: not found row", map[string]any{"id": subID})
+
+			return response.ErrorResponse(ctx, http.StatusNotFound, "Not found")
+		}
+		h.logger.Error("subscriptionV1.SetTags: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	setETag(ctx, sub.Version)
+
+	return ctx.Status(http.StatusOK).JSON(convert.SubscriptionEntityToResponse(*sub))
+}
+
+// @Summary     get members of subscription by ID
+// @Description Returns users sharing the cost of the subscription, empty when the user of the subscription pays entirely
+// @ID          SubscriptionMembers
+// @Tags  	    Subscription
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Subscription ID"
+// @Success     200 {array} dto.MemberResp
+// @Failure     400 {object} response.Error
+// @Failure     404 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /subscription/{id}/members [get]
+func (h *HandlerSubscription) members(ctx *fiber.Ctx) error {
+	subID, ok := ctx.Locals("query_id").(int64)
+	if !ok {
+		h.logger.Error("subscriptionV1.Members: get query_id", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	members, err := h.uc.Members(ctx.UserContext(), subID)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			h.logger.Error("subscriptionV1.Members: not found row", map[string]any{"id": subID})
+
+			return response.ErrorResponse(ctx, http.StatusNotFound, "Not found")
+		}
+		h.logger.Error("subscriptionV1.Members: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(convert.MembersToResponse(members))
+}
+
+// @Summary     set members of subscription by ID
+// @Description Replaces users sharing the cost of the subscription. The share is the percent of the price or the fixed amount
+// @Description of the current price, shares of all members sum to 100% of the price. Empty list leaves the subscription to its user.
+// @ID          SubscriptionSetMembers
+// @Tags  	    Subscription
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Subscription ID"
+// @Param       request body dto.SubscriptionMembersReq true "Members"
+// @Success     200 {array} dto.MemberResp
+// @Failure     400 {object} response.Error
+// @Failure     404 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /subscription/{id}/members [put]
+func (h *HandlerSubscription) setMembers(ctx *fiber.Ctx) error {
+	subID, ok := ctx.Locals("query_id").(int64)
+	if !ok {
+		h.logger.Error("subscriptionV1.SetMembers: get query_id", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	var body dto.SubscriptionMembersReq
+	if err := ctx.BodyParser(&body); err != nil {
+		h.logger.Error("subscriptionV1.SetMembers: parse body", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(body); err != nil {
+		h.logger.Error("subscriptionV1.SetMembers: validate struct", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	members, err := h.uc.SetMembers(ctx.UserContext(), subID, convert.MembersRequestToEntity(body))
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			h.logger.Error("subscriptionV1.SetMembers: not found row", map[string]any{"id": subID})
+
+			return response.ErrorResponse(ctx, http.StatusNotFound, "Not found")
+		}
+		if errors.Is(err, errs.ErrInvalidInput) {
+			h.logger.Error("subscriptionV1.SetMembers: invalid members", map[string]any{"err": err})
+
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+		}
+		h.logger.Error("subscriptionV1.SetMembers: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(convert.MembersToResponse(members))
+}
+
+// addPaginationHeaders - sets response headers pagination params for list.
+// Page headers are set in offset mode, total headers when the total was counted.
 func addPaginationHeaders(ctx *fiber.Ctx, info entities.PaginationInfo) {
-	ctx.Set("X-Page", strconv.FormatUint(info.Page, 10))
 	ctx.Set("X-Page-Size", strconv.FormatUint(uint64(info.PageSize), 10))
-	ctx.Set("X-Total-Count", strconv.FormatUint(info.TotalCount, 10))
-	ctx.Set("X-Total-Pages", strconv.FormatUint(uint64(info.TotalPages), 10))
 
-	hasNext := info.Page < uint64(info.TotalPages)
-	hasPrev := info.Page > 1
+	if info.Page > 0 {
+		ctx.Set("X-Page", strconv.FormatUint(info.Page, 10))
+		ctx.Set("X-Has-Prev-Page", strconv.FormatBool(info.Page > 1))
+	}
+
+	if info.WithTotal {
+		ctx.Set("X-Total-Count", strconv.FormatUint(info.TotalCount, 10))
+		ctx.Set("X-Total-Pages", strconv.FormatUint(uint64(info.TotalPages), 10))
+	}
+
+	ctx.Set("X-Has-Next-Page", strconv.FormatBool(info.HasNext))
+	if info.Next != nil {
+		ctx.Set("X-Next-Cursor", convert.EncodeCursor(*info.Next))
+	}
+}
+
+// setETag - sets response header ETag with the version of subscription
+func setETag(ctx *fiber.Ctx, version int64) {
+	ctx.Set(fiber.HeaderETag, strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// ifMatchVersion - returns the version of subscription from the If-Match header, zero when it is not set or "*"
+func ifMatchVersion(ctx *fiber.Ctx) (int64, error) {
+	value := strings.TrimSpace(ctx.Get(fiber.HeaderIfMatch))
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+
+	value = strings.Trim(strings.TrimPrefix(value, "W/"), `"`)
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("invalid version %q", value)
+	}
 
-	ctx.Set("X-Has-Next-Page", strconv.FormatBool(hasNext))
-	ctx.Set("X-Has-Prev-Page", strconv.FormatBool(hasPrev))
+	return version, nil
 }
@@ -0,0 +1,160 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	errs "github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/convert"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/dto"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/response"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/middleware"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/usecases/tag"
+)
+
+type HandlerTag struct {
+	validator *validator.Validate
+	uc        tag.TagUsecase
+
+	logger observability.Logger
+}
+
+func NewTagHandler(apiV1Group fiber.Router, validator *validator.Validate, uc tag.TagUsecase, logger observability.Logger) {
+	router := HandlerTag{
+		validator: validator,
+		uc:        uc,
+		logger:    logger,
+	}
+
+	tagGroup := apiV1Group.Group("/tags")
+	{
+		tagGroup.Get("/", middleware.ValidatedQueryParamsTagsMiddleware(logger), router.list)
+		tagGroup.Patch("/:id", middleware.ValidatedQueryIdMiddleware(logger), router.rename)
+		tagGroup.Delete("/:id", middleware.ValidatedQueryIdMiddleware(logger), router.delete)
+	}
+}
+
+// @Summary     get tags
+// @Description Returns tags ordered by name with the number of not deleted subscriptions labeled with them
+// @ID          TagList
+// @Tags  	    Tag
+// @Accept      json
+// @Produce     json
+// @Param       query query dto.QueryParamTags true "Filter params"
+// @Success     200 {array} dto.TagResp
+// @Failure     400 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /tags [get]
+func (h *HandlerTag) list(ctx *fiber.Ctx) error {
+	params, ok := ctx.Locals("query_tags").(dto.QueryParamTags)
+	if !ok {
+		h.logger.Error("tagV1.List: get query_tags", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	tags, err := h.uc.List(ctx.UserContext(), convert.TagQueryParamsToFilter(params))
+	if err != nil {
+		h.logger.Error("tagV1.List: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(convert.TagsToResponse(tags))
+}
+
+// @Summary     rename tag by ID
+// @Description Renames the tag, the labeled subscriptions keep it under the new name
+// @ID          TagRename
+// @Tags  	    Tag
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Tag ID"
+// @Param       request body dto.TagRenameReq true "New name"
+// @Success     200 {object} dto.TagResp
+// @Failure     400 {object} response.Error
+// @Failure     404 {object} response.Error
+// @Failure     409 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /tags/{id} [patch]
+func (h *HandlerTag) rename(ctx *fiber.Ctx) error {
+	tagID, ok := ctx.Locals("query_id").(int64)
+	if !ok {
+		h.logger.Error("tagV1.Rename: get query_id", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	var body dto.TagRenameReq
+	if err := ctx.BodyParser(&body); err != nil {
+		h.logger.Error("tagV1.Rename: parse body", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(body); err != nil {
+		h.logger.Error("tagV1.Rename: validate struct", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	renamed, err := h.uc.Rename(ctx.UserContext(), tagID, body.Name)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			h.logger.Error("tagV1.Rename: not found row", map[string]any{"id": tagID})
+
+			return response.ErrorResponse(ctx, http.StatusNotFound, "Not found")
+		}
+		if errors.Is(err, errs.ErrAlreadyExists) {
+			h.logger.Error("tagV1.Rename: name taken", map[string]any{"name": body.Name})
+
+			return response.ErrorResponse(ctx, http.StatusConflict, "Tag already exists")
+		}
+		h.logger.Error("tagV1.Rename: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(convert.TagEntityToResponse(*renamed))
+}
+
+// @Summary     delete tag by ID
+// @Description Deletes the tag, it is removed from all subscriptions
+// @ID          TagDelete
+// @Tags  	    Tag
+// @Accept      json
+// @Produce     json
+// @Param       id   path      int  true  "Tag ID"
+// @Success     204
+// @Failure     400 {object} response.Error
+// @Failure     404 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /tags/{id} [delete]
+func (h *HandlerTag) delete(ctx *fiber.Ctx) error {
+	tagID, ok := ctx.Locals("query_id").(int64)
+	if !ok {
+		h.logger.Error("tagV1.Delete: get query_id", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	if err := h.uc.Delete(ctx.UserContext(), tagID); err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			h.logger.Error("tagV1.Delete: not found row", map[string]any{"id": tagID})
+
+			return response.ErrorResponse(ctx, http.StatusNotFound, "Not found")
+		}
+		h.logger.Error("tagV1.Delete: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
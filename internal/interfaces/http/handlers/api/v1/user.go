@@ -0,0 +1,390 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	errs "github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/convert"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/dto"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/response"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/middleware"
+	"github.com/mathbdw/subscription-service/internal/interfaces/ical"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	uc "github.com/mathbdw/subscription-service/internal/usecases/subscription"
+	"github.com/mathbdw/subscription-service/internal/usecases/user"
+)
+
+type HandlerUser struct {
+	validator *validator.Validate
+	uc        uc.SubscriptionUsecase
+	ucUser    user.UserUsecase
+
+	logger observability.Logger
+}
+
+func NewUserHandler(apiV1Group fiber.Router, validator *validator.Validate, uc uc.SubscriptionUsecase, ucUser user.UserUsecase, logger observability.Logger) {
+	router := HandlerUser{
+		validator: validator,
+		uc:        uc,
+		ucUser:    ucUser,
+		logger:    logger,
+	}
+
+	userGroup := apiV1Group.Group("/users")
+	{
+		userGroup.Post("/", router.create)
+		userGroup.Get("/", middleware.ValidatedQueryParamsUsersMiddleware(logger), router.list)
+		userGroup.Get("/:user_id/renewals.ics", router.renewals)
+		userGroup.Get("/:id", middleware.ValidatedQueryUserIdMiddleware(logger), router.getId)
+		userGroup.Patch("/:id", middleware.ValidatedQueryUserIdMiddleware(logger), router.update)
+		userGroup.Delete("/:id", middleware.ValidatedQueryUserIdMiddleware(logger), router.delete)
+		userGroup.Get("/:id/subscriptions", middleware.ValidatedQueryUserIdMiddleware(logger), middleware.ValidatedQueryParamsMiddleware(logger), router.subscriptions)
+		userGroup.Get("/:id/cost", middleware.ValidatedQueryUserIdMiddleware(logger), middleware.ValidatedQueryParamsCostMiddleware(logger), router.cost)
+	}
+}
+
+// @Summary     Create user
+// @Description Creates the user, subscriptions can be created only for existing users
+// @ID          UserCreate
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Param       request body dto.UserReq true "Data user"
+// @Success     201 {object} dto.UserResp
+// @Header      201 {string} Location "URL of the created user"
+// @Failure     400 {object} response.Error
+// @Failure     409 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /users [post]
+func (h *HandlerUser) create(ctx *fiber.Ctx) error {
+	var body dto.UserReq
+	if err := ctx.BodyParser(&body); err != nil {
+		h.logger.Error("userV1.Create: parse body", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(body); err != nil {
+		h.logger.Error("userV1.Create: validate struct", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	created, err := h.ucUser.Create(ctx.UserContext(), convert.UserRequestToEntity(body))
+	if err != nil {
+		if errors.Is(err, errs.ErrAlreadyExists) {
+			h.logger.Error("userV1.Create: id or email taken", map[string]any{"id": body.ID, "email": body.Email})
+
+			return response.ErrorResponse(ctx, http.StatusConflict, "User already exists")
+		}
+		h.logger.Error("userV1.Create: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	ctx.Location(fmt.Sprintf("/api/v1/users/%s", created.ID))
+
+	return ctx.Status(http.StatusCreated).JSON(convert.UserEntityToResponse(*created))
+}
+
+// @Summary     get users
+// @Description Returns users ordered by name
+// @ID          UserList
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Param       query query dto.QueryParamUsers true "Filter params"
+// @Success     200 {array} dto.UserResp
+// @Failure     400 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /users [get]
+func (h *HandlerUser) list(ctx *fiber.Ctx) error {
+	params, ok := ctx.Locals("query_users").(dto.QueryParamUsers)
+	if !ok {
+		h.logger.Error("userV1.List: get query_users", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	users, err := h.ucUser.List(ctx.UserContext(), convert.UserQueryParamsToFilter(params))
+	if err != nil {
+		h.logger.Error("userV1.List: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(convert.UsersToResponse(users))
+}
+
+// @Summary     get user by ID
+// @Description Returns user by ID
+// @ID          UserGetByID
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Param       id   path      string  true  "User ID" format(uuid)
+// @Success     200 {object} dto.UserResp
+// @Failure     404 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /users/{id} [get]
+func (h *HandlerUser) getId(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("query_user_id").(uuid.UUID)
+	if !ok {
+		h.logger.Error("userV1.GetId: get query_user_id", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	found, err := h.ucUser.GetByID(ctx.UserContext(), userID)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			h.logger.Error("userV1.GetId: not found row", map[string]any{"id": userID})
+
+			return response.ErrorResponse(ctx, http.StatusNotFound, "Not found")
+		}
+		h.logger.Error("userV1.GetId: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(convert.UserEntityToResponse(*found))
+}
+
+// @Summary     update user by ID
+// @Description Updates fields of the user set in the request and returns the user
+// @ID          UserUpdate
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Param       id   path      string  true  "User ID" format(uuid)
+// @Param       request body dto.UserUpdateReq true "Data user"
+// @Success     200 {object} dto.UserResp
+// @Failure     400 {object} response.Error
+// @Failure     404 {object} response.Error
+// @Failure     409 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /users/{id} [patch]
+func (h *HandlerUser) update(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("query_user_id").(uuid.UUID)
+	if !ok {
+		h.logger.Error("userV1.Update: get query_user_id", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	var body dto.UserUpdateReq
+	if err := ctx.BodyParser(&body); err != nil {
+		h.logger.Error("userV1.Update: parse body", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
+	}
+
+	if err := h.validator.Struct(body); err != nil {
+		h.logger.Error("userV1.Update: validate struct", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	fields := convert.UserRequestToMap(body)
+	if len(fields) == 0 {
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "no fields to update")
+	}
+
+	updated, err := h.ucUser.Update(ctx.UserContext(), userID, fields)
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			h.logger.Error("userV1.Update: not found row", map[string]any{"id": userID})
+
+			return response.ErrorResponse(ctx, http.StatusNotFound, "Not found")
+		}
+		if errors.Is(err, errs.ErrAlreadyExists) {
+			h.logger.Error("userV1.Update: email taken", map[string]any{"email": body.Email})
+
+			return response.ErrorResponse(ctx, http.StatusConflict, "User already exists")
+		}
+		h.logger.Error("userV1.Update: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.Status(http.StatusOK).JSON(convert.UserEntityToResponse(*updated))
+}
+
+// @Summary     delete user by ID
+// @Description Deletes the user without subscriptions and memberships, soft-deleted subscriptions have to be purged before
+// @ID          UserDelete
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Param       id   path      string  true  "User ID" format(uuid)
+// @Success     204
+// @Failure     404 {object} response.Error
+// @Failure     409 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /users/{id} [delete]
+func (h *HandlerUser) delete(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("query_user_id").(uuid.UUID)
+	if !ok {
+		h.logger.Error("userV1.Delete: get query_user_id", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	if err := h.ucUser.Delete(ctx.UserContext(), userID); err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			h.logger.Error("userV1.Delete: not found row", map[string]any{"id": userID})
+
+			return response.ErrorResponse(ctx, http.StatusNotFound, "Not found")
+		}
+		if errors.Is(err, errs.ErrConflict) {
+			h.logger.Error("userV1.Delete: user has subscriptions", map[string]any{"id": userID})
+
+			return response.ErrorResponse(ctx, http.StatusConflict, "User has subscriptions or memberships")
+		}
+		h.logger.Error("userV1.Delete: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+// @Summary     iCalendar feed of renewals
+// @Description Returns calendar of the user with recurring events on renewal days of subscriptions
+// @Description and an event on the end date. Paused and cancelled subscriptions are skipped.
+// @ID          UserRenewals
+// @Tags  	    User
+// @Produce     text/calendar
+// @Param       user_id path string true "User ID" format(uuid)
+// @Success     200 {string} string "iCalendar"
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /users/{user_id}/renewals.ics [get]
+func (h *HandlerUser) renewals(ctx *fiber.Ctx) error {
+	userID, err := uuid.Parse(ctx.Params("user_id"))
+	if err != nil {
+		h.logger.Error("userV1.Renewals: parse user_id", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "invalid user_id")
+	}
+
+	subs, err := h.uc.Renewals(ctx.UserContext(), userID)
+	if err != nil {
+		h.logger.Error("userV1.Renewals: usecase exec", map[string]any{"err": err})
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	ctx.Set(fiber.HeaderContentType, ical.ContentType)
+
+	return ctx.Status(http.StatusOK).Send(ical.Renewals("Subscriptions", subs))
+}
+
+// @Summary     get subscriptions of user
+// @Description Returns subscriptions of the user, the params are the params of the subscription list with user_id
+// @Description set to the path ID and user_ids ignored.
+// @ID          UserSubscriptions
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Param       id   path      string  true  "User ID" format(uuid)
+// @Param       query query dto.QueryParamList true "Query Criteria"
+// @Success     200 {object} dto.SubscriptionListResp
+// @Header      200 {string} X-Next-Cursor "Cursor of the next page, set when it exists"
+// @Header      200 {integer} X-Total-Count "Total count, not set with with_total=false"
+// @Failure     400 {object} response.Error
+// @Failure     404 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /users/{id}/subscriptions [get]
+func (h *HandlerUser) subscriptions(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("query_user_id").(uuid.UUID)
+	if !ok {
+		h.logger.Error("userV1.Subscriptions: get query_user_id", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	params, ok := ctx.Locals("query_params").(dto.QueryParamList)
+	if !ok {
+		h.logger.Error("userV1.Subscriptions: get query_params", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	if found, err := h.userExists(ctx, "userV1.Subscriptions", userID); !found {
+		return err
+	}
+
+	params.UserId = userID.String()
+	params.UserIds = nil
+
+	return listSubscriptions(ctx, &h.uc, h.logger, "userV1.Subscriptions", params)
+}
+
+// @Summary     get cost of user
+// @Description Returns cost subscriptions of the user billed within the period, the params are the params of the cost
+// @Description with user_id set to the path ID.
+// @ID          UserCost
+// @Tags  	    User
+// @Accept      json
+// @Produce     json
+// @Param       id   path      string  true  "User ID" format(uuid)
+// @Param       query query dto.QueryParamCost true "Filter params"
+// @Success     200 {object} dto.CostResp
+// @Failure     400 {object} response.Error
+// @Failure     404 {object} response.Error
+// @Failure     422 {object} response.Error
+// @Failure     500 {object} response.Error
+// @Router      /users/{id}/cost [get]
+func (h *HandlerUser) cost(ctx *fiber.Ctx) error {
+	userID, ok := ctx.Locals("query_user_id").(uuid.UUID)
+	if !ok {
+		h.logger.Error("userV1.Cost: get query_user_id", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	params, ok := ctx.Locals("query_cost").(dto.QueryParamCost)
+	if !ok {
+		h.logger.Error("userV1.Cost: get query_cost", nil)
+
+		return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	if found, err := h.userExists(ctx, "userV1.Cost", userID); !found {
+		return err
+	}
+
+	params.UserId = userID.String()
+
+	return costSubscriptions(ctx, &h.uc, h.logger, "userV1.Cost", params)
+}
+
+// userExists - responds with 404 when the user is not found, the nested routes are not answered with an empty result then.
+// The returned error is the error of the response.
+func (h *HandlerUser) userExists(ctx *fiber.Ctx, op string, userID uuid.UUID) (bool, error) {
+	if _, err := h.ucUser.GetByID(ctx.UserContext(), userID); err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			h.logger.Error(op+": not found user", map[string]any{"id": userID})
+
+			return false, response.ErrorResponse(ctx, http.StatusNotFound, "Not found")
+		}
+		h.logger.Error(op+": usecase getById", map[string]any{"err": err})
+
+		return false, response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+	}
+
+	return true, nil
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/response"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/usecases/audit"
+)
+
+// ActorHeader - request header identifying the actor making the changes
+const ActorHeader = "X-Actor"
+
+// actorMaxLength - max length of the actor stored in the audit log
+const actorMaxLength = 255
+
+// Actor - middleware puts the actor from request header into the request context for the audit log
+func Actor(logger observability.Logger) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		actor := strings.TrimSpace(ctx.Get(ActorHeader))
+		if actor == "" {
+			return ctx.Next()
+		}
+
+		if len(actor) > actorMaxLength {
+			logger.Error("middaleware.Actor: validate header", map[string]any{"actor": actor})
+
+			return response.ErrorResponse(ctx, http.StatusBadRequest, "Invalid X-Actor header")
+		}
+
+		ctx.SetUserContext(audit.WithActor(ctx.UserContext(), actor))
+		return ctx.Next()
+	}
+}
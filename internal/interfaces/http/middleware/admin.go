@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/response"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+)
+
+// AdminTokenHeader - request header with the token of the admin
+const AdminTokenHeader = "X-Admin-Token"
+
+// adminLocal - key of the locals marking the request made by the admin
+const adminLocal = "is_admin"
+
+// Admin - middleware marks the request made by the admin when the header holds the configured token.
+// Without the configured token no request is made by the admin.
+func Admin(token string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		header := ctx.Get(AdminTokenHeader)
+		ctx.Locals(adminLocal, token != "" && subtle.ConstantTimeCompare([]byte(header), []byte(token)) == 1)
+
+		return ctx.Next()
+	}
+}
+
+// IsAdmin - the request is made by the admin
+func IsAdmin(ctx *fiber.Ctx) bool {
+	admin, _ := ctx.Locals(adminLocal).(bool)
+	return admin
+}
+
+// RequireAdmin - middleware rejects the request not made by the admin with 403
+func RequireAdmin(logger observability.Logger) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if !IsAdmin(ctx) {
+			logger.Error("middaleware.RequireAdmin: not admin", map[string]any{"path": ctx.Path()})
+
+			return response.ErrorResponse(ctx, http.StatusForbidden, "Forbidden")
+		}
+
+		return ctx.Next()
+	}
+}
+
+// AdminQuery - middleware rejects the request setting any of the admin query flags with 403
+// unless it is made by the admin
+func AdminQuery(logger observability.Logger, flags ...string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if IsAdmin(ctx) {
+			return ctx.Next()
+		}
+
+		for _, flag := range flags {
+			if ctx.QueryBool(flag) {
+				logger.Error("middaleware.AdminQuery: not admin", map[string]any{"flag": flag})
+
+				return response.ErrorResponse(ctx, http.StatusForbidden, "Forbidden")
+			}
+		}
+
+		return ctx.Next()
+	}
+}
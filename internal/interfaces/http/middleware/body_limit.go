@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/response"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+)
+
+// BodyLimit - middleware rejects the request body larger than limit bytes with 413.
+// The server streams request bodies, so the body over the server limit is not rejected by the server:
+// the streamed body is read here up to the limit for the handlers reading the whole body.
+// Requests to the streamed paths keep the stream and are read by their handlers.
+func BodyLimit(limit int, logger observability.Logger, streamed ...string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if slices.Contains(streamed, ctx.Path()) {
+			return ctx.Next()
+		}
+
+		if ctx.Request().Header.ContentLength() > limit {
+			logger.Error("middaleware.BodyLimit: content length", map[string]any{"length": ctx.Request().Header.ContentLength()})
+
+			return response.ErrorResponse(ctx, http.StatusRequestEntityTooLarge, "Request body too large")
+		}
+
+		stream := ctx.Context().RequestBodyStream()
+		if stream == nil {
+			return ctx.Next()
+		}
+
+		body, err := io.ReadAll(io.LimitReader(stream, int64(limit)+1))
+		if err != nil {
+			logger.Error("middaleware.BodyLimit: read body", map[string]any{"err": err})
+
+			return response.ErrorResponse(ctx, http.StatusBadRequest, "invalid request body")
+		}
+		if len(body) > limit {
+			logger.Error("middaleware.BodyLimit: body length", map[string]any{"limit": limit})
+
+			return response.ErrorResponse(ctx, http.StatusRequestEntityTooLarge, "Request body too large")
+		}
+		ctx.Request().SetBodyRaw(body)
+
+		return ctx.Next()
+	}
+}
+
+// StreamLimit - middleware rejects the streamed request body larger than limit bytes with 413
+// before the handler reads any of it. The size must be known up front, so the request
+// without Content-Length (chunked) is rejected with 411.
+func StreamLimit(limit int, logger observability.Logger) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		length := ctx.Request().Header.ContentLength()
+		if length < 0 {
+			logger.Error("middaleware.StreamLimit: content length", map[string]any{"length": length})
+
+			return response.ErrorResponse(ctx, http.StatusLengthRequired, "Content-Length is required")
+		}
+
+		if length > limit {
+			logger.Error("middaleware.StreamLimit: content length", map[string]any{"length": length, "limit": limit})
+
+			return response.ErrorResponse(ctx, http.StatusRequestEntityTooLarge, "Request body too large")
+		}
+
+		return ctx.Next()
+	}
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	errs "github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/response"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/usecases/idempotency"
+)
+
+// IdempotencyKeyHeader - request header with the client key of the request
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader - response header marking the response replayed for the key
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// idempotencyKeyMaxLength - max length of the stored key
+const idempotencyKeyMaxLength = 255
+
+// replayedHeaders - response headers stored with the response and replayed with it
+var replayedHeaders = []string{fiber.HeaderLocation, fiber.HeaderETag}
+
+// Idempotency - middleware replays the stored response with its Location and ETag for the repeated Idempotency-Key
+// and stores the successful response for the new one. The handler runs in the transaction the key is reserved
+// and the response is stored in, the request fails when the response cannot be stored.
+// Requests without the key are passed through.
+func Idempotency(uc idempotency.IdempotencyUsecase, logger observability.Logger) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		key := strings.TrimSpace(ctx.Get(IdempotencyKeyHeader))
+		if key == "" {
+			return ctx.Next()
+		}
+
+		if len(key) > idempotencyKeyMaxLength {
+			logger.Error("middaleware.Idempotency: validate header", map[string]any{"key": key})
+
+			return response.ErrorResponse(ctx, http.StatusBadRequest, "Invalid Idempotency-Key header")
+		}
+
+		hash := idempotency.RequestHash(ctx.Method(), ctx.Path(), ctx.Body())
+
+		userCtx := ctx.UserContext()
+		stored, err := uc.Execute(userCtx, key, hash, func(txCtx context.Context) (entities.IdempotentResponse, error) {
+			ctx.SetUserContext(txCtx)
+			defer ctx.SetUserContext(userCtx)
+
+			if err := ctx.Next(); err != nil {
+				return entities.IdempotentResponse{}, err
+			}
+
+			headers := make(entities.ResponseHeaders)
+			for _, name := range replayedHeaders {
+				if value := ctx.GetRespHeader(name); value != "" {
+					headers[name] = value
+				}
+			}
+
+			return entities.IdempotentResponse{
+				StatusCode: ctx.Response().StatusCode(),
+				Body:       ctx.Response().Body(),
+				Headers:    headers,
+			}, nil
+		})
+		if err != nil {
+			ctx.Response().Reset()
+			if errors.Is(err, errs.ErrInvalidInput) {
+				logger.Error("middaleware.Idempotency: key reused", map[string]any{"key": key})
+
+				return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Idempotency-Key is already used with another request")
+			}
+			logger.Error("middaleware.Idempotency: execute", map[string]any{"key": key, "err": err})
+
+			return response.ErrorResponse(ctx, http.StatusInternalServerError, "Internal server error")
+		}
+
+		if stored != nil {
+			ctx.Set(IdempotentReplayedHeader, "true")
+			for name, value := range stored.ResponseHeaders {
+				ctx.Set(name, value)
+			}
+			if len(stored.ResponseBody) > 0 {
+				ctx.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			}
+
+			return ctx.Status(stored.StatusCode).Send(stored.ResponseBody)
+		}
+
+		return nil
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/convert"
 	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/dto"
 	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1/response"
 	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
@@ -19,13 +20,9 @@ var validate = validator.New()
 
 func init() {
 	_ = validate.RegisterValidation("sort_by", func(fl validator.FieldLevel) bool {
-		val := fl.Field().String()
-
-		if _, ok := entities.SortByTypes[val]; !ok {
-			return false
-		}
+		_, err := convert.ParseSort(fl.Field().String(), "")
 
-		return true
+		return err == nil
 	})
 	_ = validate.RegisterValidation("sort_order", func(fl validator.FieldLevel) bool {
 		val := fl.Field().String()
@@ -37,6 +34,20 @@ func init() {
 
 		return true
 	})
+	_ = validate.RegisterValidation("group_by", func(fl validator.FieldLevel) bool {
+		for _, val := range strings.Split(fl.Field().String(), ",") {
+			if _, ok := entities.CostGroupTypes[strings.TrimSpace(val)]; !ok {
+				return false
+			}
+		}
+
+		return true
+	})
+	_ = validate.RegisterValidation("cursor", func(fl validator.FieldLevel) bool {
+		_, err := convert.DecodeCursor(fl.Field().String())
+
+		return err == nil
+	})
 	_ = validate.RegisterValidation("uuid", func(fl validator.FieldLevel) bool {
 		val := fl.Field().String()
 
@@ -46,7 +57,23 @@ func init() {
 
 		return true
 	})
+	validate.RegisterStructValidation(func(sl validator.StructLevel) {
+		params := sl.Current().Interface().(dto.QueryParamList)
+		if params.Q != "" {
+			return
+		}
 
+		// relevance is the similarity to the search text, there is nothing to sort by without it
+		sort, err := convert.ParseSort(params.SortBy, "")
+		if err != nil {
+			return
+		}
+		for _, key := range sort {
+			if key.SortBy == entities.SortTypeRelevance {
+				sl.ReportError(params.SortBy, "SortBy", "sort", "sort_by", "")
+			}
+		}
+	}, dto.QueryParamList{})
 }
 
 // ValidatedQueryParamsMiddleware - middleware parse and validate params query for List
@@ -93,6 +120,50 @@ func ValidatedQueryParamsCostMiddleware(logger observability.Logger) fiber.Handl
 	}
 }
 
+// ValidatedQueryParamsCostGroupedMiddleware - middleware parse and validate params query for grouped Cost
+func ValidatedQueryParamsCostGroupedMiddleware(logger observability.Logger) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var queryParams dto.QueryParamCostGrouped
+
+		if err := ctx.QueryParser(&queryParams); err != nil {
+			logger.Error("middaleware.ValidatedQueryParamsCostGroupedMiddleware: invalid query parameters", map[string]any{"err": err})
+
+			return response.ErrorResponse(ctx, http.StatusBadRequest, "Invalid query parameters")
+		}
+
+		if err := validate.Struct(&queryParams); err != nil {
+			logger.Error("middaleware.ValidatedQueryParamsCostGroupedMiddleware: validate", map[string]any{"err": err})
+
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+		}
+
+		ctx.Locals("query_cost_grouped", queryParams)
+		return ctx.Next()
+	}
+}
+
+// ValidatedQueryParamsTrialsMiddleware - middleware parse and validate params query for upcoming trial conversions
+func ValidatedQueryParamsTrialsMiddleware(logger observability.Logger) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var queryParams dto.QueryParamTrials
+
+		if err := ctx.QueryParser(&queryParams); err != nil {
+			logger.Error("middaleware.ValidatedQueryParamsTrialsMiddleware: invalid query parameters", map[string]any{"err": err})
+
+			return response.ErrorResponse(ctx, http.StatusBadRequest, "Invalid query parameters")
+		}
+
+		if err := validate.Struct(&queryParams); err != nil {
+			logger.Error("middaleware.ValidatedQueryParamsTrialsMiddleware: validate", map[string]any{"err": err})
+
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+		}
+
+		ctx.Locals("query_trials", queryParams)
+		return ctx.Next()
+	}
+}
+
 // ValidatedQueryIdMiddleware - middleware parse and validate params query ID subscription
 func ValidatedQueryIdMiddleware(logger observability.Logger) fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
@@ -114,3 +185,107 @@ func ValidatedQueryIdMiddleware(logger observability.Logger) fiber.Handler {
 		return ctx.Next()
 	}
 }
+
+// ValidatedQueryParamsAuditMiddleware - middleware parse and validate params query for audit log
+func ValidatedQueryParamsAuditMiddleware(logger observability.Logger) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var queryParams dto.QueryParamAudit
+
+		if err := ctx.QueryParser(&queryParams); err != nil {
+			logger.Error("middaleware.ValidatedQueryParamsAuditMiddleware: invalid query parameters", map[string]any{"err": err})
+
+			return response.ErrorResponse(ctx, http.StatusBadRequest, "Invalid query parameters")
+		}
+
+		if err := validate.Struct(&queryParams); err != nil {
+			logger.Error("middaleware.ValidatedQueryParamsAuditMiddleware: validate", map[string]any{"err": err})
+
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+		}
+
+		ctx.Locals("query_audit", queryParams)
+		return ctx.Next()
+	}
+}
+
+// ValidatedQueryParamsServicesMiddleware - middleware parse and validate params query for the service catalog
+func ValidatedQueryParamsServicesMiddleware(logger observability.Logger) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var queryParams dto.QueryParamServices
+
+		if err := ctx.QueryParser(&queryParams); err != nil {
+			logger.Error("middaleware.ValidatedQueryParamsServicesMiddleware: invalid query parameters", map[string]any{"err": err})
+
+			return response.ErrorResponse(ctx, http.StatusBadRequest, "Invalid query parameters")
+		}
+
+		if err := validate.Struct(&queryParams); err != nil {
+			logger.Error("middaleware.ValidatedQueryParamsServicesMiddleware: validate", map[string]any{"err": err})
+
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+		}
+
+		ctx.Locals("query_services", queryParams)
+		return ctx.Next()
+	}
+}
+
+// ValidatedQueryParamsTagsMiddleware - middleware parse and validate params query for the tags
+func ValidatedQueryParamsTagsMiddleware(logger observability.Logger) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var queryParams dto.QueryParamTags
+
+		if err := ctx.QueryParser(&queryParams); err != nil {
+			logger.Error("middaleware.ValidatedQueryParamsTagsMiddleware: invalid query parameters", map[string]any{"err": err})
+
+			return response.ErrorResponse(ctx, http.StatusBadRequest, "Invalid query parameters")
+		}
+
+		if err := validate.Struct(&queryParams); err != nil {
+			logger.Error("middaleware.ValidatedQueryParamsTagsMiddleware: validate", map[string]any{"err": err})
+
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+		}
+
+		ctx.Locals("query_tags", queryParams)
+		return ctx.Next()
+	}
+}
+
+// ValidatedQueryParamsUsersMiddleware - middleware parse and validate params query for the users
+func ValidatedQueryParamsUsersMiddleware(logger observability.Logger) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		var queryParams dto.QueryParamUsers
+
+		if err := ctx.QueryParser(&queryParams); err != nil {
+			logger.Error("middaleware.ValidatedQueryParamsUsersMiddleware: invalid query parameters", map[string]any{"err": err})
+
+			return response.ErrorResponse(ctx, http.StatusBadRequest, "Invalid query parameters")
+		}
+
+		if err := validate.Struct(&queryParams); err != nil {
+			logger.Error("middaleware.ValidatedQueryParamsUsersMiddleware: validate", map[string]any{"err": err})
+
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, err.Error())
+		}
+
+		ctx.Locals("query_users", queryParams)
+		return ctx.Next()
+	}
+}
+
+// ValidatedQueryUserIdMiddleware - middleware parse and validate params query ID user
+func ValidatedQueryUserIdMiddleware(logger observability.Logger) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		userStrID := ctx.Params("id")
+		userID, err := uuid.Parse(userStrID)
+		if err != nil {
+			logger.Error("middaleware.ValidatedQueryUserIdMiddleware: parse param", map[string]any{"userStrID": userStrID, "err": err})
+
+			return response.ErrorResponse(ctx, http.StatusUnprocessableEntity, "Invalid id: must be a UUID")
+		}
+
+		ctx.Locals("query_user_id", userID)
+		return ctx.Next()
+	}
+}
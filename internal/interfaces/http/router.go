@@ -10,7 +10,12 @@ import (
 	"github.com/mathbdw/subscription-service/internal/interfaces/http/handlers/api/v1"
 	"github.com/mathbdw/subscription-service/internal/interfaces/http/middleware"
 	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/usecases/audit"
+	"github.com/mathbdw/subscription-service/internal/usecases/idempotency"
+	"github.com/mathbdw/subscription-service/internal/usecases/service"
 	uc "github.com/mathbdw/subscription-service/internal/usecases/subscription"
+	"github.com/mathbdw/subscription-service/internal/usecases/tag"
+	"github.com/mathbdw/subscription-service/internal/usecases/user"
 )
 
 // NewRouter -.
@@ -20,10 +25,14 @@ import (
 // @version     1.0
 // @host        localhost:8080
 // @BasePath    /api/v1
-func NewRouter(app *fiber.App, cfg *config.Rest, uc uc.SubscriptionUsecase, logger observability.Logger) {
+func NewRouter(app *fiber.App, cfg *config.Rest, uc uc.SubscriptionUsecase, ucAudit audit.AuditUsecase, ucIdempotency idempotency.IdempotencyUsecase, ucService service.ServiceUsecase, ucTag tag.TagUsecase, ucUser user.UserUsecase, logger observability.Logger) {
 	// Options
 	app.Use(middleware.Logger(logger))
 	app.Use(middleware.Recovery(logger))
+	app.Use(middleware.Actor(logger))
+	app.Use(middleware.BodyLimit(app.Config().BodyLimit, logger, v1.ImportPath))
+	app.Use(middleware.Admin(cfg.AdminToken))
+	app.Use(middleware.AdminQuery(logger, "include_deleted"))
 
 	// Swagger
 	if cfg.Swagger {
@@ -32,7 +41,12 @@ func NewRouter(app *fiber.App, cfg *config.Rest, uc uc.SubscriptionUsecase, logg
 
 	// Routers
 	apiV1Group := app.Group("/api/v1")
+	validate := validator.New(validator.WithRequiredStructEnabled())
 	{
-		v1.NewHandler(apiV1Group, validator.New(validator.WithRequiredStructEnabled()), uc, logger)
+		v1.NewHandler(apiV1Group, validate, uc, ucIdempotency, cfg.ImportLimit, logger)
+		v1.NewAuditHandler(apiV1Group, ucAudit, logger)
+		v1.NewUserHandler(apiV1Group, validate, uc, ucUser, logger)
+		v1.NewServiceHandler(apiV1Group, validate, ucService, logger)
+		v1.NewTagHandler(apiV1Group, validate, ucTag, logger)
 	}
 }
@@ -0,0 +1,215 @@
+package ical
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+)
+
+// ContentType - content type of the iCalendar file
+const ContentType = "text/calendar; charset=utf-8"
+
+const (
+	prodID     = "-//subscription-service//renewals//EN"
+	uidDomain  = "subscription-service"
+	dateFormat = "20060102"
+	timeFormat = "20060102T150405Z"
+	// lineLimit - max length of the content line in octets, longer lines are folded
+	lineLimit = 75
+)
+
+// Renewals - Returns calendar with recurring events on the renewal days of the subscriptions
+// and an event on the end date. Renewals of the free trial months are skipped.
+func Renewals(name string, subs []entities.Subscription) []byte {
+	var cal calendar
+	cal.line("BEGIN:VCALENDAR")
+	cal.line("VERSION:2.0")
+	cal.line("PRODID:" + prodID)
+	cal.line("CALSCALE:GREGORIAN")
+	cal.line("METHOD:PUBLISH")
+	cal.line("X-WR-CALNAME:" + escape(name))
+
+	for _, sub := range subs {
+		stamp := sub.UpdatedAt.UTC().Format(timeFormat)
+
+		if start, ok := firstRenewal(sub); ok {
+			cal.line("BEGIN:VEVENT")
+			cal.line(fmt.Sprintf("UID:subscription-%d-renewal@%s", sub.ID, uidDomain))
+			cal.line("DTSTAMP:" + stamp)
+			cal.line("DTSTART;VALUE=DATE:" + start.Format(dateFormat))
+			cal.line("RRULE:" + rrule(sub))
+			cal.line("SUMMARY:" + escape(fmt.Sprintf("%s: %d %s", sub.ServiceName, sub.Price, sub.Currency)))
+			cal.line("DESCRIPTION:" + escape(fmt.Sprintf("Renewal of subscription #%d, billed %s", sub.ID, billing(sub))))
+			cal.line("TRANSP:TRANSPARENT")
+			cal.line("END:VEVENT")
+		}
+
+		if sub.EndDate.Valid {
+			cal.line("BEGIN:VEVENT")
+			cal.line(fmt.Sprintf("UID:subscription-%d-end@%s", sub.ID, uidDomain))
+			cal.line("DTSTAMP:" + stamp)
+			cal.line("DTSTART;VALUE=DATE:" + sub.EndDate.Time.Format(dateFormat))
+			cal.line("SUMMARY:" + escape(sub.ServiceName+" ends"))
+			cal.line("DESCRIPTION:" + escape(fmt.Sprintf("Subscription #%d ends", sub.ID)))
+			cal.line("TRANSP:TRANSPARENT")
+			cal.line("END:VEVENT")
+		}
+	}
+
+	cal.line("END:VCALENDAR")
+
+	return []byte(cal.String())
+}
+
+// firstRenewal - first billed renewal day, the month the trial ends in is billed.
+// False is returned when the subscription ends before it.
+func firstRenewal(sub entities.Subscription) (time.Time, bool) {
+	start := sub.StartDate
+	if sub.TrialEndDate.Valid {
+		trialMonth := time.Date(sub.TrialEndDate.Time.Year(), sub.TrialEndDate.Time.Month(), 1, 0, 0, 0, 0, time.UTC)
+		for n := 1; start.Before(trialMonth); n++ {
+			start = renewal(sub, n)
+		}
+	}
+
+	if sub.EndDate.Valid && start.After(sub.EndDate.Time) {
+		return time.Time{}, false
+	}
+
+	return start, true
+}
+
+// renewal - n-th renewal day after the start.
+// Renewals counted in months fall on the start day, clamped to the end of the shorter month.
+func renewal(sub entities.Subscription, n int) time.Time {
+	if sub.BillingPeriod == entities.BillingPeriodWeekly {
+		return sub.StartDate.AddDate(0, 0, 7*n)
+	}
+
+	return addMonths(sub.StartDate, months(sub)*n)
+}
+
+// addMonths - the same day of the month months later, the end of the month when the month is shorter
+func addMonths(day time.Time, months int) time.Time {
+	first := time.Date(day.Year(), day.Month()+time.Month(months), 1, 0, 0, 0, 0, day.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	if day.Day() < last {
+		last = day.Day()
+	}
+
+	return first.AddDate(0, 0, last-1)
+}
+
+// months - months between renewals of the billing period other than weekly
+func months(sub entities.Subscription) int {
+	switch sub.BillingPeriod {
+	case entities.BillingPeriodQuarterly:
+		return 3
+	case entities.BillingPeriodYearly:
+		return 12
+	case entities.BillingPeriodCustom:
+		return int(interval(sub))
+	default:
+		return 1
+	}
+}
+
+// rrule - recurrence rule of the billing period, renewals stop at the end date.
+// Renewals from a day after the 28th fall on the last existing day up to the start day, as addMonths does.
+func rrule(sub entities.Subscription) string {
+	var rule string
+	switch sub.BillingPeriod {
+	case entities.BillingPeriodWeekly:
+		rule = "FREQ=WEEKLY"
+	case entities.BillingPeriodQuarterly:
+		rule = "FREQ=MONTHLY;INTERVAL=3"
+	case entities.BillingPeriodYearly:
+		rule = "FREQ=YEARLY"
+	case entities.BillingPeriodCustom:
+		rule = fmt.Sprintf("FREQ=MONTHLY;INTERVAL=%d", interval(sub))
+	default:
+		rule = "FREQ=MONTHLY"
+	}
+
+	if day := sub.StartDate.Day(); day > 28 && sub.BillingPeriod != entities.BillingPeriodWeekly {
+		if sub.BillingPeriod == entities.BillingPeriodYearly {
+			rule += fmt.Sprintf(";BYMONTH=%d", sub.StartDate.Month())
+		}
+		rule += ";BYMONTHDAY=" + monthDays(day) + ";BYSETPOS=-1"
+	}
+
+	if sub.EndDate.Valid {
+		rule += ";UNTIL=" + sub.EndDate.Time.Format(dateFormat)
+	}
+
+	return rule
+}
+
+// monthDays - days of the month from the 28th up to the day
+func monthDays(day int) string {
+	days := make([]string, 0, day-27)
+	for d := 28; d <= day; d++ {
+		days = append(days, strconv.Itoa(d))
+	}
+
+	return strings.Join(days, ",")
+}
+
+// billing - human readable billing period
+func billing(sub entities.Subscription) string {
+	if sub.BillingPeriod == entities.BillingPeriodCustom {
+		return fmt.Sprintf("every %d months", interval(sub))
+	}
+	if sub.BillingPeriod == "" {
+		return string(entities.BillingPeriodMonthly)
+	}
+
+	return string(sub.BillingPeriod)
+}
+
+// interval - months between renewals of the custom billing period
+func interval(sub entities.Subscription) uint16 {
+	if sub.BillingInterval == 0 {
+		return 1
+	}
+
+	return sub.BillingInterval
+}
+
+// escape - escapes special characters of the text value
+func escape(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// calendar - content lines separated by CRLF
+type calendar struct {
+	strings.Builder
+}
+
+// line - writes the content line, folding it by lineLimit octets without splitting UTF-8 characters
+func (c *calendar) line(content string) {
+	limit := lineLimit
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8Start(content[cut]) {
+			cut--
+		}
+
+		c.WriteString(content[:cut])
+		c.WriteString("\r\n ")
+		content = content[cut:]
+		// the leading space of the continuation line counts to its length
+		limit = lineLimit - 1
+	}
+
+	c.WriteString(content)
+	c.WriteString("\r\n")
+}
+
+// utf8Start - the byte starts UTF-8 character
+func utf8Start(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,146 @@
+package ical
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestRenewals_Events(t *testing.T) {
+	subs := []entities.Subscription{
+		{
+			ID:            1,
+			ServiceName:   "Netflix, Premium",
+			Price:         400,
+			Currency:      "RUB",
+			BillingPeriod: entities.BillingPeriodMonthly,
+			StartDate:     date(2025, time.January, 1),
+			EndDate:       sql.NullTime{Time: date(2025, time.December, 1), Valid: true},
+			UpdatedAt:     time.Date(2025, time.January, 31, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			ID:              2,
+			ServiceName:     "Cloud",
+			Price:           100,
+			Currency:        "USD",
+			BillingPeriod:   entities.BillingPeriodCustom,
+			BillingInterval: 2,
+			StartDate:       date(2025, time.January, 1),
+			TrialEndDate:    sql.NullTime{Time: date(2025, time.February, 15), Valid: true},
+		},
+	}
+
+	cal := string(Renewals("Subscriptions", subs))
+
+	assert.True(t, strings.HasPrefix(cal, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(cal, "END:VCALENDAR\r\n"))
+	assert.Equal(t, 3, strings.Count(cal, "BEGIN:VEVENT"))
+
+	assert.Contains(t, cal, "UID:subscription-1-renewal@subscription-service\r\n")
+	assert.Contains(t, cal, "DTSTAMP:20250131T100000Z\r\n")
+	assert.Contains(t, cal, "DTSTART;VALUE=DATE:20250101\r\nRRULE:FREQ=MONTHLY;UNTIL=20251201\r\n")
+	assert.Contains(t, cal, `SUMMARY:Netflix\, Premium: 400 RUB`)
+	assert.Contains(t, cal, "UID:subscription-1-end@subscription-service\r\n")
+	assert.Contains(t, cal, "DTSTART;VALUE=DATE:20251201\r\nSUMMARY:Netflix\\, Premium ends\r\n")
+
+	// months of the trial are not billed, renewals start from the month the trial ends in
+	assert.Contains(t, cal, "DTSTART;VALUE=DATE:20250301\r\nRRULE:FREQ=MONTHLY;INTERVAL=2\r\n")
+	assert.NotContains(t, cal, "subscription-2-end")
+}
+
+func TestRenewals_EndedInTrial(t *testing.T) {
+	subs := []entities.Subscription{{
+		ID:            1,
+		ServiceName:   "Music",
+		BillingPeriod: entities.BillingPeriodYearly,
+		StartDate:     date(2025, time.January, 1),
+		EndDate:       sql.NullTime{Time: date(2025, time.March, 1), Valid: true},
+		TrialEndDate:  sql.NullTime{Time: date(2025, time.June, 1), Valid: true},
+	}}
+
+	cal := string(Renewals("Subscriptions", subs))
+
+	assert.NotContains(t, cal, "subscription-1-renewal")
+	assert.Contains(t, cal, "subscription-1-end")
+}
+
+func TestRenewals_StartOnMonthEnd(t *testing.T) {
+	subs := []entities.Subscription{
+		{
+			ID:            1,
+			ServiceName:   "Music",
+			BillingPeriod: entities.BillingPeriodMonthly,
+			StartDate:     date(2025, time.January, 31),
+			TrialEndDate:  sql.NullTime{Time: date(2025, time.February, 10), Valid: true},
+		},
+		{
+			ID:            2,
+			ServiceName:   "Cloud",
+			BillingPeriod: entities.BillingPeriodQuarterly,
+			StartDate:     date(2024, time.November, 30),
+			TrialEndDate:  sql.NullTime{Time: date(2025, time.January, 10), Valid: true},
+		},
+	}
+
+	cal := string(Renewals("Subscriptions", subs))
+
+	// the trial month is walked on the last day of February, not rolled over to March
+	assert.Contains(t, cal, "DTSTART;VALUE=DATE:20250228\r\nRRULE:FREQ=MONTHLY;BYMONTHDAY=28,29,30,31;BYSETPOS=-1\r\n")
+	assert.Contains(t, cal, "DTSTART;VALUE=DATE:20250228\r\nRRULE:FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=28,29,30;BYSETPOS=-1\r\n")
+}
+
+func TestAddMonths(t *testing.T) {
+	assert.Equal(t, date(2025, time.February, 28), addMonths(date(2025, time.January, 31), 1))
+	assert.Equal(t, date(2025, time.March, 31), addMonths(date(2025, time.January, 31), 2))
+	assert.Equal(t, date(2025, time.February, 28), addMonths(date(2024, time.February, 29), 12))
+}
+
+func TestRrule(t *testing.T) {
+	tests := []struct {
+		period   entities.BillingPeriodType
+		interval uint16
+		expected string
+	}{
+		{period: entities.BillingPeriodWeekly, expected: "FREQ=WEEKLY"},
+		{period: entities.BillingPeriodMonthly, expected: "FREQ=MONTHLY"},
+		{period: entities.BillingPeriodQuarterly, expected: "FREQ=MONTHLY;INTERVAL=3"},
+		{period: entities.BillingPeriodYearly, expected: "FREQ=YEARLY"},
+		{period: entities.BillingPeriodCustom, interval: 6, expected: "FREQ=MONTHLY;INTERVAL=6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.period), func(t *testing.T) {
+			sub := entities.Subscription{BillingPeriod: tt.period, BillingInterval: tt.interval}
+
+			require.Equal(t, tt.expected, rrule(sub))
+		})
+	}
+}
+
+func TestCalendar_LineFolding(t *testing.T) {
+	var cal calendar
+	cal.line("SUMMARY:" + strings.Repeat("я", 100))
+
+	lines := strings.Split(strings.TrimSuffix(cal.String(), "\r\n"), "\r\n")
+	require.Greater(t, len(lines), 1)
+
+	unfolded := lines[0]
+	for _, line := range lines {
+		assert.LessOrEqual(t, len(line), lineLimit)
+	}
+	for _, line := range lines[1:] {
+		require.True(t, strings.HasPrefix(line, " "))
+		unfolded += line[1:]
+	}
+	assert.Equal(t, "SUMMARY:"+strings.Repeat("я", 100), unfolded)
+}
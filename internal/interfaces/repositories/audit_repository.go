@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+)
+
+//go:generate mockgen -destination=./../../../mocks/mock_audit_repository.go -package=mocks -source=./audit_repository.go
+
+type AuditRepository interface {
+	Create(ctx context.Context, entry entities.AuditEntry) error
+	List(ctx context.Context, filter entities.AuditFilter) ([]entities.AuditEntry, error)
+}
@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+)
+
+//go:generate mockgen -destination=./../../../mocks/mock_idempotency_repository.go -package=mocks -source=./idempotency_repository.go
+
+type IdempotencyRepository interface {
+	Get(ctx context.Context, key string, at time.Time) (*entities.IdempotencyKey, error)
+	Create(ctx context.Context, key entities.IdempotencyKey) error
+	SetResponse(ctx context.Context, key string, resp entities.IdempotentResponse) error
+	DeleteExpired(ctx context.Context, at time.Time) (int64, error)
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+)
+
+//go:generate mockgen -destination=./../../../mocks/mock_service_repository.go -package=mocks -source=./service_repository.go
+
+type ServiceRepository interface {
+	Create(ctx context.Context, service entities.Service) (*entities.Service, error)
+	GetByID(ctx context.Context, id int64) (*entities.Service, error)
+	List(ctx context.Context, filter entities.ServiceFilter) ([]entities.Service, error)
+	Update(ctx context.Context, id int64, fields map[string]any) error
+	Delete(ctx context.Context, id int64) error
+	LinkSubscriptions(ctx context.Context, service entities.Service) (int64, error)
+}
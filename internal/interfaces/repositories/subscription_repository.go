@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/mathbdw/subscription-service/internal/domain/entities"
 )
@@ -9,10 +10,24 @@ import (
 //go:generate mockgen -destination=./../../../mocks/mock_subscription_repository.go -package=mocks -source=./subscription_repository.go
 
 type SubscriptionRepository interface {
-	Create(ctx context.Context, subscription entities.Subscription) error
-	GetByID(ctx context.Context, id int64) (*entities.Subscription, error)
+	Create(ctx context.Context, subscription entities.Subscription) (*entities.Subscription, error)
+	GetByID(ctx context.Context, id int64, includeDeleted bool) (*entities.Subscription, error)
 	List(ctx context.Context, params entities.QueryCriteria) (*entities.ResponseListSubscription, error)
-	Update(ctx context.Context, id int64, fields map[string]any) error
-	Delete(ctx context.Context, id int64) error
-	GetCost(ctx context.Context, params entities.FilterParams) (int64, error)
+	Export(ctx context.Context, params entities.QueryCriteria, fn func(entities.Subscription) error) error
+	Update(ctx context.Context, id int64, fields map[string]any, version int64) error
+	Delete(ctx context.Context, id int64, version int64) error
+	Restore(ctx context.Context, id int64) error
+	Purge(ctx context.Context, before time.Time) (int64, error)
+	GetCost(ctx context.Context, params entities.FilterParams) ([]entities.CostItem, error)
+	GetCostMonthly(ctx context.Context, params entities.FilterParams) ([]entities.CostMonthService, error)
+	GetCostGrouped(ctx context.Context, params entities.FilterParams, groupBy []entities.CostGroupType) ([]entities.CostGroup, error)
+	ChangeStatus(ctx context.Context, id int64, status entities.SubscriptionStatusType, at time.Time) error
+	GetStatusHistory(ctx context.Context, id int64) ([]entities.SubscriptionStatusChange, error)
+	ListTrialsEnding(ctx context.Context, period entities.DateRange, at time.Time) ([]entities.Subscription, error)
+	AddPrice(ctx context.Context, id int64, price uint32, effectiveFrom time.Time) error
+	DeletePricesUntil(ctx context.Context, id int64, until time.Time) error
+	GetPriceHistory(ctx context.Context, id int64) ([]entities.PriceChange, error)
+	SetTags(ctx context.Context, id int64, tags []string) error
+	GetMembers(ctx context.Context, id int64) ([]entities.Member, error)
+	SetMembers(ctx context.Context, id int64, members []entities.Member) error
 }
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+)
+
+//go:generate mockgen -destination=./../../../mocks/mock_tag_repository.go -package=mocks -source=./tag_repository.go
+
+type TagRepository interface {
+	GetByID(ctx context.Context, id int64) (*entities.Tag, error)
+	List(ctx context.Context, filter entities.TagFilter) ([]entities.Tag, error)
+	Rename(ctx context.Context, id int64, name string) error
+	Delete(ctx context.Context, id int64) error
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import "context"
+
+//go:generate mockgen -destination=./../../../mocks/mock_transactor.go -package=mocks -source=./transactor.go
+
+type Transactor interface {
+	// WithinTransaction - runs fn in a transaction carried by the context passed to fn.
+	// Nested calls join the outer transaction.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+	// WithinSavepoint - runs fn in a savepoint of the transaction carried by the context,
+	// changes of fn are rolled back on error while the transaction goes on.
+	// Without transaction in the context fn runs in a new transaction.
+	WithinSavepoint(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+)
+
+//go:generate mockgen -destination=./../../../mocks/mock_user_repository.go -package=mocks -source=./user_repository.go
+
+type UserRepository interface {
+	Create(ctx context.Context, user entities.User) (*entities.User, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error)
+	List(ctx context.Context, filter entities.UserFilter) ([]entities.User, error)
+	Update(ctx context.Context, id uuid.UUID, fields map[string]any) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,23 @@
+package audit
+
+import "context"
+
+// DefaultActor - actor of the changes made without identified caller
+const DefaultActor = "anonymous"
+
+// actorKey - context key of the actor making the change
+type actorKey struct{}
+
+// WithActor - returns context carrying the actor making the change
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext - returns the actor carried by the context or DefaultActor
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+
+	return DefaultActor
+}
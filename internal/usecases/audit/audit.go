@@ -0,0 +1,30 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/interfaces/repositories"
+)
+
+type AuditUsecase struct {
+	repo   repositories.AuditRepository
+	logger observability.Logger
+}
+
+// NewAuditUsecase - Constructor AuditUsecase
+func NewAuditUsecase(repo repositories.AuditRepository, logger observability.Logger) AuditUsecase {
+	return AuditUsecase{repo: repo, logger: logger}
+}
+
+// List - Returns audit entries by filter, the latest go first
+func (uc *AuditUsecase) List(ctx context.Context, filter entities.AuditFilter) ([]entities.AuditEntry, error) {
+	entries, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "AuditUsecase.List: repo exec")
+	}
+
+	return entries, nil
+}
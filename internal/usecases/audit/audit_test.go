@@ -0,0 +1,76 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudit_NewEntry_Update(t *testing.T) {
+	ctx := WithActor(context.Background(), "admin")
+
+	before := map[string]any{"price": uint32(100), "service_name": "Test service", "end_date": nil}
+	after := map[string]any{"price": uint32(200), "service_name": "Test service", "end_date": "2025-01-01"}
+
+	entry, err := NewEntry(ctx, entities.AuditEntitySubscription, 1, entities.AuditActionUpdate, before, after)
+
+	require.NoError(t, err)
+	assert.Equal(t, "admin", entry.Actor)
+	assert.Equal(t, entities.AuditActionUpdate, entry.Action)
+	assert.JSONEq(t, `{"price":100,"service_name":"Test service","end_date":null}`, string(entry.Before))
+	assert.JSONEq(t, `{"price":{"before":100,"after":200},"end_date":{"before":null,"after":"2025-01-01"}}`, string(entry.Diff))
+}
+
+func TestAudit_NewEntry_Create(t *testing.T) {
+	entry, err := NewEntry(context.Background(), entities.AuditEntitySubscription, 1, entities.AuditActionCreate, nil, map[string]any{"price": 100})
+
+	require.NoError(t, err)
+	assert.Equal(t, DefaultActor, entry.Actor)
+	assert.Nil(t, entry.Before)
+	assert.JSONEq(t, `{"price":{"before":null,"after":100}}`, string(entry.Diff))
+}
+
+func TestAudit_List_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAuditRepository(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewAuditUsecase(mockRepo, mockLogger)
+	ctx := context.Background()
+
+	filter := entities.AuditFilter{Actor: "admin"}
+	entries := []entities.AuditEntry{{ID: 1, Actor: "admin"}}
+
+	mockRepo.EXPECT().List(ctx, filter).Return(entries, nil)
+
+	res, err := us.List(ctx, filter)
+
+	require.NoError(t, err)
+	require.Equal(t, entries, res)
+}
+
+func TestAudit_List_ErrorRepo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAuditRepository(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewAuditUsecase(mockRepo, mockLogger)
+	ctx := context.Background()
+
+	mockRepo.EXPECT().List(ctx, entities.AuditFilter{}).Return(nil, errors.New("error repo"))
+
+	res, err := us.List(ctx, entities.AuditFilter{})
+
+	require.Nil(t, res)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "AuditUsecase.List: repo exec")
+}
@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+)
+
+// NewEntry - builds the audit entry of the change made by the actor from context.
+// Snapshot before is nil for create and snapshot after is nil for delete.
+func NewEntry(ctx context.Context, entityType string, entityID int64, action entities.AuditActionType, before, after map[string]any) (entities.AuditEntry, error) {
+	entry := entities.AuditEntry{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Actor:      ActorFromContext(ctx),
+	}
+
+	var err error
+	if before != nil {
+		if entry.Before, err = json.Marshal(before); err != nil {
+			return entities.AuditEntry{}, errors.Wrap(err, "audit.NewEntry: marshal before")
+		}
+	}
+
+	if after != nil {
+		if entry.After, err = json.Marshal(after); err != nil {
+			return entities.AuditEntry{}, errors.Wrap(err, "audit.NewEntry: marshal after")
+		}
+	}
+
+	changes, err := Diff(before, after)
+	if err != nil {
+		return entities.AuditEntry{}, errors.Wrap(err, "audit.NewEntry: diff")
+	}
+
+	if entry.Diff, err = json.Marshal(changes); err != nil {
+		return entities.AuditEntry{}, errors.Wrap(err, "audit.NewEntry: marshal diff")
+	}
+
+	return entry, nil
+}
+
+// Diff - Returns fields whose JSON values differ between the snapshots
+func Diff(before, after map[string]any) (map[string]entities.AuditChange, error) {
+	changes := make(map[string]entities.AuditChange)
+
+	for field, valBefore := range before {
+		valAfter, ok := after[field]
+		if ok {
+			equal, err := jsonEqual(valBefore, valAfter)
+			if err != nil {
+				return nil, errors.Wrap(err, field)
+			}
+			if equal {
+				continue
+			}
+		}
+
+		changes[field] = entities.AuditChange{Before: valBefore, After: valAfter}
+	}
+
+	for field, valAfter := range after {
+		if _, ok := before[field]; !ok {
+			changes[field] = entities.AuditChange{After: valAfter}
+		}
+	}
+
+	return changes, nil
+}
+
+// jsonEqual - compares values by their JSON representation
+func jsonEqual(a, b any) (bool, error) {
+	dataA, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+
+	dataB, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(dataA, dataB), nil
+}
@@ -0,0 +1,110 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	stderrors "errors"
+	"time"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/interfaces/repositories"
+)
+
+// DefaultTTL - time the stored response is replayed for when TTL is not set
+const DefaultTTL = 24 * time.Hour
+
+type IdempotencyUsecase struct {
+	repo   repositories.IdempotencyRepository
+	tx     repositories.Transactor
+	ttl    time.Duration
+	logger observability.Logger
+}
+
+// NewIdempotencyUsecase - Constructor IdempotencyUsecase
+func NewIdempotencyUsecase(repo repositories.IdempotencyRepository, tx repositories.Transactor, ttl time.Duration, logger observability.Logger) IdempotencyUsecase {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	return IdempotencyUsecase{repo: repo, tx: tx, ttl: ttl, logger: logger}
+}
+
+// RequestHash - returns the hash identifying the request the key is used with
+func RequestHash(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(path))
+	hash.Write([]byte{0})
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// errNotStored - rolls back the key reserved for the request with the unsuccessful response
+var errNotStored = stderrors.New("response is not stored")
+
+// Execute - Runs handle once for the key and stores its successful response, the stored response is returned
+// for the repeated key instead. The key is reserved before handle in the transaction handle runs in,
+// so a concurrent request with the key waits for it and gets the stored response,
+// the key is released when handle fails or its response is not successful.
+// ErrInvalidInput is returned when the key was used with another request.
+func (uc *IdempotencyUsecase) Execute(ctx context.Context, key, requestHash string, handle func(ctx context.Context) (entities.IdempotentResponse, error)) (*entities.IdempotencyKey, error) {
+	var stored *entities.IdempotencyKey
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now().UTC()
+
+		err := uc.repo.Create(ctx, entities.IdempotencyKey{
+			Key:         key,
+			RequestHash: requestHash,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(uc.ttl),
+		})
+		if stderrors.Is(err, errors.ErrAlreadyExists) {
+			stored, err = uc.repo.Get(ctx, key, now)
+			if err != nil {
+				return errors.Wrap(err, "IdempotencyUsecase.Execute: repo get")
+			}
+			if stored.RequestHash != requestHash {
+				return errors.Wrap(errors.ErrInvalidInput, "IdempotencyUsecase.Execute: key is used with another request")
+			}
+
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "IdempotencyUsecase.Execute: repo reserve")
+		}
+
+		resp, err := handle(ctx)
+		if err != nil {
+			return err
+		}
+		if !resp.Succeeded() {
+			return errNotStored
+		}
+
+		if err := uc.repo.SetResponse(ctx, key, resp); err != nil {
+			return errors.Wrap(err, "IdempotencyUsecase.Execute: repo save")
+		}
+
+		return nil
+	})
+	if err != nil && !stderrors.Is(err, errNotStored) {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+// Purge - Deletes expired keys, returns the number of deleted keys
+func (uc *IdempotencyUsecase) Purge(ctx context.Context) (int64, error) {
+	deleted, err := uc.repo.DeleteExpired(ctx, time.Now().UTC())
+	if err != nil {
+		return 0, errors.Wrap(err, "IdempotencyUsecase.Purge: repo exec")
+	}
+
+	return deleted, nil
+}
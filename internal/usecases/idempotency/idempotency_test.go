@@ -0,0 +1,221 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotency_RequestHash(t *testing.T) {
+	hash := RequestHash("POST", "/api/v1/subscription/create", []byte(`{"price":100}`))
+
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, RequestHash("POST", "/api/v1/subscription/create", []byte(`{"price":100}`)))
+	assert.NotEqual(t, hash, RequestHash("POST", "/api/v1/subscription/create", []byte(`{"price":200}`)))
+}
+
+func expectTransaction(mockTx *mocks.MockTransactor, ctx context.Context) {
+	mockTx.EXPECT().
+		WithinTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+}
+
+func TestIdempotency_Execute_Stored(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIdempotencyRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	us := NewIdempotencyUsecase(mockRepo, mockTx, time.Hour, mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	resp := entities.IdempotentResponse{StatusCode: 201, Body: []byte(`{"id":1}`)}
+	expectTransaction(mockTx, ctx)
+	gomock.InOrder(
+		mockRepo.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, key entities.IdempotencyKey) error {
+				assert.Equal(t, "key-1", key.Key)
+				assert.Equal(t, "hash", key.RequestHash)
+				assert.Equal(t, time.Hour, key.ExpiresAt.Sub(key.CreatedAt))
+				return nil
+			}),
+		mockRepo.EXPECT().SetResponse(ctx, "key-1", resp).Return(nil),
+	)
+
+	calls := 0
+	stored, err := us.Execute(ctx, "key-1", "hash", func(ctx context.Context) (entities.IdempotentResponse, error) {
+		calls++
+		return resp, nil
+	})
+
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotency_Execute_DefaultTTL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIdempotencyRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	us := NewIdempotencyUsecase(mockRepo, mockTx, 0, mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	expectTransaction(mockTx, ctx)
+	mockRepo.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, key entities.IdempotencyKey) error {
+			assert.Equal(t, DefaultTTL, key.ExpiresAt.Sub(key.CreatedAt))
+			return nil
+		})
+	mockRepo.EXPECT().SetResponse(ctx, "key-1", gomock.Any()).Return(nil)
+
+	_, err := us.Execute(ctx, "key-1", "hash", func(ctx context.Context) (entities.IdempotentResponse, error) {
+		return entities.IdempotentResponse{StatusCode: 204}, nil
+	})
+
+	require.NoError(t, err)
+}
+
+func TestIdempotency_Execute_Replay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIdempotencyRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	us := NewIdempotencyUsecase(mockRepo, mockTx, time.Hour, mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	key := &entities.IdempotencyKey{Key: "key-1", RequestHash: "hash", StatusCode: 201}
+	expectTransaction(mockTx, ctx)
+	mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(errors.Wrap(errors.ErrAlreadyExists, "repo"))
+	mockRepo.EXPECT().Get(ctx, "key-1", gomock.Any()).Return(key, nil)
+
+	stored, err := us.Execute(ctx, "key-1", "hash", func(ctx context.Context) (entities.IdempotentResponse, error) {
+		t.Fatal("handle runs for the stored key")
+		return entities.IdempotentResponse{}, nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, key, stored)
+}
+
+func TestIdempotency_Execute_ErrorAnotherRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIdempotencyRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	us := NewIdempotencyUsecase(mockRepo, mockTx, time.Hour, mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	expectTransaction(mockTx, ctx)
+	mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(errors.ErrAlreadyExists)
+	mockRepo.EXPECT().Get(ctx, "key-1", gomock.Any()).Return(&entities.IdempotencyKey{Key: "key-1", RequestHash: "hash"}, nil)
+
+	_, err := us.Execute(ctx, "key-1", "another", func(ctx context.Context) (entities.IdempotentResponse, error) {
+		t.Fatal("handle runs for the key used with another request")
+		return entities.IdempotentResponse{}, nil
+	})
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+}
+
+func TestIdempotency_Execute_NotSucceeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIdempotencyRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	us := NewIdempotencyUsecase(mockRepo, mockTx, time.Hour, mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	var txErr error
+	mockTx.EXPECT().
+		WithinTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			txErr = fn(ctx)
+			return txErr
+		})
+	mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+	stored, err := us.Execute(ctx, "key-1", "hash", func(ctx context.Context) (entities.IdempotentResponse, error) {
+		return entities.IdempotentResponse{StatusCode: 422}, nil
+	})
+
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+	assert.Error(t, txErr, "the reserved key is rolled back")
+}
+
+func TestIdempotency_Execute_ErrorSave(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIdempotencyRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	us := NewIdempotencyUsecase(mockRepo, mockTx, time.Hour, mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	expectTransaction(mockTx, ctx)
+	mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	mockRepo.EXPECT().SetResponse(ctx, "key-1", gomock.Any()).Return(errors.New("error repo"))
+
+	_, err := us.Execute(ctx, "key-1", "hash", func(ctx context.Context) (entities.IdempotentResponse, error) {
+		return entities.IdempotentResponse{StatusCode: 201}, nil
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "IdempotencyUsecase.Execute: repo save")
+}
+
+func TestIdempotency_Execute_ErrorReserve(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIdempotencyRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	us := NewIdempotencyUsecase(mockRepo, mockTx, time.Hour, mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	expectTransaction(mockTx, ctx)
+	mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(errors.New("error repo"))
+
+	_, err := us.Execute(ctx, "key-1", "hash", func(ctx context.Context) (entities.IdempotentResponse, error) {
+		t.Fatal("handle runs without the reserved key")
+		return entities.IdempotentResponse{}, nil
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "IdempotencyUsecase.Execute: repo reserve")
+}
+
+func TestIdempotency_Purge_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockIdempotencyRepository(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewIdempotencyUsecase(mockRepo, mocks.NewMockTransactor(ctrl), time.Hour, mockLogger)
+	ctx := context.Background()
+
+	mockRepo.EXPECT().DeleteExpired(ctx, gomock.Any()).Return(int64(2), nil)
+
+	deleted, err := us.Purge(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/interfaces/repositories"
+)
+
+type ServiceUsecase struct {
+	repo   repositories.ServiceRepository
+	tx     repositories.Transactor
+	logger observability.Logger
+}
+
+// NewServiceUsecase - Constructor ServiceUsecase
+func NewServiceUsecase(repo repositories.ServiceRepository, tx repositories.Transactor, logger observability.Logger) ServiceUsecase {
+	return ServiceUsecase{repo: repo, tx: tx, logger: logger}
+}
+
+// Create - Adds new service to the catalog, subscriptions with the same normalized name
+// are linked to it and take its name within the same transaction
+func (uc *ServiceUsecase) Create(ctx context.Context, service entities.Service) (*entities.Service, error) {
+	var created *entities.Service
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = uc.repo.Create(ctx, service)
+		if err != nil {
+			return errors.Wrap(err, "ServiceUsecase.Create: repo exec")
+		}
+
+		if _, err := uc.repo.LinkSubscriptions(ctx, *created); err != nil {
+			return errors.Wrap(err, "ServiceUsecase.Create: link subscriptions")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetByID - Returns service by ID
+func (uc *ServiceUsecase) GetByID(ctx context.Context, id int64) (*entities.Service, error) {
+	service, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "ServiceUsecase.GetByID: repo exec")
+	}
+
+	return service, nil
+}
+
+// List - Returns services of the catalog by filter
+func (uc *ServiceUsecase) List(ctx context.Context, filter entities.ServiceFilter) ([]entities.Service, error) {
+	services, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "ServiceUsecase.List: repo exec")
+	}
+
+	return services, nil
+}
+
+// Update - Updated fields of service by ID, the renamed service passes its name to the linked subscriptions.
+// ErrAlreadyExists is returned when the new name is the name of another service.
+func (uc *ServiceUsecase) Update(ctx context.Context, id int64, fields map[string]any) error {
+	return uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := uc.repo.GetByID(ctx, id); err != nil {
+			return errors.Wrap(err, "ServiceUsecase.Update: repo getById")
+		}
+
+		if err := uc.repo.Update(ctx, id, fields); err != nil {
+			return errors.Wrap(err, "ServiceUsecase.Update: repo exec")
+		}
+
+		if _, ok := fields["name"]; !ok {
+			return nil
+		}
+
+		updated, err := uc.repo.GetByID(ctx, id)
+		if err != nil {
+			return errors.Wrap(err, "ServiceUsecase.Update: repo getById")
+		}
+
+		if _, err := uc.repo.LinkSubscriptions(ctx, *updated); err != nil {
+			return errors.Wrap(err, "ServiceUsecase.Update: link subscriptions")
+		}
+
+		return nil
+	})
+}
+
+// Delete - Deletes service by ID, linked subscriptions keep their names
+func (uc *ServiceUsecase) Delete(ctx context.Context, id int64) error {
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		return errors.Wrap(err, "ServiceUsecase.Delete: repo exec")
+	}
+
+	return nil
+}
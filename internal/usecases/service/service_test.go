@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func expectTransaction(mockTx *mocks.MockTransactor, ctx context.Context) {
+	mockTx.EXPECT().
+		WithinTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+}
+
+func TestService_Create_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockServiceRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	us := NewServiceUsecase(mockRepo, mockTx, mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	service := entities.Service{Name: "Spotify", Category: "music"}
+	created := &entities.Service{ID: 1, Name: "Spotify", Category: "music"}
+
+	expectTransaction(mockTx, ctx)
+	mockRepo.EXPECT().Create(ctx, service).Return(created, nil)
+	mockRepo.EXPECT().LinkSubscriptions(ctx, *created).Return(int64(2), nil)
+
+	res, err := us.Create(ctx, service)
+
+	require.NoError(t, err)
+	assert.Equal(t, created, res)
+}
+
+func TestService_Create_ErrorAlreadyExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockServiceRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	us := NewServiceUsecase(mockRepo, mockTx, mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	expectTransaction(mockTx, ctx)
+	mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil, errors.ErrAlreadyExists)
+
+	_, err := us.Create(ctx, entities.Service{Name: "spotify"})
+
+	assert.ErrorIs(t, err, errors.ErrAlreadyExists)
+}
+
+func TestService_Update_Rename(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockServiceRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	us := NewServiceUsecase(mockRepo, mockTx, mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	fields := map[string]any{"name": "Spotify Music"}
+	renamed := &entities.Service{ID: 1, Name: "Spotify Music"}
+
+	expectTransaction(mockTx, ctx)
+	gomock.InOrder(
+		mockRepo.EXPECT().GetByID(ctx, int64(1)).Return(&entities.Service{ID: 1, Name: "Spotify"}, nil),
+		mockRepo.EXPECT().Update(ctx, int64(1), fields).Return(nil),
+		mockRepo.EXPECT().GetByID(ctx, int64(1)).Return(renamed, nil),
+		mockRepo.EXPECT().LinkSubscriptions(ctx, *renamed).Return(int64(3), nil),
+	)
+
+	err := us.Update(ctx, 1, fields)
+
+	require.NoError(t, err)
+}
+
+func TestService_Update_WithoutRename(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockServiceRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	us := NewServiceUsecase(mockRepo, mockTx, mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	fields := map[string]any{"category": "music"}
+
+	expectTransaction(mockTx, ctx)
+	mockRepo.EXPECT().GetByID(ctx, int64(1)).Return(&entities.Service{ID: 1}, nil)
+	mockRepo.EXPECT().Update(ctx, int64(1), fields).Return(nil)
+
+	err := us.Update(ctx, 1, fields)
+
+	require.NoError(t, err)
+}
+
+func TestService_Update_ErrorNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockServiceRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	us := NewServiceUsecase(mockRepo, mockTx, mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	expectTransaction(mockTx, ctx)
+	mockRepo.EXPECT().GetByID(ctx, int64(1)).Return(nil, errors.ErrNotFound)
+
+	err := us.Update(ctx, 1, map[string]any{"category": "music"})
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
+
+func TestService_Delete_ErrorRepo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockServiceRepository(ctrl)
+	us := NewServiceUsecase(mockRepo, mocks.NewMockTransactor(ctrl), mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	mockRepo.EXPECT().Delete(ctx, int64(1)).Return(errors.ErrNotFound)
+
+	err := us.Delete(ctx, 1)
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
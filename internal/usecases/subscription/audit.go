@@ -0,0 +1,71 @@
+package subscription
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/usecases/audit"
+)
+
+// recordAudit - stores the audit entry of the subscription change within the running transaction
+func (uc *SubscriptionUsecase) recordAudit(ctx context.Context, id int64, action entities.AuditActionType, before, after *entities.Subscription) error {
+	return uc.recordSnapshots(ctx, id, action, subscriptionSnapshot(before), subscriptionSnapshot(after))
+}
+
+// recordSnapshots - stores the audit entry of the subscription snapshots taken before and after the change
+func (uc *SubscriptionUsecase) recordSnapshots(ctx context.Context, id int64, action entities.AuditActionType, before, after map[string]any) error {
+	entry, err := audit.NewEntry(ctx, entities.AuditEntitySubscription, id, action, before, after)
+	if err != nil {
+		return errors.Wrap(err, "build audit entry")
+	}
+
+	if err := uc.auditRepo.Create(ctx, entry); err != nil {
+		return errors.Wrap(err, "repo audit")
+	}
+
+	return nil
+}
+
+// subscriptionSnapshot - fields of the subscription kept in the audit log
+func subscriptionSnapshot(sub *entities.Subscription) map[string]any {
+	if sub == nil {
+		return nil
+	}
+
+	return map[string]any{
+		"service_name":     sub.ServiceName,
+		"user_id":          sub.UserId,
+		"price":            sub.Price,
+		"currency":         sub.Currency,
+		"billing_period":   sub.BillingPeriod,
+		"billing_interval": sub.BillingInterval,
+		"start_date":       sub.StartDate.Format(time.DateOnly),
+		"end_date":         snapshotDate(sub.EndDate),
+		"trial_end_date":   snapshotDate(sub.TrialEndDate),
+		"status":           sub.Status,
+		"category":         sub.Category,
+		"tags":             sub.Tags,
+		"deleted_at":       snapshotTime(sub.DeletedAt),
+	}
+}
+
+// snapshotDate - date of the snapshot, nil when it is not set
+func snapshotDate(date sql.NullTime) any {
+	if !date.Valid {
+		return nil
+	}
+
+	return date.Time.Format(time.DateOnly)
+}
+
+// snapshotTime - time of the snapshot, nil when it is not set
+func snapshotTime(at sql.NullTime) any {
+	if !at.Valid {
+		return nil
+	}
+
+	return at.Time.UTC().Format(time.RFC3339)
+}
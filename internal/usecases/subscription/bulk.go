@@ -0,0 +1,125 @@
+package subscription
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+)
+
+// errBulkRolledBack - the atomic bulk is rolled back because one of its items has failed
+var errBulkRolledBack = errors.New("bulk rolled back")
+
+// CreateBulk - Adds subscriptions in one transaction, returns the result of every item.
+// IDs of the rolled back bulk are not returned.
+func (uc *SubscriptionUsecase) CreateBulk(ctx context.Context, subs []entities.Subscription, mode entities.BulkModeType) (*entities.BulkResult, error) {
+	result, err := uc.runBulk(ctx, len(subs), mode, func(ctx context.Context, i int) (int64, error) {
+		created, err := uc.Create(ctx, subs[i])
+		if err != nil {
+			return 0, err
+		}
+
+		return created.ID, nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "SubscriptionUsecase.CreateBulk")
+	}
+
+	if !result.Committed {
+		for i := range result.Items {
+			result.Items[i].ID = 0
+		}
+	}
+
+	return result, nil
+}
+
+// UpdateBulk - Updates subscriptions in one transaction, returns the result of every item
+func (uc *SubscriptionUsecase) UpdateBulk(ctx context.Context, updates []entities.BulkUpdate, mode entities.BulkModeType) (*entities.BulkResult, error) {
+	result, err := uc.runBulk(ctx, len(updates), mode, func(ctx context.Context, i int) (int64, error) {
+		return updates[i].ID, uc.Update(ctx, updates[i].ID, updates[i].Fields, updates[i].Version)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "SubscriptionUsecase.UpdateBulk")
+	}
+
+	return result, nil
+}
+
+// DeleteBulk - Soft deletes subscriptions in one transaction, returns the result of every item
+func (uc *SubscriptionUsecase) DeleteBulk(ctx context.Context, ids []int64, mode entities.BulkModeType) (*entities.BulkResult, error) {
+	result, err := uc.runBulk(ctx, len(ids), mode, func(ctx context.Context, i int) (int64, error) {
+		return ids[i], uc.Delete(ctx, ids[i], 0)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "SubscriptionUsecase.DeleteBulk")
+	}
+
+	return result, nil
+}
+
+// runBulk - runs fn for every item in one transaction.
+// The atomic bulk stops at the first failed item and is rolled back,
+// the best-effort bulk rolls back the failed item only and goes on.
+func (uc *SubscriptionUsecase) runBulk(ctx context.Context, count int, mode entities.BulkModeType, fn func(ctx context.Context, i int) (int64, error)) (*entities.BulkResult, error) {
+	result := &entities.BulkResult{Mode: mode, Items: make([]entities.BulkItemResult, count)}
+	for i := range result.Items {
+		result.Items[i].Index = i
+	}
+
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		for i := range result.Items {
+			item := &result.Items[i]
+
+			run := func(ctx context.Context) error {
+				id, err := fn(ctx, i)
+				item.ID = id
+
+				return err
+			}
+
+			var err error
+			switch mode {
+			case entities.BulkModeAtomic:
+				err = run(ctx)
+			case entities.BulkModeBestEffort:
+				err = uc.tx.WithinSavepoint(ctx, run)
+			default:
+				return errors.Wrap(errors.ErrInvalidInput, fmt.Sprintf("bulk mode %s", mode))
+			}
+
+			if err != nil {
+				item.Err = err
+				if mode == entities.BulkModeAtomic {
+					return errBulkRolledBack
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil && err != errBulkRolledBack {
+		return nil, err
+	}
+
+	result.Committed = err == nil
+
+	return result, nil
+}
+
+// ItemErrorMessage - message of the failed bulk item or imported row the client is shown,
+// invalid input is explained and internal errors are not exposed
+func ItemErrorMessage(err error) string {
+	switch {
+	case stderrors.Is(err, errors.ErrNotFound):
+		return "Not found"
+	case stderrors.Is(err, errors.ErrConflict):
+		return "Precondition failed"
+	case stderrors.Is(err, errors.ErrInvalidInput):
+		return err.Error()
+	}
+
+	return "Internal server error"
+}
@@ -0,0 +1,188 @@
+package subscription
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expectBulkTransaction - the bulk transaction is joined by the transactions of its items
+func expectBulkTransaction(mockTx *mocks.MockTransactor) {
+	mockTx.EXPECT().
+		WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).
+		AnyTimes()
+}
+
+// expectSavepoint - the savepoint of the best-effort item
+func expectSavepoint(mockTx *mocks.MockTransactor, times int) {
+	mockTx.EXPECT().
+		WithinSavepoint(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		}).
+		Times(times)
+}
+
+func TestSubscription_CreateBulk_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	second := subTest
+	second.ServiceName = "Second service"
+	created, createdSecond := subTest, second
+	createdSecond.ID = 2
+
+	expectBulkTransaction(mockTx)
+	gomock.InOrder(
+		mockSubRepo.EXPECT().Create(ctx, subTest).Return(&created, nil),
+		mockAudit.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+		mockSubRepo.EXPECT().Create(ctx, second).Return(&createdSecond, nil),
+		mockAudit.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+	)
+
+	result, err := us.CreateBulk(ctx, []entities.Subscription{subTest, second}, entities.BulkModeAtomic)
+
+	require.NoError(t, err)
+	assert.True(t, result.Committed)
+	assert.Equal(t, []entities.BulkItemResult{{Index: 0, ID: 1}, {Index: 1, ID: 2}}, result.Items)
+}
+
+func TestSubscription_CreateBulk_AtomicRolledBack(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	second := subTest
+	second.ServiceName = "Second service"
+	created := subTest
+
+	expectBulkTransaction(mockTx)
+	gomock.InOrder(
+		mockSubRepo.EXPECT().Create(ctx, subTest).Return(&created, nil),
+		mockAudit.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+		mockSubRepo.EXPECT().Create(ctx, second).Return(nil, errors.New("error repo")),
+	)
+
+	result, err := us.CreateBulk(ctx, []entities.Subscription{subTest, second, subTest}, entities.BulkModeAtomic)
+
+	require.NoError(t, err)
+	assert.False(t, result.Committed)
+	require.Len(t, result.Items, 3)
+	assert.Equal(t, int64(0), result.Items[0].ID)
+	assert.NoError(t, result.Items[0].Err)
+	assert.Error(t, result.Items[1].Err)
+	assert.NoError(t, result.Items[2].Err)
+}
+
+func TestSubscription_UpdateBulk_BestEffort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	fields := map[string]any{"service_name": "Updated service"}
+	updates := []entities.BulkUpdate{{ID: 5, Fields: fields}, {ID: subTest.ID, Fields: fields}}
+
+	expectBulkTransaction(mockTx)
+	expectSavepoint(mockTx, 2)
+	gomock.InOrder(
+		mockSubRepo.EXPECT().GetByID(ctx, int64(5), false).Return(nil, errors.ErrNotFound),
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&subTest, nil),
+		mockSubRepo.EXPECT().Update(ctx, subTest.ID, fields, int64(0)).Return(nil),
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&subTest, nil),
+		mockAudit.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+	)
+
+	result, err := us.UpdateBulk(ctx, updates, entities.BulkModeBestEffort)
+
+	require.NoError(t, err)
+	assert.True(t, result.Committed)
+	assert.ErrorIs(t, result.Items[0].Err, errors.ErrNotFound)
+	assert.Equal(t, int64(5), result.Items[0].ID)
+	assert.NoError(t, result.Items[1].Err)
+	assert.Equal(t, subTest.ID, result.Items[1].ID)
+}
+
+func TestSubscription_DeleteBulk_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	expectBulkTransaction(mockTx)
+	gomock.InOrder(
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&subTest, nil),
+		mockSubRepo.EXPECT().Delete(ctx, subTest.ID, int64(0)).Return(nil),
+		mockAudit.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+	)
+
+	result, err := us.DeleteBulk(ctx, []int64{subTest.ID}, entities.BulkModeAtomic)
+
+	require.NoError(t, err)
+	assert.True(t, result.Committed)
+	assert.Equal(t, []entities.BulkItemResult{{Index: 0, ID: subTest.ID}}, result.Items)
+}
+
+func TestSubscription_DeleteBulk_ErrorMode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	expectBulkTransaction(mockTx)
+
+	_, err := us.DeleteBulk(ctx, []int64{subTest.ID}, entities.BulkModeType("unknown"))
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+}
+
+func TestSubscription_ItemErrorMessage(t *testing.T) {
+	assert.Equal(t, "Not found", ItemErrorMessage(errors.Wrap(errors.ErrNotFound, "repo")))
+	assert.Equal(t, "Precondition failed", ItemErrorMessage(errors.Wrap(errors.ErrConflict, "repo")))
+	assert.Equal(t, "unknown user: invalid input", ItemErrorMessage(errors.Wrap(errors.ErrInvalidInput, "unknown user")))
+	assert.Equal(t, "Internal server error", ItemErrorMessage(errors.New("error repo")))
+}
@@ -0,0 +1,27 @@
+package subscription
+
+import (
+	"context"
+	"math"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+)
+
+// convert - converts amount from currency into the reporting currency
+func (uc *SubscriptionUsecase) convert(ctx context.Context, amount int64, from, to string) (*entities.Converted, error) {
+	rate, err := uc.rates.Rate(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.Converted{
+		Amount:   roundAmount(float64(amount) * rate),
+		Currency: to,
+		Rate:     rate,
+	}, nil
+}
+
+// roundAmount - rounds amount to hundredths of the currency unit
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
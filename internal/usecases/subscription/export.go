@@ -0,0 +1,36 @@
+package subscription
+
+import (
+	"context"
+	"time"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+)
+
+// Export - Passes all subscriptions matching Query Criteria to fn, pagination of the criteria is ignored.
+// Rows are streamed from the repository within one read transaction, prices are converted into params.Currency when it is set.
+func (uc *SubscriptionUsecase) Export(ctx context.Context, params entities.QueryCriteria, fn func(entities.Subscription) error) error {
+	now := time.Now().UTC()
+
+	return uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := uc.repo.Export(ctx, params, func(sub entities.Subscription) error {
+			refreshStatus(&sub, now)
+
+			if params.Currency != "" {
+				converted, err := uc.convert(ctx, int64(sub.Price), sub.Currency, params.Currency)
+				if err != nil {
+					return errors.Wrap(err, "SubscriptionUsecase.Export: convert")
+				}
+				sub.ConvertedPrice = converted
+			}
+
+			return fn(sub)
+		})
+		if err != nil {
+			return errors.Wrap(err, "SubscriptionUsecase.Export: repo exec")
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,108 @@
+package subscription
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/mocks"
+)
+
+// expectExport - repository passes subs to the export callback
+func expectExport(mockSubRepo *mocks.MockSubscriptionRepository, ctx context.Context, subs []entities.Subscription) {
+	mockSubRepo.EXPECT().
+		Export(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, params entities.QueryCriteria, fn func(entities.Subscription) error) error {
+			for _, sub := range subs {
+				if err := fn(sub); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+}
+
+func TestSubscription_Export_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	expectTransaction(mockTx, ctx)
+	expectExport(mockSubRepo, ctx, []entities.Subscription{{ID: 1, Price: 10, Currency: "USD"}, {ID: 2, Price: 20, Currency: "RUB"}})
+	mockRates.EXPECT().Rate(ctx, "USD", "RUB").Return(90.5, nil)
+	mockRates.EXPECT().Rate(ctx, "RUB", "RUB").Return(1.0, nil)
+
+	var exported []entities.Subscription
+	err := us.Export(ctx, entities.QueryCriteria{Currency: "RUB"}, func(sub entities.Subscription) error {
+		exported = append(exported, sub)
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Len(t, exported, 2)
+	assert.Equal(t, &entities.Converted{Amount: 905, Currency: "RUB", Rate: 90.5}, exported[0].ConvertedPrice)
+	assert.Equal(t, &entities.Converted{Amount: 20, Currency: "RUB", Rate: 1}, exported[1].ConvertedPrice)
+}
+
+func TestSubscription_Export_ErrorRate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	expectTransaction(mockTx, ctx)
+	expectExport(mockSubRepo, ctx, []entities.Subscription{{ID: 1, Price: 10, Currency: "USD"}})
+	mockRates.EXPECT().Rate(ctx, "USD", "XXX").Return(0.0, errors.Wrap(errors.ErrInvalidInput, "unknown currency XXX"))
+
+	err := us.Export(ctx, entities.QueryCriteria{Currency: "XXX"}, func(sub entities.Subscription) error {
+		t.Fatal("row must not be exported")
+		return nil
+	})
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+	assert.Contains(t, err.Error(), "SubscriptionUsecase.Export: repo exec")
+}
+
+func TestSubscription_Export_ErrorWrite(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	expectTransaction(mockTx, ctx)
+	expectExport(mockSubRepo, ctx, []entities.Subscription{{ID: 1}, {ID: 2}})
+
+	calls := 0
+	err := us.Export(ctx, entities.QueryCriteria{}, func(sub entities.Subscription) error {
+		calls++
+		return errors.New("broken pipe")
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,80 @@
+package subscription
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+)
+
+// CreateInput - fields of the new subscription as the clients send them, the start and end are months
+// in the format 01-2006 and the trial end is the day. The HTTP API and the CSV import validate it
+// by the same rules and convert it into the subscription with the same defaults.
+type CreateInput struct {
+	ServiceName     string    `validate:"required"`
+	UserId          uuid.UUID `validate:"required,uuid"`
+	Price           uint32    `validate:"required,gte=1,lte=4294967295"`
+	Currency        string    `validate:"omitempty,iso4217"`
+	BillingPeriod   string    `validate:"omitempty,oneof=weekly monthly quarterly yearly custom"`
+	BillingInterval uint16    `validate:"required_if=BillingPeriod custom,omitempty,gte=1,lte=120"`
+	StartDate       string    `validate:"required,datetime=01-2006"`
+	EndDate         string    `validate:"omitempty,datetime=01-2006"`
+	TrialEndDate    string    `validate:"omitempty,datetime=2006-01-02"`
+	Category        string    `validate:"omitempty,max=100"`
+	Tags            []string  `validate:"omitempty,max=20,dive,min=1,max=50"`
+}
+
+// ToEntity - Returns the subscription of the input, it is billed monthly unless the period is set
+func (in CreateInput) ToEntity() (entities.Subscription, error) {
+	sub := entities.Subscription{
+		ServiceName:     in.ServiceName,
+		UserId:          in.UserId,
+		Price:           in.Price,
+		Currency:        in.Currency,
+		BillingPeriod:   entities.BillingPeriodMonthly,
+		BillingInterval: 1,
+		Category:        in.Category,
+		Tags:            in.Tags,
+	}
+
+	if in.BillingPeriod != "" {
+		sub.BillingPeriod = entities.BillingPeriodType(in.BillingPeriod)
+	}
+	if sub.BillingPeriod == entities.BillingPeriodCustom {
+		sub.BillingInterval = in.BillingInterval
+	}
+	tmpDate, err := time.Parse("01-2006", in.StartDate)
+	if err != nil {
+		return entities.Subscription{}, fmt.Errorf("StartDate parse - %s", in.StartDate)
+	}
+	sub.StartDate = tmpDate
+
+	if in.EndDate != "" {
+		tmpDate, err := time.Parse("01-2006", in.EndDate)
+		if err != nil {
+			return entities.Subscription{}, fmt.Errorf("EndDate parse - %s", in.EndDate)
+		}
+
+		sub.EndDate = sql.NullTime{
+			Time:  tmpDate,
+			Valid: true,
+		}
+	}
+
+	if in.TrialEndDate != "" {
+		tmpDate, err := time.Parse("2006-01-02", in.TrialEndDate)
+		if err != nil {
+			return entities.Subscription{}, fmt.Errorf("TrialEndDate parse - %s", in.TrialEndDate)
+		}
+
+		sub.TrialEndDate = sql.NullTime{
+			Time:  tmpDate,
+			Valid: true,
+		}
+	}
+
+	return sub, nil
+}
@@ -0,0 +1,53 @@
+package subscription
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+)
+
+func TestSubscription_CreateInput_ToEntity(t *testing.T) {
+	userID := uuid.New()
+
+	sub, err := CreateInput{
+		ServiceName:  "Netflix",
+		UserId:       userID,
+		Price:        400,
+		StartDate:    "01-2025",
+		EndDate:      "12-2025",
+		TrialEndDate: "2025-01-15",
+	}.ToEntity()
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.Subscription{
+		ServiceName:     "Netflix",
+		UserId:          userID,
+		Price:           400,
+		BillingPeriod:   entities.BillingPeriodMonthly,
+		BillingInterval: 1,
+		StartDate:       time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:         sql.NullTime{Time: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		TrialEndDate:    sql.NullTime{Time: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), Valid: true},
+	}, sub)
+}
+
+func TestSubscription_CreateInput_ToEntityCustomPeriod(t *testing.T) {
+	sub, err := CreateInput{StartDate: "01-2025", BillingPeriod: "custom", BillingInterval: 45}.ToEntity()
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.BillingPeriodCustom, sub.BillingPeriod)
+	assert.Equal(t, uint16(45), sub.BillingInterval)
+}
+
+func TestSubscription_CreateInput_ToEntityErrorDate(t *testing.T) {
+	_, err := CreateInput{StartDate: "2025-01"}.ToEntity()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "StartDate parse")
+}
@@ -0,0 +1,105 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+)
+
+// Members - Returns members sharing the cost of the subscription, empty when its user pays entirely
+func (uc *SubscriptionUsecase) Members(ctx context.Context, id int64) ([]entities.Member, error) {
+	if _, err := uc.repo.GetByID(ctx, id, false); err != nil {
+		return nil, errors.Wrap(err, "SubscriptionUsecase.Members: repo getById")
+	}
+
+	members, err := uc.repo.GetMembers(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "SubscriptionUsecase.Members: repo exec")
+	}
+
+	return members, nil
+}
+
+// SetMembers - Replaces members of the subscription, empty members leave the subscription to its user.
+// ErrInvalidInput is returned when a user is listed twice, is unknown or the shares do not sum to 100% of the price.
+func (uc *SubscriptionUsecase) SetMembers(ctx context.Context, id int64, members []entities.Member) ([]entities.Member, error) {
+	var result []entities.Member
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := uc.repo.GetByID(ctx, id, false)
+		if err != nil {
+			return errors.Wrap(err, "repo getById")
+		}
+
+		if err := validateMembers(members, before.Price); err != nil {
+			return err
+		}
+
+		current, err := uc.repo.GetMembers(ctx, id)
+		if err != nil {
+			return errors.Wrap(err, "repo get members")
+		}
+
+		if err := uc.repo.SetMembers(ctx, id, members); err != nil {
+			return errors.Wrap(err, "repo set members")
+		}
+
+		result, err = uc.repo.GetMembers(ctx, id)
+		if err != nil {
+			return errors.Wrap(err, "repo get members")
+		}
+
+		after, err := uc.repo.GetByID(ctx, id, false)
+		if err != nil {
+			return errors.Wrap(err, "repo getById")
+		}
+
+		return uc.recordSnapshots(ctx, id, entities.AuditActionUpdate, membersSnapshot(before, current), membersSnapshot(after, result))
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "SubscriptionUsecase.SetMembers")
+	}
+
+	return result, nil
+}
+
+// membersSnapshot - fields of the subscription kept in the audit log with the shares of its members
+func membersSnapshot(sub *entities.Subscription, members []entities.Member) map[string]any {
+	shares := make([]map[string]any, 0, len(members))
+	for _, member := range members {
+		shares = append(shares, map[string]any{
+			"user_id":    member.UserId,
+			"share_type": member.ShareType,
+			"share":      member.Share,
+		})
+	}
+
+	snapshot := subscriptionSnapshot(sub)
+	snapshot["members"] = shares
+
+	return snapshot
+}
+
+// validateMembers - every user is listed once and the shares sum to 100% of the price
+func validateMembers(members []entities.Member, price uint32) error {
+	if len(members) == 0 {
+		return nil
+	}
+
+	users := make(map[uuid.UUID]bool, len(members))
+	for _, member := range members {
+		if users[member.UserId] {
+			return errors.Wrap(errors.ErrInvalidInput, fmt.Sprintf("user %s is listed twice", member.UserId))
+		}
+		users[member.UserId] = true
+	}
+
+	if !entities.SharesCoverPrice(members, price) {
+		return errors.Wrap(errors.ErrInvalidInput, fmt.Sprintf("shares of the members do not sum to 100%% of the price %d", price))
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package subscription
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscription_SetMembers_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	sub := subTest
+	sub.Price = 300
+	members := []entities.Member{
+		{UserId: sub.UserId, ShareType: entities.ShareTypeFixed, Share: 150},
+		{UserId: uuid.New(), ShareType: entities.ShareTypePercent, Share: 50},
+	}
+
+	updated := sub
+	updated.Version = sub.Version + 1
+
+	var entry entities.AuditEntry
+	expectTransaction(mockTx, ctx)
+	gomock.InOrder(
+		mockSubRepo.EXPECT().GetByID(ctx, sub.ID, false).Return(&sub, nil),
+		mockSubRepo.EXPECT().GetMembers(ctx, sub.ID).Return([]entities.Member{}, nil),
+		mockSubRepo.EXPECT().SetMembers(ctx, sub.ID, members).Return(nil),
+		mockSubRepo.EXPECT().GetMembers(ctx, sub.ID).Return(members, nil),
+		mockSubRepo.EXPECT().GetByID(ctx, sub.ID, false).Return(&updated, nil),
+		mockAudit.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, e entities.AuditEntry) error {
+			entry = e
+			return nil
+		}),
+	)
+
+	res, err := us.SetMembers(ctx, sub.ID, members)
+
+	require.NoError(t, err)
+	assert.Equal(t, members, res)
+	assert.Equal(t, entities.AuditActionUpdate, entry.Action)
+	assert.Contains(t, string(entry.Diff), `"members"`)
+}
+
+func TestSubscription_SetMembers_SharesNotCovered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	sub := subTest
+	sub.Price = 300
+
+	expectTransaction(mockTx, ctx)
+	mockSubRepo.EXPECT().GetByID(ctx, sub.ID, false).Return(&sub, nil)
+
+	res, err := us.SetMembers(ctx, sub.ID, []entities.Member{
+		{UserId: uuid.New(), ShareType: entities.ShareTypePercent, Share: 50},
+		{UserId: uuid.New(), ShareType: entities.ShareTypeFixed, Share: 100},
+	})
+
+	require.Nil(t, res)
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+	assert.Contains(t, err.Error(), "do not sum to 100%")
+}
+
+func TestSubscription_SetMembers_DuplicateUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	expectTransaction(mockTx, ctx)
+	mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&subTest, nil)
+
+	res, err := us.SetMembers(ctx, subTest.ID, []entities.Member{
+		{UserId: subTest.UserId, ShareType: entities.ShareTypePercent, Share: 50},
+		{UserId: subTest.UserId, ShareType: entities.ShareTypePercent, Share: 50},
+	})
+
+	require.Nil(t, res)
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+}
+
+func TestSubscription_Update_PriceWithFixedShares(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	sub := subTest
+	sub.Price = 300
+	fields := map[string]any{"price": uint32(400)}
+
+	expectTransaction(mockTx, ctx)
+	gomock.InOrder(
+		mockSubRepo.EXPECT().GetByID(ctx, sub.ID, false).Return(&sub, nil),
+		mockSubRepo.EXPECT().GetPriceHistory(ctx, sub.ID).Return([]entities.PriceChange{}, nil),
+		mockSubRepo.EXPECT().AddPrice(ctx, sub.ID, sub.Price, monthStart(sub.StartDate)).Return(nil),
+		mockSubRepo.EXPECT().AddPrice(ctx, sub.ID, uint32(400), monthStart(time.Now().UTC())).Return(nil),
+		mockSubRepo.EXPECT().Update(ctx, sub.ID, fields, int64(0)).Return(nil),
+		mockSubRepo.EXPECT().GetByID(ctx, sub.ID, false).Return(&sub, nil),
+		mockAudit.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+	)
+
+	err := us.Update(ctx, sub.ID, fields, 0)
+
+	require.NoError(t, err)
+}
+
+func TestSubscription_Members_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(nil, errors.ErrNotFound)
+
+	res, err := us.Members(ctx, subTest.ID)
+
+	require.Nil(t, res)
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
@@ -0,0 +1,110 @@
+package subscription
+
+import (
+	"context"
+	"time"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+)
+
+// Prices - Returns prices of the subscription in chronological order.
+// Subscription which price never changed has the only price effective from its start.
+func (uc *SubscriptionUsecase) Prices(ctx context.Context, id int64) ([]entities.PriceChange, error) {
+	sub, err := uc.repo.GetByID(ctx, id, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "SubscriptionUsecase.Prices: repo getById")
+	}
+
+	prices, err := uc.repo.GetPriceHistory(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "SubscriptionUsecase.Prices: repo exec")
+	}
+
+	if len(prices) == 0 {
+		prices = append(prices, entities.PriceChange{
+			Price:         sub.Price,
+			EffectiveFrom: sub.StartDate,
+			CreatedAt:     sub.CreatedAt,
+		})
+	}
+
+	return prices, nil
+}
+
+// recordPriceChange - keeps the price history in line with the updated price and start date.
+// The new price is effective from the current month or from the start month when the subscription starts later.
+// The price the subscription started with is recorded on the first change.
+func (uc *SubscriptionUsecase) recordPriceChange(ctx context.Context, sub entities.Subscription, fields map[string]any) error {
+	price, priceChanged := fields["price"].(uint32)
+	priceChanged = priceChanged && price != sub.Price
+
+	start := monthStart(sub.StartDate)
+	newStart, startChanged := fields["start_date"].(time.Time)
+	startChanged = startChanged && !monthStart(newStart).Equal(start)
+	if startChanged {
+		start = monthStart(newStart)
+	}
+
+	if !priceChanged && !startChanged {
+		return nil
+	}
+
+	prices, err := uc.repo.GetPriceHistory(ctx, sub.ID)
+	if err != nil {
+		return errors.Wrap(err, "repo get history")
+	}
+
+	switch {
+	case len(prices) == 0 && priceChanged:
+		if err := uc.repo.AddPrice(ctx, sub.ID, sub.Price, start); err != nil {
+			return errors.Wrap(err, "repo add start price")
+		}
+	case len(prices) > 0 && startChanged:
+		if err := uc.moveStartPrice(ctx, sub.ID, prices, start); err != nil {
+			return err
+		}
+	}
+
+	if !priceChanged {
+		return nil
+	}
+
+	effectiveFrom := monthStart(time.Now().UTC())
+	if start.After(effectiveFrom) {
+		effectiveFrom = start
+	}
+
+	if err := uc.repo.AddPrice(ctx, sub.ID, price, effectiveFrom); err != nil {
+		return errors.Wrap(err, "repo add price")
+	}
+
+	return nil
+}
+
+// moveStartPrice - replaces the first price of the history with the price effective at the new start month.
+// Prices effective before the new start are dropped.
+func (uc *SubscriptionUsecase) moveStartPrice(ctx context.Context, id int64, prices []entities.PriceChange, start time.Time) error {
+	startPrice := prices[0].Price
+	for _, p := range prices {
+		if p.EffectiveFrom.After(start) {
+			break
+		}
+		startPrice = p.Price
+	}
+
+	until := start
+	if prices[0].EffectiveFrom.After(until) {
+		until = prices[0].EffectiveFrom
+	}
+
+	if err := uc.repo.DeletePricesUntil(ctx, id, until); err != nil {
+		return errors.Wrap(err, "repo delete start price")
+	}
+
+	if err := uc.repo.AddPrice(ctx, id, startPrice, start); err != nil {
+		return errors.Wrap(err, "repo add start price")
+	}
+
+	return nil
+}
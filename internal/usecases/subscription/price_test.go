@@ -0,0 +1,213 @@
+package subscription
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscription_Update_FirstPriceChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	fields := map[string]any{"price": uint32(250)}
+
+	expectTransaction(mockTx, ctx)
+	gomock.InOrder(
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&subTest, nil),
+		mockSubRepo.EXPECT().GetPriceHistory(ctx, subTest.ID).Return([]entities.PriceChange{}, nil),
+		mockSubRepo.EXPECT().AddPrice(ctx, subTest.ID, subTest.Price, monthStart(subTest.StartDate)).Return(nil),
+		mockSubRepo.EXPECT().AddPrice(ctx, subTest.ID, uint32(250), monthStart(time.Now().UTC())).Return(nil),
+		mockSubRepo.EXPECT().Update(ctx, subTest.ID, fields, int64(0)).Return(nil),
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&subTest, nil),
+		mockAudit.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+	)
+
+	err := us.Update(ctx, subTest.ID, fields, 0)
+
+	require.NoError(t, err)
+}
+
+func TestSubscription_Update_NextPriceChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	fields := map[string]any{"price": uint32(300)}
+	history := []entities.PriceChange{
+		{Price: subTest.Price, EffectiveFrom: monthStart(subTest.StartDate)},
+		{Price: 250, EffectiveFrom: monthStart(time.Now().UTC())},
+	}
+
+	expectTransaction(mockTx, ctx)
+	gomock.InOrder(
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&subTest, nil),
+		mockSubRepo.EXPECT().GetPriceHistory(ctx, subTest.ID).Return(history, nil),
+		mockSubRepo.EXPECT().AddPrice(ctx, subTest.ID, uint32(300), monthStart(time.Now().UTC())).Return(nil),
+		mockSubRepo.EXPECT().Update(ctx, subTest.ID, fields, int64(0)).Return(nil),
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&subTest, nil),
+		mockAudit.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+	)
+
+	err := us.Update(ctx, subTest.ID, fields, 0)
+
+	require.NoError(t, err)
+}
+
+func TestSubscription_Update_PriceChangeFutureStart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	sub := subTest
+	sub.StartDate = monthStart(time.Now().UTC()).AddDate(0, 3, 0)
+	fields := map[string]any{"price": uint32(250)}
+
+	expectTransaction(mockTx, ctx)
+	gomock.InOrder(
+		mockSubRepo.EXPECT().GetByID(ctx, sub.ID, false).Return(&sub, nil),
+		mockSubRepo.EXPECT().GetPriceHistory(ctx, sub.ID).Return([]entities.PriceChange{}, nil),
+		mockSubRepo.EXPECT().AddPrice(ctx, sub.ID, sub.Price, sub.StartDate).Return(nil),
+		mockSubRepo.EXPECT().AddPrice(ctx, sub.ID, uint32(250), sub.StartDate).Return(nil),
+		mockSubRepo.EXPECT().Update(ctx, sub.ID, fields, int64(0)).Return(nil),
+		mockSubRepo.EXPECT().GetByID(ctx, sub.ID, false).Return(&sub, nil),
+		mockAudit.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+	)
+
+	err := us.Update(ctx, sub.ID, fields, 0)
+
+	require.NoError(t, err)
+}
+
+func TestSubscription_Update_StartDateMovesHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	sub := subTest
+	sub.StartDate = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	newStart := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+	fields := map[string]any{"start_date": newStart}
+	history := []entities.PriceChange{
+		{Price: 100, EffectiveFrom: sub.StartDate},
+		{Price: 200, EffectiveFrom: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		{Price: 300, EffectiveFrom: time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	expectTransaction(mockTx, ctx)
+	gomock.InOrder(
+		mockSubRepo.EXPECT().GetByID(ctx, sub.ID, false).Return(&sub, nil),
+		mockSubRepo.EXPECT().GetPriceHistory(ctx, sub.ID).Return(history, nil),
+		mockSubRepo.EXPECT().DeletePricesUntil(ctx, sub.ID, newStart).Return(nil),
+		mockSubRepo.EXPECT().AddPrice(ctx, sub.ID, uint32(200), newStart).Return(nil),
+		mockSubRepo.EXPECT().Update(ctx, sub.ID, fields, int64(0)).Return(nil),
+		mockSubRepo.EXPECT().GetByID(ctx, sub.ID, false).Return(&sub, nil),
+		mockAudit.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+	)
+
+	err := us.Update(ctx, sub.ID, fields, 0)
+
+	require.NoError(t, err)
+}
+
+func TestSubscription_Update_ErrorPriceHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	fields := map[string]any{"price": uint32(250)}
+
+	expectTransaction(mockTx, ctx)
+	mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&subTest, nil)
+	mockSubRepo.EXPECT().GetPriceHistory(ctx, subTest.ID).Return(nil, errors.New("error repo"))
+
+	err := us.Update(ctx, subTest.ID, fields, 0)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SubscriptionUsecase.Update: price history: repo get history")
+}
+
+func TestSubscription_Prices_WithoutHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&subTest, nil)
+	mockSubRepo.EXPECT().GetPriceHistory(ctx, subTest.ID).Return([]entities.PriceChange{}, nil)
+
+	prices, err := us.Prices(ctx, subTest.ID)
+
+	require.NoError(t, err)
+	require.Equal(t, []entities.PriceChange{{Price: subTest.Price, EffectiveFrom: subTest.StartDate}}, prices)
+}
+
+func TestSubscription_Prices_ErrorNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(nil, errors.ErrNotFound)
+
+	prices, err := us.Prices(ctx, subTest.ID)
+
+	require.Nil(t, prices)
+	require.ErrorIs(t, err, errors.ErrNotFound)
+}
@@ -0,0 +1,39 @@
+package subscription
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+)
+
+// Renewals - Returns subscriptions of the user that are billed or were billed,
+// paused and cancelled subscriptions are skipped because they are not renewed.
+func (uc *SubscriptionUsecase) Renewals(ctx context.Context, userID uuid.UUID) ([]entities.Subscription, error) {
+	params := entities.QueryCriteria{
+		Filter: entities.FilterParams{UserId: userID},
+		Sort:   []entities.SortParams{{SortBy: entities.SortTypeID, SortOrder: entities.SortOrderTypeAsc}},
+	}
+	now := time.Now().UTC()
+
+	subs := make([]entities.Subscription, 0)
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		return uc.repo.Export(ctx, params, func(sub entities.Subscription) error {
+			refreshStatus(&sub, now)
+			if sub.Status == entities.SubscriptionStatusPaused || sub.Status == entities.SubscriptionStatusCancelled {
+				return nil
+			}
+
+			subs = append(subs, sub)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "SubscriptionUsecase.Renewals: repo exec")
+	}
+
+	return subs, nil
+}
@@ -0,0 +1,79 @@
+package subscription
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/mocks"
+)
+
+func TestSubscription_Renewals_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	expectTransaction(mockTx, ctx)
+	mockSubRepo.EXPECT().
+		Export(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, params entities.QueryCriteria, fn func(entities.Subscription) error) error {
+			assert.Equal(t, userID, params.Filter.UserId)
+
+			for _, sub := range []entities.Subscription{
+				{ID: 1, Status: entities.SubscriptionStatusActive},
+				{ID: 2, Status: entities.SubscriptionStatusPaused},
+				{ID: 3, Status: entities.SubscriptionStatusCancelled},
+				{ID: 4, Status: entities.SubscriptionStatusTrial},
+			} {
+				if err := fn(sub); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	subs, err := us.Renewals(ctx, userID)
+
+	require.NoError(t, err)
+	require.Len(t, subs, 2)
+	assert.Equal(t, int64(1), subs[0].ID)
+	assert.Equal(t, int64(4), subs[1].ID)
+}
+
+func TestSubscription_Renewals_ErrorRepo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	expectTransaction(mockTx, ctx)
+	mockSubRepo.EXPECT().
+		Export(ctx, gomock.Any(), gomock.Any()).
+		Return(errors.New("error repo"))
+
+	subs, err := us.Renewals(ctx, uuid.New())
+
+	require.Error(t, err)
+	require.Nil(t, subs)
+	assert.Contains(t, err.Error(), "SubscriptionUsecase.Renewals: repo exec")
+}
@@ -0,0 +1,51 @@
+package subscription
+
+import (
+	"context"
+	"time"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+)
+
+// Restore - Restores soft-deleted subscription by ID
+func (uc *SubscriptionUsecase) Restore(ctx context.Context, id int64) (*entities.Subscription, error) {
+	var sub *entities.Subscription
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		deleted, err := uc.repo.GetByID(ctx, id, true)
+		if err != nil {
+			return errors.Wrap(err, "repo getById")
+		}
+
+		if !deleted.DeletedAt.Valid {
+			return errors.Wrap(errors.ErrInvalidTransition, "subscription is not deleted")
+		}
+
+		if err := uc.repo.Restore(ctx, id); err != nil {
+			return errors.Wrap(err, "repo restore")
+		}
+
+		sub, err = uc.repo.GetByID(ctx, id, false)
+		if err != nil {
+			return errors.Wrap(err, "repo getById")
+		}
+
+		return uc.recordAudit(ctx, id, entities.AuditActionRestore, deleted, sub)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "SubscriptionUsecase.Restore")
+	}
+	refreshStatus(sub, time.Now().UTC())
+
+	return sub, nil
+}
+
+// Purge - Hard deletes subscriptions soft-deleted longer than retention ago, returns their number
+func (uc *SubscriptionUsecase) Purge(ctx context.Context, retention time.Duration) (int64, error) {
+	purged, err := uc.repo.Purge(ctx, time.Now().UTC().Add(-retention))
+	if err != nil {
+		return 0, errors.Wrap(err, "SubscriptionUsecase.Purge: repo exec")
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,122 @@
+package subscription
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscription_Restore_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	deleted := subTest
+	deleted.Status = entities.SubscriptionStatusActive
+	deleted.DeletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	restored := subTest
+	restored.Status = entities.SubscriptionStatusActive
+
+	expectTransaction(mockTx, ctx)
+	gomock.InOrder(
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, true).Return(&deleted, nil),
+		mockSubRepo.EXPECT().Restore(ctx, subTest.ID).Return(nil),
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&restored, nil),
+		mockAudit.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, entry entities.AuditEntry) error {
+				assert.Equal(t, entities.AuditActionRestore, entry.Action)
+				assert.Contains(t, string(entry.Diff), "deleted_at")
+				return nil
+			}),
+	)
+
+	sub, err := us.Restore(ctx, subTest.ID)
+
+	require.NoError(t, err)
+	require.False(t, sub.DeletedAt.Valid)
+}
+
+func TestSubscription_Restore_ErrorNotDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	expectTransaction(mockTx, ctx)
+	mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, true).Return(&subTest, nil)
+
+	sub, err := us.Restore(ctx, subTest.ID)
+
+	require.Nil(t, sub)
+	require.ErrorIs(t, err, errors.ErrInvalidTransition)
+}
+
+func TestSubscription_Purge_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	retention := 30 * 24 * time.Hour
+	mockSubRepo.EXPECT().
+		Purge(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, before time.Time) (int64, error) {
+			assert.WithinDuration(t, time.Now().UTC().Add(-retention), before, time.Minute)
+			return 2, nil
+		})
+
+	purged, err := us.Purge(ctx, retention)
+
+	require.NoError(t, err)
+	require.Equal(t, int64(2), purged)
+}
+
+func TestSubscription_Purge_ErrorRepo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	mockSubRepo.EXPECT().Purge(ctx, gomock.Any()).Return(int64(0), errors.New("error repo"))
+
+	purged, err := us.Purge(ctx, time.Hour)
+
+	require.Error(t, err)
+	require.Zero(t, purged)
+	assert.Contains(t, err.Error(), "SubscriptionUsecase.Purge: repo exec")
+}
@@ -0,0 +1,136 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+)
+
+// statusTransitions - statuses reachable from the current status.
+// Cancelled and expired subscriptions are final.
+// Trial is not paused: its months are not billed anyway and it turns active by trial_end_date,
+// resuming would skip the rest of the trial, so the trial can only be cancelled.
+var statusTransitions = map[entities.SubscriptionStatusType][]entities.SubscriptionStatusType{
+	entities.SubscriptionStatusActive: {entities.SubscriptionStatusPaused, entities.SubscriptionStatusCancelled},
+	entities.SubscriptionStatusPaused: {entities.SubscriptionStatusActive, entities.SubscriptionStatusCancelled},
+	entities.SubscriptionStatusTrial:  {entities.SubscriptionStatusCancelled},
+}
+
+// Pause - Pauses active subscription, paused months are excluded from spend
+func (uc *SubscriptionUsecase) Pause(ctx context.Context, id int64) (*entities.Subscription, error) {
+	sub, err := uc.changeStatus(ctx, id, entities.SubscriptionStatusPaused)
+	if err != nil {
+		return nil, errors.Wrap(err, "SubscriptionUsecase.Pause")
+	}
+
+	return sub, nil
+}
+
+// Resume - Resumes paused subscription
+func (uc *SubscriptionUsecase) Resume(ctx context.Context, id int64) (*entities.Subscription, error) {
+	sub, err := uc.changeStatus(ctx, id, entities.SubscriptionStatusActive)
+	if err != nil {
+		return nil, errors.Wrap(err, "SubscriptionUsecase.Resume")
+	}
+
+	return sub, nil
+}
+
+// Cancel - Cancels subscription, it is billed up to the current month
+func (uc *SubscriptionUsecase) Cancel(ctx context.Context, id int64) (*entities.Subscription, error) {
+	sub, err := uc.changeStatus(ctx, id, entities.SubscriptionStatusCancelled)
+	if err != nil {
+		return nil, errors.Wrap(err, "SubscriptionUsecase.Cancel")
+	}
+
+	return sub, nil
+}
+
+// StatusHistory - Returns status periods of the subscription
+func (uc *SubscriptionUsecase) StatusHistory(ctx context.Context, id int64) ([]entities.SubscriptionStatusChange, error) {
+	_, err := uc.repo.GetByID(ctx, id, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "SubscriptionUsecase.StatusHistory: repo getById")
+	}
+
+	history, err := uc.repo.GetStatusHistory(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "SubscriptionUsecase.StatusHistory: repo exec")
+	}
+
+	return history, nil
+}
+
+// changeStatus - validates the transition and stores the new status in one transaction
+func (uc *SubscriptionUsecase) changeStatus(ctx context.Context, id int64, status entities.SubscriptionStatusType) (*entities.Subscription, error) {
+	now := time.Now().UTC()
+
+	var sub *entities.Subscription
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, err := uc.repo.GetByID(ctx, id, false)
+		if err != nil {
+			return errors.Wrap(err, "repo getById")
+		}
+		refreshStatus(current, now)
+
+		if !slices.Contains(statusTransitions[current.Status], status) {
+			return errors.Wrap(errors.ErrInvalidTransition, fmt.Sprintf("%s to %s", current.Status, status))
+		}
+
+		if err := uc.repo.ChangeStatus(ctx, id, status, now); err != nil {
+			return errors.Wrap(err, "repo change status")
+		}
+
+		if status == entities.SubscriptionStatusCancelled {
+			endDate := cancelEndDate(current, now)
+			if !current.EndDate.Valid || current.EndDate.Time.After(endDate) {
+				if err := uc.repo.Update(ctx, id, map[string]any{"end_date": endDate}, 0); err != nil {
+					return errors.Wrap(err, "repo update end date")
+				}
+			}
+		}
+
+		sub, err = uc.repo.GetByID(ctx, id, false)
+		if err != nil {
+			return errors.Wrap(err, "repo getById")
+		}
+
+		return uc.recordAudit(ctx, id, entities.AuditActionUpdate, current, sub)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+// cancelEndDate - the cancelled subscription is billed up to the current month,
+// a subscription which has not started yet ends in its first month
+func cancelEndDate(sub *entities.Subscription, now time.Time) time.Time {
+	endDate := monthStart(now)
+	if start := monthStart(sub.StartDate); start.After(endDate) {
+		return start
+	}
+
+	return endDate
+}
+
+// refreshStatus - converts the subscription to active once the trial has ended
+// and marks it expired once its last billed month has passed
+func refreshStatus(sub *entities.Subscription, now time.Time) {
+	if sub.Status == entities.SubscriptionStatusTrial && sub.TrialEndDate.Valid && !sub.TrialEndDate.Time.After(now) {
+		sub.Status = entities.SubscriptionStatusActive
+	}
+
+	if sub.Status == entities.SubscriptionStatusCancelled || !sub.EndDate.Valid {
+		return
+	}
+
+	if sub.EndDate.Time.Before(monthStart(now)) {
+		sub.Status = entities.SubscriptionStatusExpired
+	}
+}
@@ -0,0 +1,336 @@
+package subscription
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expectTransaction - runs the transaction function with the same context
+func expectTransaction(mockTx *mocks.MockTransactor, ctx context.Context) {
+	mockTx.EXPECT().
+		WithinTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+}
+
+func TestSubscription_Pause_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	active := subTest
+	active.Status = entities.SubscriptionStatusActive
+	paused := subTest
+	paused.Status = entities.SubscriptionStatusPaused
+
+	expectTransaction(mockTx, ctx)
+	gomock.InOrder(
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&active, nil),
+		mockSubRepo.EXPECT().ChangeStatus(ctx, subTest.ID, entities.SubscriptionStatusPaused, gomock.Any()).Return(nil),
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&paused, nil),
+		mockAudit.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+	)
+
+	sub, err := us.Pause(ctx, subTest.ID)
+
+	require.NoError(t, err)
+	require.Equal(t, entities.SubscriptionStatusPaused, sub.Status)
+}
+
+func TestSubscription_Resume_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	paused := subTest
+	paused.Status = entities.SubscriptionStatusPaused
+	active := subTest
+	active.Status = entities.SubscriptionStatusActive
+
+	expectTransaction(mockTx, ctx)
+	gomock.InOrder(
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&paused, nil),
+		mockSubRepo.EXPECT().ChangeStatus(ctx, subTest.ID, entities.SubscriptionStatusActive, gomock.Any()).Return(nil),
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&active, nil),
+		mockAudit.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+	)
+
+	sub, err := us.Resume(ctx, subTest.ID)
+
+	require.NoError(t, err)
+	require.Equal(t, entities.SubscriptionStatusActive, sub.Status)
+}
+
+func TestSubscription_Cancel_SetsEndDate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	active := subTest
+	active.Status = entities.SubscriptionStatusActive
+	cancelled := subTest
+	cancelled.Status = entities.SubscriptionStatusCancelled
+
+	expectTransaction(mockTx, ctx)
+	gomock.InOrder(
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&active, nil),
+		mockSubRepo.EXPECT().ChangeStatus(ctx, subTest.ID, entities.SubscriptionStatusCancelled, gomock.Any()).Return(nil),
+		mockSubRepo.EXPECT().Update(ctx, subTest.ID, map[string]any{"end_date": monthStart(time.Now().UTC())}, int64(0)).Return(nil),
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&cancelled, nil),
+		mockAudit.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+	)
+
+	sub, err := us.Cancel(ctx, subTest.ID)
+
+	require.NoError(t, err)
+	require.Equal(t, entities.SubscriptionStatusCancelled, sub.Status)
+}
+
+func TestSubscription_Cancel_KeepsEndDate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	paused := subTest
+	paused.Status = entities.SubscriptionStatusPaused
+	paused.EndDate = sql.NullTime{Time: monthStart(time.Now().UTC()), Valid: true}
+
+	expectTransaction(mockTx, ctx)
+	gomock.InOrder(
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&paused, nil),
+		mockSubRepo.EXPECT().ChangeStatus(ctx, subTest.ID, entities.SubscriptionStatusCancelled, gomock.Any()).Return(nil),
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&paused, nil),
+		mockAudit.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+	)
+
+	_, err := us.Cancel(ctx, subTest.ID)
+
+	require.NoError(t, err)
+}
+
+func TestSubscription_Cancel_FutureStart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	start := monthStart(time.Now().UTC()).AddDate(0, 3, 0)
+	active := subTest
+	active.Status = entities.SubscriptionStatusActive
+	active.StartDate = start
+	cancelled := active
+	cancelled.Status = entities.SubscriptionStatusCancelled
+	cancelled.EndDate = sql.NullTime{Time: start, Valid: true}
+
+	expectTransaction(mockTx, ctx)
+	gomock.InOrder(
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&active, nil),
+		mockSubRepo.EXPECT().ChangeStatus(ctx, subTest.ID, entities.SubscriptionStatusCancelled, gomock.Any()).Return(nil),
+		mockSubRepo.EXPECT().Update(ctx, subTest.ID, map[string]any{"end_date": start}, int64(0)).Return(nil),
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&cancelled, nil),
+		mockAudit.EXPECT().Create(ctx, gomock.Any()).Return(nil),
+	)
+
+	sub, err := us.Cancel(ctx, subTest.ID)
+
+	require.NoError(t, err)
+	require.False(t, sub.EndDate.Time.Before(sub.StartDate))
+}
+
+func TestSubscription_ChangeStatus_InvalidTransition(t *testing.T) {
+	tests := []struct {
+		name       string
+		current    entities.Subscription
+		transition func(us *SubscriptionUsecase, ctx context.Context, id int64) (*entities.Subscription, error)
+	}{
+		{
+			name:       "resumeActive",
+			current:    entities.Subscription{ID: 1, Status: entities.SubscriptionStatusActive},
+			transition: (*SubscriptionUsecase).Resume,
+		},
+		{
+			name:       "pauseTrial",
+			current:    entities.Subscription{ID: 1, Status: entities.SubscriptionStatusTrial},
+			transition: (*SubscriptionUsecase).Pause,
+		},
+		{
+			name:       "pauseCancelled",
+			current:    entities.Subscription{ID: 1, Status: entities.SubscriptionStatusCancelled},
+			transition: (*SubscriptionUsecase).Pause,
+		},
+		{
+			name: "cancelExpired",
+			current: entities.Subscription{
+				ID:      1,
+				Status:  entities.SubscriptionStatusActive,
+				EndDate: sql.NullTime{Time: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+			},
+			transition: (*SubscriptionUsecase).Cancel,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+			mockAudit := mocks.NewMockAuditRepository(ctrl)
+			mockTx := mocks.NewMockTransactor(ctrl)
+			mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+			mockLogger := mocks.NewMockLogger(ctrl)
+			us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+			ctx := context.Background()
+
+			current := tt.current
+			expectTransaction(mockTx, ctx)
+			mockSubRepo.EXPECT().GetByID(ctx, current.ID, false).Return(&current, nil)
+
+			sub, err := tt.transition(&us, ctx, current.ID)
+
+			require.Nil(t, sub)
+			require.ErrorIs(t, err, errors.ErrInvalidTransition)
+		})
+	}
+}
+
+func TestSubscription_ChangeStatus_ErrorRepo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	active := subTest
+	active.Status = entities.SubscriptionStatusActive
+
+	expectTransaction(mockTx, ctx)
+	mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&active, nil)
+	mockSubRepo.EXPECT().
+		ChangeStatus(ctx, subTest.ID, entities.SubscriptionStatusPaused, gomock.Any()).
+		Return(errors.New("error repo"))
+
+	sub, err := us.Pause(ctx, subTest.ID)
+
+	require.Error(t, err)
+	require.Nil(t, sub)
+	assert.Contains(t, err.Error(), "SubscriptionUsecase.Pause: repo change status")
+}
+
+func TestSubscription_StatusHistory_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	history := []entities.SubscriptionStatusChange{
+		{ID: 1, SubscriptionID: subTest.ID, Status: entities.SubscriptionStatusPaused, StartedAt: time.Now()},
+	}
+
+	mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&subTest, nil)
+	mockSubRepo.EXPECT().GetStatusHistory(ctx, subTest.ID).Return(history, nil)
+
+	res, err := us.StatusHistory(ctx, subTest.ID)
+
+	require.NoError(t, err)
+	require.Equal(t, history, res)
+}
+
+func TestSubscription_StatusHistory_ErrorNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(nil, errors.ErrNotFound)
+
+	res, err := us.StatusHistory(ctx, subTest.ID)
+
+	require.Nil(t, res)
+	require.ErrorIs(t, err, errors.ErrNotFound)
+}
+
+func TestSubscription_GetByID_Expired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	ended := subTest
+	ended.Status = entities.SubscriptionStatusActive
+	ended.EndDate = sql.NullTime{Time: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+
+	mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&ended, nil)
+
+	sub, err := us.GetByID(ctx, subTest.ID, false)
+
+	require.NoError(t, err)
+	require.Equal(t, entities.SubscriptionStatusExpired, sub.Status)
+}
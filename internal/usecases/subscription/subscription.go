@@ -2,88 +2,281 @@ package subscription
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/mathbdw/subscription-service/internal/domain/entities"
 	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/exchangerate"
 	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
 	"github.com/mathbdw/subscription-service/internal/interfaces/repositories"
 )
 
 type SubscriptionUsecase struct {
-	repo   repositories.SubscriptionRepository
-	logger observability.Logger
+	repo      repositories.SubscriptionRepository
+	auditRepo repositories.AuditRepository
+	tx        repositories.Transactor
+	rates     exchangerate.ExchangeRateProvider
+	logger    observability.Logger
 }
 
 // NewSubscriptionUsecase - Constructor SubscriptionUsecase
-func NewSubscriptionUsecase(repo repositories.SubscriptionRepository, logger observability.Logger) SubscriptionUsecase {
-	return SubscriptionUsecase{repo: repo, logger: logger}
+func NewSubscriptionUsecase(repo repositories.SubscriptionRepository, auditRepo repositories.AuditRepository, tx repositories.Transactor, rates exchangerate.ExchangeRateProvider, logger observability.Logger) SubscriptionUsecase {
+	return SubscriptionUsecase{repo: repo, auditRepo: auditRepo, tx: tx, rates: rates, logger: logger}
 }
 
-// Create - Adds new subscription
-func (uc *SubscriptionUsecase) Create(ctx context.Context, sub entities.Subscription) error {
-	err := uc.repo.Create(ctx, sub)
+// Create - Adds new subscription, it starts in trial while the trial has not ended.
+// The change is recorded in the audit log within the same transaction.
+func (uc *SubscriptionUsecase) Create(ctx context.Context, sub entities.Subscription) (*entities.Subscription, error) {
+	now := time.Now().UTC()
+	if sub.TrialEndDate.Valid && sub.TrialEndDate.Time.After(now) {
+		sub.Status = entities.SubscriptionStatusTrial
+	}
+
+	var created *entities.Subscription
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = uc.repo.Create(ctx, sub)
+		if err != nil {
+			return errors.Wrap(err, "SubscriptionUsecase.Create: repo exec")
+		}
+
+		if len(sub.Tags) > 0 {
+			if err := uc.repo.SetTags(ctx, created.ID, sub.Tags); err != nil {
+				return errors.Wrap(err, "SubscriptionUsecase.Create: repo set tags")
+			}
+			created.Tags = entities.NormalizeTags(sub.Tags)
+		}
+
+		if err := uc.recordAudit(ctx, created.ID, entities.AuditActionCreate, nil, created); err != nil {
+			return errors.Wrap(err, "SubscriptionUsecase.Create")
+		}
+
+		return nil
+	})
 	if err != nil {
-		return errors.Wrap(err, "SubscriptionUsecase.Create: repo exec")
+		return nil, err
 	}
+	refreshStatus(created, now)
 
-	return nil
+	return created, nil
 }
 
-// GetByID - Returns subscription by ID
-func (uc *SubscriptionUsecase) GetByID(ctx context.Context, id int64) (*entities.Subscription, error) {
-	sub, err := uc.repo.GetByID(ctx, id)
+// GetByID - Returns subscription by ID, soft-deleted subscription is returned only with includeDeleted
+func (uc *SubscriptionUsecase) GetByID(ctx context.Context, id int64, includeDeleted bool) (*entities.Subscription, error) {
+	sub, err := uc.repo.GetByID(ctx, id, includeDeleted)
 	if err != nil {
 		return nil, errors.Wrap(err, "SubscriptionUsecase.GetByID: repo exec")
 	}
+	refreshStatus(sub, time.Now().UTC())
 
 	return sub, nil
 }
 
-// List - Returns slice subscriptions by Query Criteria
+// List - Returns slice subscriptions by Query Criteria.
+// Prices are converted into params.Currency when it is set.
 func (uc *SubscriptionUsecase) List(ctx context.Context, params entities.QueryCriteria) (*entities.ResponseListSubscription, error) {
 	resp, err := uc.repo.List(ctx, params)
 	if err != nil {
 		return nil, errors.Wrap(err, "SubscriptionUsecase.List: repo exec")
 	}
 
+	now := time.Now().UTC()
+	for i := range resp.Data {
+		refreshStatus(&resp.Data[i], now)
+	}
+
+	if params.Currency == "" {
+		return resp, nil
+	}
+
+	for i := range resp.Data {
+		converted, err := uc.convert(ctx, int64(resp.Data[i].Price), resp.Data[i].Currency, params.Currency)
+		if err != nil {
+			return nil, errors.Wrap(err, "SubscriptionUsecase.List: convert")
+		}
+		resp.Data[i].ConvertedPrice = converted
+	}
+
 	return resp, nil
 }
 
-// Update - Updated fields of subscription by ID
-func (uc *SubscriptionUsecase) Update(ctx context.Context, id int64, fields map[string]any) error {
-	_, err := uc.repo.GetByID(ctx, id)
+// Update - Updated fields of subscription by ID, price changes are kept in the price history.
+// When version is set the subscription is updated only if it was not changed since, otherwise ErrConflict is returned.
+func (uc *SubscriptionUsecase) Update(ctx context.Context, id int64, fields map[string]any, version int64) error {
+	return uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		sub, err := uc.repo.GetByID(ctx, id, false)
+		if err != nil {
+			return errors.Wrap(err, "SubscriptionUsecase.Update: repo getById")
+		}
+
+		if err := checkVersion(sub, version); err != nil {
+			return errors.Wrap(err, "SubscriptionUsecase.Update")
+		}
+
+		if err := uc.recordPriceChange(ctx, *sub, fields); err != nil {
+			return errors.Wrap(err, "SubscriptionUsecase.Update: price history")
+		}
+
+		err = uc.repo.Update(ctx, id, fields, version)
+		if err != nil {
+			return errors.Wrap(err, "SubscriptionUsecase.Update: repo exec")
+		}
+
+		updated, err := uc.repo.GetByID(ctx, id, false)
+		if err != nil {
+			return errors.Wrap(err, "SubscriptionUsecase.Update: repo getById")
+		}
+
+		if err := uc.recordAudit(ctx, id, entities.AuditActionUpdate, sub, updated); err != nil {
+			return errors.Wrap(err, "SubscriptionUsecase.Update")
+		}
+
+		return nil
+	})
+}
+
+// Delete - Soft deletes subscription by ID, it can be restored until purge.
+// When version is set the subscription is deleted only if it was not changed since, otherwise ErrConflict is returned.
+func (uc *SubscriptionUsecase) Delete(ctx context.Context, id int64, version int64) error {
+	return uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		sub, err := uc.repo.GetByID(ctx, id, false)
+		if err != nil {
+			return errors.Wrap(err, "SubscriptionUsecase.Delete: repo getById")
+		}
+
+		if err := checkVersion(sub, version); err != nil {
+			return errors.Wrap(err, "SubscriptionUsecase.Delete")
+		}
+
+		err = uc.repo.Delete(ctx, id, version)
+		if err != nil {
+			return errors.Wrap(err, "SubscriptionUsecase.Delete: repo exec")
+		}
+
+		if err := uc.recordAudit(ctx, id, entities.AuditActionDelete, sub, nil); err != nil {
+			return errors.Wrap(err, "SubscriptionUsecase.Delete")
+		}
+
+		return nil
+	})
+}
+
+// GetCost - Returns total cost of subscriptions by FilterParams per currency with breakdown by subscription.
+// Costs are converted into currency when it is set.
+func (uc *SubscriptionUsecase) GetCost(ctx context.Context, params entities.FilterParams, currency string) (*entities.Cost, error) {
+	items, err := uc.repo.GetCost(ctx, params)
 	if err != nil {
-		return errors.Wrap(err, "SubscriptionUsecase.Update: repo getById")
+		return nil, errors.Wrap(err, "SubscriptionUsecase.GetCost: repo exec")
 	}
 
-	err = uc.repo.Update(ctx, id, fields)
-	if err != nil {
-		return errors.Wrap(err, "SubscriptionUsecase.Update: repo exec")
+	cost := &entities.Cost{Totals: []entities.CostTotal{}, Items: items}
+	for _, item := range items {
+		cost.Totals = addTotal(cost.Totals, item.Currency, item.Cost)
 	}
 
-	return nil
+	if currency == "" {
+		return cost, nil
+	}
+
+	cost.TotalConverted = &entities.Converted{Currency: currency}
+	for i := range cost.Items {
+		converted, err := uc.convert(ctx, cost.Items[i].Cost, cost.Items[i].Currency, currency)
+		if err != nil {
+			return nil, errors.Wrap(err, "SubscriptionUsecase.GetCost: convert")
+		}
+		cost.Items[i].ConvertedCost = converted
+		cost.TotalConverted.Amount += converted.Amount
+	}
+	cost.TotalConverted.Amount = roundAmount(cost.TotalConverted.Amount)
+
+	return cost, nil
 }
 
-// Delete - Deleted subscription by ID
-func (uc *SubscriptionUsecase) Delete(ctx context.Context, id int64) error {
-	_, err := uc.repo.GetByID(ctx, id)
+// GetCostMonthly - Returns spend for every calendar month of the period by FilterParams per currency.
+// Months without billed subscriptions are returned without totals.
+func (uc *SubscriptionUsecase) GetCostMonthly(ctx context.Context, params entities.FilterParams) ([]entities.CostMonth, error) {
+	services, err := uc.repo.GetCostMonthly(ctx, params)
 	if err != nil {
-		return errors.Wrap(err, "SubscriptionUsecase.Delete: repo getById")
+		return nil, errors.Wrap(err, "SubscriptionUsecase.GetCostMonthly: repo exec")
 	}
 
-	err = uc.repo.Delete(ctx, id)
-	if err != nil {
-		return errors.Wrap(err, "SubscriptionUsecase.Delete: repo exec")
+	var from, to time.Time
+	switch {
+	case params.Period.From != nil:
+		from = monthStart(*params.Period.From)
+	case len(services) > 0:
+		from = monthStart(services[0].Month)
+	default:
+		return []entities.CostMonth{}, nil
 	}
 
-	return nil
+	if params.Period.To != nil {
+		to = monthStart(*params.Period.To)
+	} else {
+		to = monthStart(time.Now().UTC())
+	}
+
+	months := make([]entities.CostMonth, 0)
+	index := make(map[time.Time]int)
+	for month := from; !month.After(to); month = month.AddDate(0, 1, 0) {
+		index[month] = len(months)
+		months = append(months, entities.CostMonth{Month: month, Totals: []entities.CostTotal{}, Services: []entities.CostMonthService{}})
+	}
+
+	for _, service := range services {
+		i, ok := index[monthStart(service.Month)]
+		if !ok {
+			continue
+		}
+
+		months[i].Subscriptions += service.Subscriptions
+		months[i].Totals = addTotal(months[i].Totals, service.Currency, service.Cost)
+		months[i].Services = append(months[i].Services, service)
+	}
+
+	return months, nil
 }
 
-// GetCost - Returns total cost of subscriptions by FilterParams
-func (uc *SubscriptionUsecase) GetCost(ctx context.Context, params entities.FilterParams) (int64, error) {
-	cost, err := uc.repo.GetCost(ctx, params)
+// GetCostGrouped - Returns cost of subscriptions by FilterParams grouped by service, user or both, every group is split by currency
+func (uc *SubscriptionUsecase) GetCostGrouped(ctx context.Context, params entities.FilterParams, groupBy []entities.CostGroupType) ([]entities.CostGroup, error) {
+	if len(groupBy) == 0 {
+		return nil, errors.Wrap(errors.ErrInvalidInput, "SubscriptionUsecase.GetCostGrouped: empty group by")
+	}
+
+	groups, err := uc.repo.GetCostGrouped(ctx, params, groupBy)
 	if err != nil {
-		return 0, errors.Wrap(err, "SubscriptionUsecase.GetCost: repo exec")
+		return nil, errors.Wrap(err, "SubscriptionUsecase.GetCostGrouped: repo exec")
 	}
 
-	return cost, nil
+	return groups, nil
+}
+
+// addTotal - adds cost to the total of its currency, totals are kept sorted by currency
+func addTotal(totals []entities.CostTotal, currency string, cost int64) []entities.CostTotal {
+	i, found := slices.BinarySearchFunc(totals, currency, func(total entities.CostTotal, currency string) int {
+		return strings.Compare(total.Currency, currency)
+	})
+	if !found {
+		totals = slices.Insert(totals, i, entities.CostTotal{Currency: currency})
+	}
+	totals[i].Cost += cost
+
+	return totals
+}
+
+// monthStart - returns the first day of the month in UTC
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// checkVersion - the subscription must be in the version the client has seen, zero version skips the check
+func checkVersion(sub *entities.Subscription, version int64) error {
+	if version != 0 && sub.Version != version {
+		return errors.Wrap(errors.ErrConflict, fmt.Sprintf("version %d, current %d", version, sub.Version))
+	}
+
+	return nil
 }
@@ -26,15 +26,15 @@ var (
 
 	updateFields = map[string]any{
 		"service_name": subTest.ServiceName,
-		"user_id": subTest.UserId,
-		"price": subTest.Price,
-		"start_date": subTest.StartDate,
+		"user_id":      subTest.UserId,
+		"price":        subTest.Price,
+		"start_date":   subTest.StartDate,
 	}
 
 	filterCost = entities.FilterParams{
 		ServiceName: subTest.ServiceName,
-		UserId: subTest.UserId,
-		StartDate: entities.DateRange{From: &subTest.StartDate},
+		UserId:      subTest.UserId,
+		Period:      entities.DateRange{From: &subTest.StartDate},
 	}
 )
 
@@ -43,15 +43,19 @@ func TestSubscription_Create_ErrorRepo(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
 	mockLogger := mocks.NewMockLogger(ctrl)
-	us := NewSubscriptionUsecase(mockSubRepo, mockLogger)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
 	ctx := context.Background()
 
+	expectTransaction(mockTx, ctx)
 	mockSubRepo.EXPECT().
 		Create(ctx, subTest).
-		Return(errors.New("error repo"))
+		Return(nil, errors.New("error repo"))
 
-	err := us.Create(ctx, subTest)
+	_, err := us.Create(ctx, subTest)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "SubscriptionUsecase.Create: repo exec")
@@ -62,17 +66,59 @@ func TestSubscription_Create_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
 	mockLogger := mocks.NewMockLogger(ctrl)
-	us := NewSubscriptionUsecase(mockSubRepo, mockLogger)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
 	ctx := context.Background()
 
-	mockSubRepo.EXPECT().
-		Create(ctx, subTest).
-		Return(nil)
-
-	err := us.Create(ctx, subTest)
+	created := subTest
+	created.CreatedAt = time.Now().UTC()
+	created.UpdatedAt = created.CreatedAt
+
+	expectTransaction(mockTx, ctx)
+	gomock.InOrder(
+		mockSubRepo.EXPECT().Create(ctx, subTest).Return(&created, nil),
+		mockAudit.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, entry entities.AuditEntry) error {
+				assert.Equal(t, entities.AuditActionCreate, entry.Action)
+				assert.Equal(t, subTest.ID, entry.EntityID)
+				assert.Nil(t, entry.Before)
+				assert.NotNil(t, entry.After)
+				return nil
+			}),
+	)
+
+	sub, err := us.Create(ctx, subTest)
 
 	require.NoError(t, err)
+	assert.Equal(t, subTest.ID, sub.ID)
+	assert.Equal(t, created.CreatedAt, sub.CreatedAt)
+}
+
+func TestSubscription_Create_ErrorAudit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	expectTransaction(mockTx, ctx)
+	created := subTest
+	mockSubRepo.EXPECT().Create(ctx, subTest).Return(&created, nil)
+	mockAudit.EXPECT().Create(ctx, gomock.Any()).Return(errors.New("error repo"))
+
+	_, err := us.Create(ctx, subTest)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SubscriptionUsecase.Create: repo audit")
 }
 
 func TestSubscription_GetByID_ErrorRepo(t *testing.T) {
@@ -80,15 +126,18 @@ func TestSubscription_GetByID_ErrorRepo(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
 	mockLogger := mocks.NewMockLogger(ctrl)
-	us := NewSubscriptionUsecase(mockSubRepo, mockLogger)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
 	ctx := context.Background()
 
 	mockSubRepo.EXPECT().
-		GetByID(ctx, subTest.ID).
+		GetByID(ctx, subTest.ID, false).
 		Return(nil, errors.New("error repo"))
 
-	sub, err := us.GetByID(ctx, subTest.ID)
+	sub, err := us.GetByID(ctx, subTest.ID, false)
 
 	require.Error(t, err)
 	require.Nil(t, sub)
@@ -100,15 +149,18 @@ func TestSubscription_GetByID_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
 	mockLogger := mocks.NewMockLogger(ctrl)
-	us := NewSubscriptionUsecase(mockSubRepo, mockLogger)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
 	ctx := context.Background()
 
 	mockSubRepo.EXPECT().
-		GetByID(ctx, subTest.ID).
+		GetByID(ctx, subTest.ID, false).
 		Return(&subTest, nil)
 
-	sub, err := us.GetByID(ctx, subTest.ID)
+	sub, err := us.GetByID(ctx, subTest.ID, false)
 
 	require.NoError(t, err)
 	require.Equal(t, subTest.ID, sub.ID)
@@ -120,8 +172,11 @@ func TestSubscription_List_ErrorRepo(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
 	mockLogger := mocks.NewMockLogger(ctrl)
-	us := NewSubscriptionUsecase(mockSubRepo, mockLogger)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
 	ctx := context.Background()
 
 	params := entities.QueryCriteria{}
@@ -142,8 +197,11 @@ func TestSubscription_List_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
 	mockLogger := mocks.NewMockLogger(ctrl)
-	us := NewSubscriptionUsecase(mockSubRepo, mockLogger)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
 	ctx := context.Background()
 
 	params := entities.QueryCriteria{}
@@ -159,47 +217,159 @@ func TestSubscription_List_Success(t *testing.T) {
 	require.Equal(t, respData, res)
 }
 
+func TestSubscription_List_Converted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	params := entities.QueryCriteria{Currency: "RUB"}
+	respData := &entities.ResponseListSubscription{
+		Data: []entities.Subscription{{ID: 1, Price: 10, Currency: "USD"}},
+	}
+
+	mockSubRepo.EXPECT().
+		List(ctx, gomock.Any()).
+		Return(respData, nil)
+	mockRates.EXPECT().
+		Rate(ctx, "USD", "RUB").
+		Return(90.5, nil)
+
+	res, err := us.List(ctx, params)
+
+	require.NoError(t, err)
+	require.Equal(t, &entities.Converted{Amount: 905, Currency: "RUB", Rate: 90.5}, res.Data[0].ConvertedPrice)
+}
+
+func TestSubscription_List_ErrorRate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	params := entities.QueryCriteria{Currency: "XXX"}
+	respData := &entities.ResponseListSubscription{
+		Data: []entities.Subscription{{ID: 1, Price: 10, Currency: "USD"}},
+	}
+
+	mockSubRepo.EXPECT().
+		List(ctx, gomock.Any()).
+		Return(respData, nil)
+	mockRates.EXPECT().
+		Rate(ctx, "USD", "XXX").
+		Return(float64(0), errors.ErrInvalidInput)
+
+	res, err := us.List(ctx, params)
+
+	require.Error(t, err)
+	require.Nil(t, res)
+	require.ErrorIs(t, err, errors.ErrInvalidInput)
+	assert.Contains(t, err.Error(), "SubscriptionUsecase.List: convert")
+}
+
 func TestSubscription_Update_ErrorRepo(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
 	mockLogger := mocks.NewMockLogger(ctrl)
-	us := NewSubscriptionUsecase(mockSubRepo, mockLogger)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
 	ctx := context.Background()
 
+	expectTransaction(mockTx, ctx)
+
 	mockSubRepo.EXPECT().
-		GetByID(ctx, subTest.ID).
+		GetByID(ctx, subTest.ID, false).
 		Return(&subTest, nil)
-		
+
 	mockSubRepo.EXPECT().
-		Update(ctx, subTest.ID, updateFields).
+		Update(ctx, subTest.ID, updateFields, int64(0)).
 		Return(errors.New("error repo"))
 
-	err := us.Update(ctx, subTest.ID, updateFields)
+	err := us.Update(ctx, subTest.ID, updateFields, 0)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "SubscriptionUsecase.Update: repo exec")
 }
 
+func TestSubscription_Update_ErrorVersionChanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	expectTransaction(mockTx, ctx)
+
+	current := subTest
+	current.Version = 3
+	mockSubRepo.EXPECT().
+		GetByID(ctx, subTest.ID, false).
+		Return(&current, nil)
+
+	err := us.Update(ctx, subTest.ID, updateFields, 2)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrConflict)
+}
+
 func TestSubscription_Update_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
 	mockLogger := mocks.NewMockLogger(ctrl)
-	us := NewSubscriptionUsecase(mockSubRepo, mockLogger)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
 	ctx := context.Background()
 
+	expectTransaction(mockTx, ctx)
+
 	mockSubRepo.EXPECT().
-		GetByID(ctx, subTest.ID).
+		GetByID(ctx, subTest.ID, false).
 		Return(&subTest, nil)
 
 	mockSubRepo.EXPECT().
-		Update(ctx, subTest.ID, updateFields).
+		Update(ctx, subTest.ID, updateFields, int64(0)).
 		Return(nil)
 
-	err := us.Update(ctx, subTest.ID, updateFields)
+	updated := subTest
+	updated.ServiceName = "Updated service"
+	mockSubRepo.EXPECT().
+		GetByID(ctx, subTest.ID, false).
+		Return(&updated, nil)
+
+	mockAudit.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, entry entities.AuditEntry) error {
+			assert.Equal(t, entities.AuditActionUpdate, entry.Action)
+			assert.JSONEq(t, `{"service_name":{"before":"Test service","after":"Updated service"}}`, string(entry.Diff))
+			return nil
+		})
+
+	err := us.Update(ctx, subTest.ID, updateFields, 0)
 
 	require.NoError(t, err)
 }
@@ -209,42 +379,87 @@ func TestSubscription_Delete_ErrorRepo(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
 	mockLogger := mocks.NewMockLogger(ctrl)
-	us := NewSubscriptionUsecase(mockSubRepo, mockLogger)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
 	ctx := context.Background()
 
+	expectTransaction(mockTx, ctx)
+
 	mockSubRepo.EXPECT().
-		GetByID(ctx, subTest.ID).
+		GetByID(ctx, subTest.ID, false).
 		Return(&subTest, nil)
 
 	mockSubRepo.EXPECT().
-		Delete(ctx, subTest.ID).
+		Delete(ctx, subTest.ID, int64(0)).
 		Return(errors.New("error repo"))
 
-	err := us.Delete(ctx, subTest.ID)
+	err := us.Delete(ctx, subTest.ID, 0)
 
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "SubscriptionUsecase.Delete: repo exec")
 }
 
+func TestSubscription_Delete_ErrorVersionChanged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	expectTransaction(mockTx, ctx)
+
+	current := subTest
+	current.Version = 3
+	mockSubRepo.EXPECT().
+		GetByID(ctx, subTest.ID, false).
+		Return(&current, nil)
+
+	err := us.Delete(ctx, subTest.ID, 2)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrConflict)
+}
+
 func TestSubscription_Delete_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
 	mockLogger := mocks.NewMockLogger(ctrl)
-	us := NewSubscriptionUsecase(mockSubRepo, mockLogger)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
 	ctx := context.Background()
 
+	expectTransaction(mockTx, ctx)
+
 	mockSubRepo.EXPECT().
-		GetByID(ctx, subTest.ID).
+		GetByID(ctx, subTest.ID, false).
 		Return(&subTest, nil)
 
 	mockSubRepo.EXPECT().
-		Delete(ctx, subTest.ID).
+		Delete(ctx, subTest.ID, int64(0)).
 		Return(nil)
 
-	err := us.Delete(ctx, subTest.ID)
+	mockAudit.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, entry entities.AuditEntry) error {
+			assert.Equal(t, entities.AuditActionDelete, entry.Action)
+			assert.NotNil(t, entry.Before)
+			assert.Nil(t, entry.After)
+			return nil
+		})
+
+	err := us.Delete(ctx, subTest.ID, 0)
 
 	require.NoError(t, err)
 }
@@ -254,18 +469,21 @@ func TestSubscription_GetCost_ErrorRepo(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
 	mockLogger := mocks.NewMockLogger(ctrl)
-	us := NewSubscriptionUsecase(mockSubRepo, mockLogger)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
 	ctx := context.Background()
 
 	mockSubRepo.EXPECT().
 		GetCost(ctx, filterCost).
-		Return(int64(0), errors.New("error repo"))
+		Return(nil, errors.New("error repo"))
 
-	resCost, err := us.GetCost(ctx, filterCost)
+	resCost, err := us.GetCost(ctx, filterCost, "")
 
 	require.Error(t, err)
-	require.Equal(t, int64(0), resCost)
+	require.Nil(t, resCost)
 	assert.Contains(t, err.Error(), "SubscriptionUsecase.GetCost: repo exec")
 }
 
@@ -274,16 +492,215 @@ func TestSubscription_GetCost_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	items := []entities.CostItem{
+		{SubscriptionID: 1, Price: 100, Currency: "RUB", Months: 12, Cost: 1200},
+		{SubscriptionID: 2, Price: 5, Currency: "USD", Months: 3, Cost: 15},
+		{SubscriptionID: 3, Price: 50, Currency: "RUB", Months: 3, Cost: 150},
+	}
+
+	mockSubRepo.EXPECT().
+		GetCost(ctx, filterCost).
+		Return(items, nil)
+
+	resCost, err := us.GetCost(ctx, filterCost, "")
+
+	require.NoError(t, err)
+	require.Equal(t, []entities.CostTotal{{Currency: "RUB", Cost: 1350}, {Currency: "USD", Cost: 15}}, resCost.Totals)
+	require.Nil(t, resCost.TotalConverted)
+	require.Equal(t, items, resCost.Items)
+}
+
+func TestSubscription_GetCost_Converted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
 	mockLogger := mocks.NewMockLogger(ctrl)
-	us := NewSubscriptionUsecase(mockSubRepo, mockLogger)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
 	ctx := context.Background()
 
+	items := []entities.CostItem{
+		{SubscriptionID: 1, Price: 100, Currency: "RUB", Months: 12, Cost: 1200},
+		{SubscriptionID: 2, Price: 5, Currency: "USD", Months: 3, Cost: 15},
+	}
+
 	mockSubRepo.EXPECT().
 		GetCost(ctx, filterCost).
-		Return(int64(124), nil)
+		Return(items, nil)
+	mockRates.EXPECT().
+		Rate(ctx, "RUB", "USD").
+		Return(0.0125, nil)
+	mockRates.EXPECT().
+		Rate(ctx, "USD", "USD").
+		Return(float64(1), nil)
+
+	resCost, err := us.GetCost(ctx, filterCost, "USD")
+
+	require.NoError(t, err)
+	require.Equal(t, &entities.Converted{Amount: 15, Currency: "USD", Rate: 0.0125}, resCost.Items[0].ConvertedCost)
+	require.Equal(t, &entities.Converted{Amount: 15, Currency: "USD", Rate: 1}, resCost.Items[1].ConvertedCost)
+	require.Equal(t, &entities.Converted{Amount: 30, Currency: "USD"}, resCost.TotalConverted)
+}
+
+func TestSubscription_GetCostMonthly_ErrorRepo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	mockSubRepo.EXPECT().
+		GetCostMonthly(ctx, filterCost).
+		Return(nil, errors.New("error repo"))
+
+	months, err := us.GetCostMonthly(ctx, filterCost)
+
+	require.Error(t, err)
+	require.Nil(t, months)
+	assert.Contains(t, err.Error(), "SubscriptionUsecase.GetCostMonthly: repo exec")
+}
+
+func TestSubscription_GetCostMonthly_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	from := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2020, time.April, 1, 0, 0, 0, 0, time.UTC)
+	march := time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)
+	filter := entities.FilterParams{Period: entities.DateRange{From: &from, To: &to}}
+
+	mockSubRepo.EXPECT().
+		GetCostMonthly(ctx, filter).
+		Return([]entities.CostMonthService{
+			{Month: from, ServiceName: "A", Currency: "RUB", Subscriptions: 2, Cost: 200},
+			{Month: from, ServiceName: "B", Currency: "RUB", Subscriptions: 1, Cost: 50},
+			{Month: from, ServiceName: "B", Currency: "EUR", Subscriptions: 1, Cost: 5},
+			{Month: march, ServiceName: "A", Currency: "RUB", Subscriptions: 1, Cost: 100},
+		}, nil)
+
+	months, err := us.GetCostMonthly(ctx, filter)
+
+	require.NoError(t, err)
+	require.Equal(t, 4, len(months))
+	assert.Equal(t, from, months[0].Month)
+	assert.Equal(t, int64(4), months[0].Subscriptions)
+	assert.Equal(t, []entities.CostTotal{{Currency: "EUR", Cost: 5}, {Currency: "RUB", Cost: 250}}, months[0].Totals)
+	assert.Equal(t, 3, len(months[0].Services))
+	assert.Empty(t, months[1].Totals)
+	assert.Empty(t, months[1].Services)
+	assert.Equal(t, march, months[2].Month)
+	assert.Equal(t, []entities.CostTotal{{Currency: "RUB", Cost: 100}}, months[2].Totals)
+	assert.Equal(t, to, months[3].Month)
+}
+
+func TestSubscription_GetCostMonthly_EmptyWithoutPeriod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	mockSubRepo.EXPECT().
+		GetCostMonthly(ctx, entities.FilterParams{}).
+		Return([]entities.CostMonthService{}, nil)
+
+	months, err := us.GetCostMonthly(ctx, entities.FilterParams{})
+
+	require.NoError(t, err)
+	require.Empty(t, months)
+}
+
+func TestSubscription_GetCostGrouped_ErrorEmptyGroupBy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	groups, err := us.GetCostGrouped(ctx, filterCost, nil)
+
+	require.Error(t, err)
+	require.Nil(t, groups)
+	assert.ErrorIs(t, err, errors.ErrInvalidInput)
+}
+
+func TestSubscription_GetCostGrouped_ErrorRepo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	groupBy := []entities.CostGroupType{entities.CostGroupTypeServiceName}
+	mockSubRepo.EXPECT().
+		GetCostGrouped(ctx, filterCost, groupBy).
+		Return(nil, errors.New("error repo"))
+
+	groups, err := us.GetCostGrouped(ctx, filterCost, groupBy)
+
+	require.Error(t, err)
+	require.Nil(t, groups)
+	assert.Contains(t, err.Error(), "SubscriptionUsecase.GetCostGrouped: repo exec")
+}
+
+func TestSubscription_GetCostGrouped_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	groupBy := []entities.CostGroupType{entities.CostGroupTypeServiceName, entities.CostGroupTypeUserID}
+	respData := []entities.CostGroup{{ServiceName: subTest.ServiceName, UserId: subTest.UserId, Subscriptions: 1, Cost: 1200}}
+	mockSubRepo.EXPECT().
+		GetCostGrouped(ctx, filterCost, groupBy).
+		Return(respData, nil)
 
-	resCost, err := us.GetCost(ctx, filterCost)
+	groups, err := us.GetCostGrouped(ctx, filterCost, groupBy)
 
 	require.NoError(t, err)
-	require.Equal(t, int64(124), resCost)
+	require.Equal(t, respData, groups)
 }
@@ -0,0 +1,38 @@
+package subscription
+
+import (
+	"context"
+	"time"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+)
+
+// SetTags - Replaces tags of the subscription by ID, missing tags are created.
+// The change is recorded in the audit log within the same transaction.
+func (uc *SubscriptionUsecase) SetTags(ctx context.Context, id int64, tags []string) (*entities.Subscription, error) {
+	var sub *entities.Subscription
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := uc.repo.GetByID(ctx, id, false)
+		if err != nil {
+			return errors.Wrap(err, "repo getById")
+		}
+
+		if err := uc.repo.SetTags(ctx, id, tags); err != nil {
+			return errors.Wrap(err, "repo set tags")
+		}
+
+		sub, err = uc.repo.GetByID(ctx, id, false)
+		if err != nil {
+			return errors.Wrap(err, "repo getById")
+		}
+
+		return uc.recordAudit(ctx, id, entities.AuditActionUpdate, before, sub)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "SubscriptionUsecase.SetTags")
+	}
+	refreshStatus(sub, time.Now().UTC())
+
+	return sub, nil
+}
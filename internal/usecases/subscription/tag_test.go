@@ -0,0 +1,107 @@
+package subscription
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscription_Create_WithTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	tagged := subTest
+	tagged.Tags = entities.TagList{"Streaming", "family"}
+	created := subTest
+
+	expectTransaction(mockTx, ctx)
+	gomock.InOrder(
+		mockSubRepo.EXPECT().Create(ctx, tagged).Return(&created, nil),
+		mockSubRepo.EXPECT().SetTags(ctx, created.ID, []string(tagged.Tags)).Return(nil),
+		mockAudit.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, entry entities.AuditEntry) error {
+				assert.Contains(t, string(entry.After), `"tags":["family","streaming"]`)
+				return nil
+			}),
+	)
+
+	sub, err := us.Create(ctx, tagged)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.TagList{"family", "streaming"}, sub.Tags)
+}
+
+func TestSubscription_SetTags_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	before := subTest
+	before.Tags = entities.TagList{"video"}
+	after := subTest
+	after.Tags = entities.TagList{"cloud"}
+	tags := []string{"Cloud"}
+
+	expectTransaction(mockTx, ctx)
+	gomock.InOrder(
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&before, nil),
+		mockSubRepo.EXPECT().SetTags(ctx, subTest.ID, tags).Return(nil),
+		mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(&after, nil),
+		mockAudit.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, entry entities.AuditEntry) error {
+				assert.Equal(t, entities.AuditActionUpdate, entry.Action)
+				assert.JSONEq(t, `{"tags":{"before":["video"],"after":["cloud"]}}`, string(entry.Diff))
+				return nil
+			}),
+	)
+
+	sub, err := us.SetTags(ctx, subTest.ID, tags)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.TagList{"cloud"}, sub.Tags)
+}
+
+func TestSubscription_SetTags_ErrorNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	expectTransaction(mockTx, ctx)
+	mockSubRepo.EXPECT().GetByID(ctx, subTest.ID, false).Return(nil, errors.ErrNotFound)
+
+	sub, err := us.SetTags(ctx, subTest.ID, []string{"cloud"})
+
+	require.Nil(t, sub)
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
@@ -0,0 +1,30 @@
+package subscription
+
+import (
+	"context"
+	"time"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+)
+
+// defaultUpcomingDays - look-ahead of upcoming trial conversions when days are not set
+const defaultUpcomingDays = 7
+
+// UpcomingConversions - Returns subscriptions whose trial ends within the next days
+func (uc *SubscriptionUsecase) UpcomingConversions(ctx context.Context, days int) ([]entities.Subscription, error) {
+	if days < 1 {
+		days = defaultUpcomingDays
+	}
+
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, days)
+
+	subs, err := uc.repo.ListTrialsEnding(ctx, entities.DateRange{From: &from, To: &to}, now)
+	if err != nil {
+		return nil, errors.Wrap(err, "SubscriptionUsecase.UpcomingConversions: repo exec")
+	}
+
+	return subs, nil
+}
@@ -0,0 +1,180 @@
+package subscription
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscription_Create_Trial(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	trial := subTest
+	trial.TrialEndDate = sql.NullTime{Time: time.Now().AddDate(0, 1, 0), Valid: true}
+	expected := trial
+	expected.Status = entities.SubscriptionStatusTrial
+
+	expectTransaction(mockTx, ctx)
+	mockSubRepo.EXPECT().
+		Create(ctx, expected).
+		Return(&expected, nil)
+	mockAudit.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+	sub, err := us.Create(ctx, trial)
+
+	require.NoError(t, err)
+	assert.Equal(t, entities.SubscriptionStatusTrial, sub.Status)
+}
+
+func TestSubscription_Create_TrialEnded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	ended := subTest
+	ended.TrialEndDate = sql.NullTime{Time: time.Now().AddDate(0, -1, 0), Valid: true}
+
+	expectTransaction(mockTx, ctx)
+	mockSubRepo.EXPECT().
+		Create(ctx, ended).
+		Return(&ended, nil)
+	mockAudit.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+
+	sub, err := us.Create(ctx, ended)
+
+	require.NoError(t, err)
+	assert.NotEqual(t, entities.SubscriptionStatusTrial, sub.Status)
+}
+
+func TestSubscription_UpcomingConversions_ErrorRepo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	mockSubRepo.EXPECT().
+		ListTrialsEnding(ctx, gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("error repo"))
+
+	subs, err := us.UpcomingConversions(ctx, 7)
+
+	require.Error(t, err)
+	require.Nil(t, subs)
+	assert.Contains(t, err.Error(), "SubscriptionUsecase.UpcomingConversions: repo exec")
+}
+
+func TestSubscription_UpcomingConversions_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSubRepo := mocks.NewMockSubscriptionRepository(ctrl)
+	mockAudit := mocks.NewMockAuditRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	mockRates := mocks.NewMockExchangeRateProvider(ctrl)
+	mockLogger := mocks.NewMockLogger(ctrl)
+	us := NewSubscriptionUsecase(mockSubRepo, mockAudit, mockTx, mockRates, mockLogger)
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		days       int
+		expectDays int
+	}{
+		{name: "default", days: 0, expectDays: defaultUpcomingDays},
+		{name: "days", days: 30, expectDays: 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var period entities.DateRange
+			mockSubRepo.EXPECT().
+				ListTrialsEnding(ctx, gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, p entities.DateRange, at time.Time) ([]entities.Subscription, error) {
+					period = p
+					require.True(t, at.After(*p.From))
+					return []entities.Subscription{subTest}, nil
+				})
+
+			subs, err := us.UpcomingConversions(ctx, tt.days)
+
+			require.NoError(t, err)
+			require.Equal(t, []entities.Subscription{subTest}, subs)
+			require.Equal(t, 0, period.From.Hour())
+			require.Equal(t, period.From.AddDate(0, 0, tt.expectDays), *period.To)
+		})
+	}
+}
+
+func TestSubscription_RefreshStatus_TrialEnded(t *testing.T) {
+	now := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		sub    entities.Subscription
+		expect entities.SubscriptionStatusType
+	}{
+		{
+			name: "trialRunning",
+			sub: entities.Subscription{
+				Status:       entities.SubscriptionStatusTrial,
+				TrialEndDate: sql.NullTime{Time: now.AddDate(0, 0, 1), Valid: true},
+			},
+			expect: entities.SubscriptionStatusTrial,
+		},
+		{
+			name: "trialEnded",
+			sub: entities.Subscription{
+				Status:       entities.SubscriptionStatusTrial,
+				TrialEndDate: sql.NullTime{Time: now, Valid: true},
+			},
+			expect: entities.SubscriptionStatusActive,
+		},
+		{
+			name: "trialEndedExpired",
+			sub: entities.Subscription{
+				Status:       entities.SubscriptionStatusTrial,
+				TrialEndDate: sql.NullTime{Time: now.AddDate(0, -2, 0), Valid: true},
+				EndDate:      sql.NullTime{Time: now.AddDate(0, -1, 0), Valid: true},
+			},
+			expect: entities.SubscriptionStatusExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := tt.sub
+			refreshStatus(&sub, now)
+
+			require.Equal(t, tt.expect, sub.Status)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package tag
+
+import (
+	"context"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/interfaces/repositories"
+)
+
+type TagUsecase struct {
+	repo   repositories.TagRepository
+	tx     repositories.Transactor
+	logger observability.Logger
+}
+
+// NewTagUsecase - Constructor TagUsecase
+func NewTagUsecase(repo repositories.TagRepository, tx repositories.Transactor, logger observability.Logger) TagUsecase {
+	return TagUsecase{repo: repo, tx: tx, logger: logger}
+}
+
+// List - Returns tags by filter with the number of their subscriptions
+func (uc *TagUsecase) List(ctx context.Context, filter entities.TagFilter) ([]entities.Tag, error) {
+	tags, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "TagUsecase.List: repo exec")
+	}
+
+	return tags, nil
+}
+
+// Rename - Renames tag by ID, the labeled subscriptions keep it under the new name.
+// ErrAlreadyExists is returned when the name is the name of another tag.
+func (uc *TagUsecase) Rename(ctx context.Context, id int64, name string) (*entities.Tag, error) {
+	var tag *entities.Tag
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := uc.repo.GetByID(ctx, id); err != nil {
+			return errors.Wrap(err, "TagUsecase.Rename: repo getById")
+		}
+
+		if err := uc.repo.Rename(ctx, id, name); err != nil {
+			return errors.Wrap(err, "TagUsecase.Rename: repo exec")
+		}
+
+		var err error
+		tag, err = uc.repo.GetByID(ctx, id)
+		if err != nil {
+			return errors.Wrap(err, "TagUsecase.Rename: repo getById")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tag, nil
+}
+
+// Delete - Deletes tag by ID, it is removed from the subscriptions
+func (uc *TagUsecase) Delete(ctx context.Context, id int64) error {
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		return errors.Wrap(err, "TagUsecase.Delete: repo exec")
+	}
+
+	return nil
+}
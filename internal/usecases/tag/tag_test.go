@@ -0,0 +1,115 @@
+package tag
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func expectTransaction(mockTx *mocks.MockTransactor, ctx context.Context) {
+	mockTx.EXPECT().
+		WithinTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+}
+
+func TestTag_List_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTagRepository(ctrl)
+	us := NewTagUsecase(mockRepo, mocks.NewMockTransactor(ctrl), mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	filter := entities.TagFilter{Name: "dev"}
+	tags := []entities.Tag{{ID: 1, Name: "dev tools", Subscriptions: 2}}
+	mockRepo.EXPECT().List(ctx, filter).Return(tags, nil)
+
+	res, err := us.List(ctx, filter)
+
+	require.NoError(t, err)
+	assert.Equal(t, tags, res)
+}
+
+func TestTag_Rename_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTagRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	us := NewTagUsecase(mockRepo, mockTx, mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	renamed := &entities.Tag{ID: 1, Name: "video", Subscriptions: 4}
+
+	expectTransaction(mockTx, ctx)
+	gomock.InOrder(
+		mockRepo.EXPECT().GetByID(ctx, int64(1)).Return(&entities.Tag{ID: 1, Name: "streaming"}, nil),
+		mockRepo.EXPECT().Rename(ctx, int64(1), "Video").Return(nil),
+		mockRepo.EXPECT().GetByID(ctx, int64(1)).Return(renamed, nil),
+	)
+
+	res, err := us.Rename(ctx, 1, "Video")
+
+	require.NoError(t, err)
+	assert.Equal(t, renamed, res)
+}
+
+func TestTag_Rename_ErrorNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTagRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	us := NewTagUsecase(mockRepo, mockTx, mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	expectTransaction(mockTx, ctx)
+	mockRepo.EXPECT().GetByID(ctx, int64(1)).Return(nil, errors.ErrNotFound)
+
+	res, err := us.Rename(ctx, 1, "video")
+
+	require.Nil(t, res)
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
+
+func TestTag_Rename_ErrorAlreadyExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTagRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	us := NewTagUsecase(mockRepo, mockTx, mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	expectTransaction(mockTx, ctx)
+	mockRepo.EXPECT().GetByID(ctx, int64(1)).Return(&entities.Tag{ID: 1, Name: "streaming"}, nil)
+	mockRepo.EXPECT().Rename(ctx, int64(1), "video").Return(errors.ErrAlreadyExists)
+
+	_, err := us.Rename(ctx, 1, "video")
+
+	assert.ErrorIs(t, err, errors.ErrAlreadyExists)
+}
+
+func TestTag_Delete_ErrorNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTagRepository(ctrl)
+	us := NewTagUsecase(mockRepo, mocks.NewMockTransactor(ctrl), mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	mockRepo.EXPECT().Delete(ctx, int64(1)).Return(errors.ErrNotFound)
+
+	err := us.Delete(ctx, 1)
+
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
@@ -0,0 +1,90 @@
+package user
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/internal/interfaces/observability"
+	"github.com/mathbdw/subscription-service/internal/interfaces/repositories"
+)
+
+type UserUsecase struct {
+	repo   repositories.UserRepository
+	tx     repositories.Transactor
+	logger observability.Logger
+}
+
+// NewUserUsecase - Constructor UserUsecase
+func NewUserUsecase(repo repositories.UserRepository, tx repositories.Transactor, logger observability.Logger) UserUsecase {
+	return UserUsecase{repo: repo, tx: tx, logger: logger}
+}
+
+// Create - Creates user, ErrAlreadyExists is returned when the ID or the email is taken
+func (uc *UserUsecase) Create(ctx context.Context, user entities.User) (*entities.User, error) {
+	created, err := uc.repo.Create(ctx, user)
+	if err != nil {
+		return nil, errors.Wrap(err, "UserUsecase.Create: repo exec")
+	}
+
+	return created, nil
+}
+
+// GetByID - Returns user by ID
+func (uc *UserUsecase) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
+	user, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "UserUsecase.GetByID: repo exec")
+	}
+
+	return user, nil
+}
+
+// List - Returns users by filter
+func (uc *UserUsecase) List(ctx context.Context, filter entities.UserFilter) ([]entities.User, error) {
+	users, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "UserUsecase.List: repo exec")
+	}
+
+	return users, nil
+}
+
+// Update - Updates the fields of user by ID and returns the updated user.
+// ErrAlreadyExists is returned when the email is the email of another user.
+func (uc *UserUsecase) Update(ctx context.Context, id uuid.UUID, fields map[string]any) (*entities.User, error) {
+	var user *entities.User
+	err := uc.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := uc.repo.GetByID(ctx, id); err != nil {
+			return errors.Wrap(err, "UserUsecase.Update: repo getById")
+		}
+
+		if err := uc.repo.Update(ctx, id, fields); err != nil {
+			return errors.Wrap(err, "UserUsecase.Update: repo exec")
+		}
+
+		var err error
+		user, err = uc.repo.GetByID(ctx, id)
+		if err != nil {
+			return errors.Wrap(err, "UserUsecase.Update: repo getById")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// Delete - Deletes user by ID, ErrConflict is returned while the user has subscriptions
+func (uc *UserUsecase) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		return errors.Wrap(err, "UserUsecase.Delete: repo exec")
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package user
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"github.com/mathbdw/subscription-service/internal/domain/entities"
+	"github.com/mathbdw/subscription-service/internal/errors"
+	"github.com/mathbdw/subscription-service/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func expectTransaction(mockTx *mocks.MockTransactor, ctx context.Context) {
+	mockTx.EXPECT().
+		WithinTransaction(ctx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		})
+}
+
+func TestUser_Create_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	us := NewUserUsecase(mockRepo, mocks.NewMockTransactor(ctrl), mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	user := entities.User{Name: "Ann", Email: "ann@example.com"}
+	created := &entities.User{ID: uuid.New(), Name: "Ann", Email: "ann@example.com"}
+	mockRepo.EXPECT().Create(ctx, user).Return(created, nil)
+
+	res, err := us.Create(ctx, user)
+
+	require.NoError(t, err)
+	assert.Equal(t, created, res)
+}
+
+func TestUser_Create_AlreadyExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	us := NewUserUsecase(mockRepo, mocks.NewMockTransactor(ctrl), mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil, errors.ErrAlreadyExists)
+
+	res, err := us.Create(ctx, entities.User{Name: "Ann"})
+
+	require.Nil(t, res)
+	assert.ErrorIs(t, err, errors.ErrAlreadyExists)
+}
+
+func TestUser_List_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	us := NewUserUsecase(mockRepo, mocks.NewMockTransactor(ctrl), mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	filter := entities.UserFilter{Search: "ann"}
+	users := []entities.User{{ID: uuid.New(), Name: "Ann"}}
+	mockRepo.EXPECT().List(ctx, filter).Return(users, nil)
+
+	res, err := us.List(ctx, filter)
+
+	require.NoError(t, err)
+	assert.Equal(t, users, res)
+}
+
+func TestUser_Update_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	us := NewUserUsecase(mockRepo, mockTx, mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	id := uuid.New()
+	fields := map[string]any{"name": "Anna"}
+	updated := &entities.User{ID: id, Name: "Anna"}
+
+	expectTransaction(mockTx, ctx)
+	gomock.InOrder(
+		mockRepo.EXPECT().GetByID(ctx, id).Return(&entities.User{ID: id, Name: "Ann"}, nil),
+		mockRepo.EXPECT().Update(ctx, id, fields).Return(nil),
+		mockRepo.EXPECT().GetByID(ctx, id).Return(updated, nil),
+	)
+
+	res, err := us.Update(ctx, id, fields)
+
+	require.NoError(t, err)
+	assert.Equal(t, updated, res)
+}
+
+func TestUser_Update_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	mockTx := mocks.NewMockTransactor(ctrl)
+	us := NewUserUsecase(mockRepo, mockTx, mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	id := uuid.New()
+
+	expectTransaction(mockTx, ctx)
+	mockRepo.EXPECT().GetByID(ctx, id).Return(nil, errors.ErrNotFound)
+
+	res, err := us.Update(ctx, id, map[string]any{"name": "Anna"})
+
+	require.Nil(t, res)
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
+
+func TestUser_Delete_Conflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepository(ctrl)
+	us := NewUserUsecase(mockRepo, mocks.NewMockTransactor(ctrl), mocks.NewMockLogger(ctrl))
+	ctx := context.Background()
+
+	id := uuid.New()
+	mockRepo.EXPECT().Delete(ctx, id).Return(errors.ErrConflict)
+
+	err := us.Delete(ctx, id)
+
+	assert.ErrorIs(t, err, errors.ErrConflict)
+}
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+    'up SQL query';
+
+ALTER TABLE subscription
+    ADD COLUMN billing_period VARCHAR(16) NOT NULL DEFAULT 'monthly',
+    ADD COLUMN billing_interval SMALLINT NOT NULL DEFAULT 1;
+
+ALTER TABLE subscription
+    ADD CONSTRAINT chk_subscription_billing_period CHECK (billing_period IN ('weekly', 'monthly', 'quarterly', 'yearly', 'custom')),
+    ADD CONSTRAINT chk_subscription_billing_interval CHECK (billing_interval >= 1);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+    'down SQL query';
+
+ALTER TABLE subscription
+    DROP CONSTRAINT chk_subscription_billing_interval,
+    DROP CONSTRAINT chk_subscription_billing_period,
+    DROP COLUMN billing_interval,
+    DROP COLUMN billing_period;
+
+-- +goose StatementEnd
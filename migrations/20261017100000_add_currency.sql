@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+    'up SQL query';
+
+ALTER TABLE subscription
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'RUB';
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+    'down SQL query';
+
+ALTER TABLE subscription
+    DROP COLUMN currency;
+
+-- +goose StatementEnd
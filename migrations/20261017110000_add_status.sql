@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+    'up SQL query';
+
+ALTER TABLE subscription
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active',
+    ADD COLUMN status_changed_at TIMESTAMP NOT NULL DEFAULT NOW ();
+
+ALTER TABLE subscription
+    ADD CONSTRAINT chk_subscription_status CHECK (status IN ('active', 'paused', 'cancelled', 'expired', 'trial'));
+
+UPDATE subscription
+SET
+    status_changed_at = created_at;
+
+CREATE TABLE
+    IF NOT EXISTS subscription_status_history (
+        id BIGSERIAL PRIMARY KEY,
+        subscription_id BIGINT NOT NULL REFERENCES subscription (id) ON DELETE CASCADE,
+        status VARCHAR(16) NOT NULL,
+        started_at TIMESTAMP NOT NULL DEFAULT NOW (),
+        ended_at TIMESTAMP NULL
+    );
+
+CREATE INDEX idx_subscription_status_history_subscription ON subscription_status_history (subscription_id, status) INCLUDE (started_at, ended_at);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+    'down SQL query';
+
+DROP TABLE subscription_status_history;
+
+ALTER TABLE subscription
+    DROP CONSTRAINT chk_subscription_status,
+    DROP COLUMN status_changed_at,
+    DROP COLUMN status;
+
+-- +goose StatementEnd
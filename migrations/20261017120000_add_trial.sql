@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+    'up SQL query';
+
+ALTER TABLE subscription
+    ADD COLUMN trial_end_date DATE NULL;
+
+CREATE INDEX idx_subscription_trial_end_date ON subscription (trial_end_date)
+WHERE
+    status = 'trial';
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+    'down SQL query';
+
+DROP INDEX idx_subscription_trial_end_date;
+
+ALTER TABLE subscription
+    DROP COLUMN trial_end_date;
+
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+    'up SQL query';
+
+CREATE TABLE
+    IF NOT EXISTS subscription_price_history (
+        id BIGSERIAL PRIMARY KEY,
+        subscription_id BIGINT NOT NULL REFERENCES subscription (id) ON DELETE CASCADE,
+        price BIGINT NOT NULL,
+        effective_from DATE NOT NULL,
+        created_at TIMESTAMP NOT NULL DEFAULT NOW (),
+        CONSTRAINT uq_subscription_price_history_effective_from UNIQUE (subscription_id, effective_from)
+    );
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+    'down SQL query';
+
+DROP TABLE subscription_price_history;
+
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+    'up SQL query';
+
+CREATE TABLE
+    IF NOT EXISTS audit_log (
+        id BIGSERIAL PRIMARY KEY,
+        entity_type VARCHAR(64) NOT NULL,
+        entity_id BIGINT NOT NULL,
+        action VARCHAR(16) NOT NULL,
+        actor VARCHAR(255) NOT NULL,
+        data_before JSONB,
+        data_after JSONB,
+        diff JSONB,
+        created_at TIMESTAMP NOT NULL DEFAULT NOW ()
+    );
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log (entity_type, entity_id);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log (actor);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log (created_at);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+    'down SQL query';
+
+DROP TABLE audit_log;
+
+-- +goose StatementEnd
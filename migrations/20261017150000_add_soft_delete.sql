@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+    'up SQL query';
+
+ALTER TABLE subscription
+    ADD COLUMN deleted_at TIMESTAMP NULL;
+
+CREATE INDEX idx_subscription_deleted_at ON subscription (deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+    'down SQL query';
+
+DROP INDEX idx_subscription_deleted_at;
+
+ALTER TABLE subscription
+    DROP COLUMN deleted_at;
+
+-- +goose StatementEnd
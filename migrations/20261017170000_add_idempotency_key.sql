@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+    'up SQL query';
+
+CREATE TABLE idempotency_key
+(
+    key           VARCHAR(255) PRIMARY KEY,
+    request_hash  VARCHAR(64)  NOT NULL,
+    status_code   INTEGER      NOT NULL,
+    response_body BYTEA        NULL,
+    created_at    TIMESTAMP    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at    TIMESTAMP    NOT NULL
+);
+
+CREATE INDEX idx_idempotency_key_expires_at ON idempotency_key (expires_at);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+    'down SQL query';
+
+DROP TABLE idempotency_key;
+
+-- +goose StatementEnd
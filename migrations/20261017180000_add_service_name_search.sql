@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+    'up SQL query';
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE subscription
+    ADD COLUMN service_name_normalized VARCHAR(255) GENERATED ALWAYS AS (lower(regexp_replace(btrim(service_name), '\s+', ' ', 'g'))) STORED;
+
+CREATE INDEX idx_subscription_service_name_trgm ON subscription USING GIN (service_name_normalized gin_trgm_ops);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+    'down SQL query';
+
+DROP INDEX idx_subscription_service_name_trgm;
+
+ALTER TABLE subscription
+    DROP COLUMN service_name_normalized;
+
+-- +goose StatementEnd
@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+    'up SQL query';
+
+CREATE TABLE services
+(
+    id              BIGSERIAL PRIMARY KEY,
+    name            VARCHAR(255)  NOT NULL,
+    name_normalized VARCHAR(255)  GENERATED ALWAYS AS (lower(regexp_replace(btrim(name), '\s+', ' ', 'g'))) STORED,
+    category        VARCHAR(100)  NOT NULL DEFAULT '',
+    vendor_url      VARCHAR(2048) NOT NULL DEFAULT '',
+    default_price   INTEGER       NULL,
+    currency        CHAR(3)       NOT NULL DEFAULT 'RUB',
+    created_at      TIMESTAMP     NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMP     NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_services_name_normalized ON services (name_normalized);
+CREATE INDEX idx_services_category ON services (category);
+
+-- the catalog entry of the free-text names differing only in case and spaces
+-- takes the most used spelling of them
+INSERT INTO services (name)
+SELECT DISTINCT ON (service_name_normalized) regexp_replace(btrim(service_name), '\s+', ' ', 'g')
+FROM subscription
+GROUP BY service_name_normalized, service_name
+ORDER BY service_name_normalized, COUNT(*) DESC, MIN(id);
+
+ALTER TABLE subscription
+    ADD COLUMN service_id BIGINT NULL REFERENCES services (id) ON DELETE SET NULL;
+
+UPDATE subscription AS s
+SET service_id   = sv.id,
+    service_name = sv.name
+FROM services AS sv
+WHERE sv.name_normalized = s.service_name_normalized;
+
+CREATE INDEX idx_subscription_service_id ON subscription (service_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+    'down SQL query';
+
+ALTER TABLE subscription
+    DROP COLUMN service_id;
+
+DROP TABLE services;
+
+-- +goose StatementEnd
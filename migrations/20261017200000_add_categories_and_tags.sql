@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+    'up SQL query';
+
+ALTER TABLE subscription
+    ADD COLUMN category VARCHAR(100) NOT NULL DEFAULT '';
+
+-- subscriptions of the catalog services start in the category of the service
+UPDATE subscription AS s
+SET category = lower(regexp_replace(btrim(sv.category), '\s+', ' ', 'g'))
+FROM services AS sv
+WHERE sv.id = s.service_id
+  AND sv.category <> '';
+
+CREATE INDEX idx_subscription_category ON subscription (category);
+
+CREATE TABLE tags
+(
+    id         BIGSERIAL PRIMARY KEY,
+    name       VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_tags_name ON tags (name);
+
+CREATE TABLE subscription_tags
+(
+    subscription_id BIGINT NOT NULL REFERENCES subscription (id) ON DELETE CASCADE,
+    tag_id          BIGINT NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (subscription_id, tag_id)
+);
+
+CREATE INDEX idx_subscription_tags_tag_id ON subscription_tags (tag_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+    'down SQL query';
+
+DROP TABLE subscription_tags;
+
+DROP TABLE tags;
+
+ALTER TABLE subscription
+    DROP COLUMN category;
+
+-- +goose StatementEnd
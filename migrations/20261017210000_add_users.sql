@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+    'up SQL query';
+
+CREATE TABLE users
+(
+    id         UUID PRIMARY KEY      DEFAULT gen_random_uuid(),
+    name       VARCHAR(255) NOT NULL DEFAULT '',
+    email      VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_users_email ON users (lower(email)) WHERE email <> '';
+
+-- every user already referenced by subscriptions gets a row, so the foreign key holds
+INSERT INTO users (id)
+SELECT DISTINCT user_id
+FROM subscription;
+
+ALTER TABLE subscription
+    ADD CONSTRAINT fk_subscription_user_id FOREIGN KEY (user_id) REFERENCES users (id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+    'down SQL query';
+
+ALTER TABLE subscription
+    DROP CONSTRAINT fk_subscription_user_id;
+
+DROP TABLE users;
+
+-- +goose StatementEnd
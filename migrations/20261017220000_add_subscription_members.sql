@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+    'up SQL query';
+
+-- users sharing the cost of the subscription, without members the subscription is paid by its user entirely
+CREATE TABLE subscription_members
+(
+    subscription_id BIGINT         NOT NULL REFERENCES subscription (id) ON DELETE CASCADE,
+    user_id         UUID           NOT NULL REFERENCES users (id),
+    share_type      VARCHAR(10)    NOT NULL CHECK (share_type IN ('percent', 'fixed')),
+    share           NUMERIC(12, 2) NOT NULL CHECK (share > 0),
+    created_at      TIMESTAMP      NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (subscription_id, user_id)
+);
+
+CREATE INDEX idx_subscription_members_user_id ON subscription_members (user_id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+    'down SQL query';
+
+DROP TABLE subscription_members;
+
+-- +goose StatementEnd
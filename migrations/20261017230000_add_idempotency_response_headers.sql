@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT
+    'up SQL query';
+
+ALTER TABLE idempotency_key
+    ADD COLUMN response_headers JSONB NOT NULL DEFAULT '{}';
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT
+    'down SQL query';
+
+ALTER TABLE idempotency_key
+    DROP COLUMN response_headers;
+
+-- +goose StatementEnd
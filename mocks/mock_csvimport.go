@@ -0,0 +1,57 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./importer.go
+//
+// Generated by this command:
+//
+//	mockgen -destination=./../../../mocks/mock_csvimport.go -package=mocks -source=./importer.go
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entities "github.com/mathbdw/subscription-service/internal/domain/entities"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSubscriptionCreator is a mock of SubscriptionCreator interface.
+type MockSubscriptionCreator struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubscriptionCreatorMockRecorder
+	isgomock struct{}
+}
+
+// MockSubscriptionCreatorMockRecorder is the mock recorder for MockSubscriptionCreator.
+type MockSubscriptionCreatorMockRecorder struct {
+	mock *MockSubscriptionCreator
+}
+
+// NewMockSubscriptionCreator creates a new mock instance.
+func NewMockSubscriptionCreator(ctrl *gomock.Controller) *MockSubscriptionCreator {
+	mock := &MockSubscriptionCreator{ctrl: ctrl}
+	mock.recorder = &MockSubscriptionCreatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSubscriptionCreator) EXPECT() *MockSubscriptionCreatorMockRecorder {
+	return m.recorder
+}
+
+// CreateBulk mocks base method.
+func (m *MockSubscriptionCreator) CreateBulk(ctx context.Context, subs []entities.Subscription, mode entities.BulkModeType) (*entities.BulkResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBulk", ctx, subs, mode)
+	ret0, _ := ret[0].(*entities.BulkResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBulk indicates an expected call of CreateBulk.
+func (mr *MockSubscriptionCreatorMockRecorder) CreateBulk(ctx, subs, mode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBulk", reflect.TypeOf((*MockSubscriptionCreator)(nil).CreateBulk), ctx, subs, mode)
+}
@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entities "github.com/mathbdw/subscription-service/internal/domain/entities"
 	gomock "go.uber.org/mock/gomock"
@@ -41,14 +42,43 @@ func (m *MockSubscriptionRepository) EXPECT() *MockSubscriptionRepositoryMockRec
 	return m.recorder
 }
 
-// Create mocks base method.
-func (m *MockSubscriptionRepository) Create(ctx context.Context, subscription entities.Subscription) error {
+// AddPrice mocks base method.
+func (m *MockSubscriptionRepository) AddPrice(ctx context.Context, id int64, price uint32, effectiveFrom time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, subscription)
+	ret := m.ctrl.Call(m, "AddPrice", ctx, id, price, effectiveFrom)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// AddPrice indicates an expected call of AddPrice.
+func (mr *MockSubscriptionRepositoryMockRecorder) AddPrice(ctx, id, price, effectiveFrom any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPrice", reflect.TypeOf((*MockSubscriptionRepository)(nil).AddPrice), ctx, id, price, effectiveFrom)
+}
+
+// ChangeStatus mocks base method.
+func (m *MockSubscriptionRepository) ChangeStatus(ctx context.Context, id int64, status entities.SubscriptionStatusType, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeStatus", ctx, id, status, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangeStatus indicates an expected call of ChangeStatus.
+func (mr *MockSubscriptionRepositoryMockRecorder) ChangeStatus(ctx, id, status, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeStatus", reflect.TypeOf((*MockSubscriptionRepository)(nil).ChangeStatus), ctx, id, status, at)
+}
+
+// Create mocks base method.
+func (m *MockSubscriptionRepository) Create(ctx context.Context, subscription entities.Subscription) (*entities.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, subscription)
+	ret0, _ := ret[0].(*entities.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // Create indicates an expected call of Create.
 func (mr *MockSubscriptionRepositoryMockRecorder) Create(ctx, subscription any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
@@ -56,39 +86,67 @@ func (mr *MockSubscriptionRepositoryMockRecorder) Create(ctx, subscription any)
 }
 
 // Delete mocks base method.
-func (m *MockSubscriptionRepository) Delete(ctx context.Context, id int64) error {
+func (m *MockSubscriptionRepository) Delete(ctx context.Context, id, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockSubscriptionRepositoryMockRecorder) Delete(ctx, id any) *gomock.Call {
+func (mr *MockSubscriptionRepositoryMockRecorder) Delete(ctx, id, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSubscriptionRepository)(nil).Delete), ctx, id, version)
+}
+
+// DeletePricesUntil mocks base method.
+func (m *MockSubscriptionRepository) DeletePricesUntil(ctx context.Context, id int64, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePricesUntil", ctx, id, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePricesUntil indicates an expected call of DeletePricesUntil.
+func (mr *MockSubscriptionRepositoryMockRecorder) DeletePricesUntil(ctx, id, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePricesUntil", reflect.TypeOf((*MockSubscriptionRepository)(nil).DeletePricesUntil), ctx, id, until)
+}
+
+// Export mocks base method.
+func (m *MockSubscriptionRepository) Export(ctx context.Context, params entities.QueryCriteria, fn func(entities.Subscription) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, params, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockSubscriptionRepositoryMockRecorder) Export(ctx, params, fn any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSubscriptionRepository)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockSubscriptionRepository)(nil).Export), ctx, params, fn)
 }
 
 // GetByID mocks base method.
-func (m *MockSubscriptionRepository) GetByID(ctx context.Context, id int64) (*entities.Subscription, error) {
+func (m *MockSubscriptionRepository) GetByID(ctx context.Context, id int64, includeDeleted bool) (*entities.Subscription, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id, includeDeleted)
 	ret0, _ := ret[0].(*entities.Subscription)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockSubscriptionRepositoryMockRecorder) GetByID(ctx, id any) *gomock.Call {
+func (mr *MockSubscriptionRepositoryMockRecorder) GetByID(ctx, id, includeDeleted any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockSubscriptionRepository)(nil).GetByID), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockSubscriptionRepository)(nil).GetByID), ctx, id, includeDeleted)
 }
 
 // GetCost mocks base method.
-func (m *MockSubscriptionRepository) GetCost(ctx context.Context, params entities.FilterParams) (int64, error) {
+func (m *MockSubscriptionRepository) GetCost(ctx context.Context, params entities.FilterParams) ([]entities.CostItem, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetCost", ctx, params)
-	ret0, _ := ret[0].(int64)
+	ret0, _ := ret[0].([]entities.CostItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -99,6 +157,81 @@ func (mr *MockSubscriptionRepositoryMockRecorder) GetCost(ctx, params any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCost", reflect.TypeOf((*MockSubscriptionRepository)(nil).GetCost), ctx, params)
 }
 
+// GetCostGrouped mocks base method.
+func (m *MockSubscriptionRepository) GetCostGrouped(ctx context.Context, params entities.FilterParams, groupBy []entities.CostGroupType) ([]entities.CostGroup, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCostGrouped", ctx, params, groupBy)
+	ret0, _ := ret[0].([]entities.CostGroup)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCostGrouped indicates an expected call of GetCostGrouped.
+func (mr *MockSubscriptionRepositoryMockRecorder) GetCostGrouped(ctx, params, groupBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCostGrouped", reflect.TypeOf((*MockSubscriptionRepository)(nil).GetCostGrouped), ctx, params, groupBy)
+}
+
+// GetCostMonthly mocks base method.
+func (m *MockSubscriptionRepository) GetCostMonthly(ctx context.Context, params entities.FilterParams) ([]entities.CostMonthService, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCostMonthly", ctx, params)
+	ret0, _ := ret[0].([]entities.CostMonthService)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCostMonthly indicates an expected call of GetCostMonthly.
+func (mr *MockSubscriptionRepositoryMockRecorder) GetCostMonthly(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCostMonthly", reflect.TypeOf((*MockSubscriptionRepository)(nil).GetCostMonthly), ctx, params)
+}
+
+// GetMembers mocks base method.
+func (m *MockSubscriptionRepository) GetMembers(ctx context.Context, id int64) ([]entities.Member, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembers", ctx, id)
+	ret0, _ := ret[0].([]entities.Member)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembers indicates an expected call of GetMembers.
+func (mr *MockSubscriptionRepositoryMockRecorder) GetMembers(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembers", reflect.TypeOf((*MockSubscriptionRepository)(nil).GetMembers), ctx, id)
+}
+
+// GetPriceHistory mocks base method.
+func (m *MockSubscriptionRepository) GetPriceHistory(ctx context.Context, id int64) ([]entities.PriceChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPriceHistory", ctx, id)
+	ret0, _ := ret[0].([]entities.PriceChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPriceHistory indicates an expected call of GetPriceHistory.
+func (mr *MockSubscriptionRepositoryMockRecorder) GetPriceHistory(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceHistory", reflect.TypeOf((*MockSubscriptionRepository)(nil).GetPriceHistory), ctx, id)
+}
+
+// GetStatusHistory mocks base method.
+func (m *MockSubscriptionRepository) GetStatusHistory(ctx context.Context, id int64) ([]entities.SubscriptionStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatusHistory", ctx, id)
+	ret0, _ := ret[0].([]entities.SubscriptionStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatusHistory indicates an expected call of GetStatusHistory.
+func (mr *MockSubscriptionRepositoryMockRecorder) GetStatusHistory(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatusHistory", reflect.TypeOf((*MockSubscriptionRepository)(nil).GetStatusHistory), ctx, id)
+}
+
 // List mocks base method.
 func (m *MockSubscriptionRepository) List(ctx context.Context, params entities.QueryCriteria) (*entities.ResponseListSubscription, error) {
 	m.ctrl.T.Helper()
@@ -114,16 +247,88 @@ func (mr *MockSubscriptionRepositoryMockRecorder) List(ctx, params any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSubscriptionRepository)(nil).List), ctx, params)
 }
 
+// ListTrialsEnding mocks base method.
+func (m *MockSubscriptionRepository) ListTrialsEnding(ctx context.Context, period entities.DateRange, at time.Time) ([]entities.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrialsEnding", ctx, period, at)
+	ret0, _ := ret[0].([]entities.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrialsEnding indicates an expected call of ListTrialsEnding.
+func (mr *MockSubscriptionRepositoryMockRecorder) ListTrialsEnding(ctx, period, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrialsEnding", reflect.TypeOf((*MockSubscriptionRepository)(nil).ListTrialsEnding), ctx, period, at)
+}
+
+// Purge mocks base method.
+func (m *MockSubscriptionRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockSubscriptionRepositoryMockRecorder) Purge(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockSubscriptionRepository)(nil).Purge), ctx, before)
+}
+
+// Restore mocks base method.
+func (m *MockSubscriptionRepository) Restore(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockSubscriptionRepositoryMockRecorder) Restore(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockSubscriptionRepository)(nil).Restore), ctx, id)
+}
+
+// SetMembers mocks base method.
+func (m *MockSubscriptionRepository) SetMembers(ctx context.Context, id int64, members []entities.Member) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMembers", ctx, id, members)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMembers indicates an expected call of SetMembers.
+func (mr *MockSubscriptionRepositoryMockRecorder) SetMembers(ctx, id, members any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMembers", reflect.TypeOf((*MockSubscriptionRepository)(nil).SetMembers), ctx, id, members)
+}
+
+// SetTags mocks base method.
+func (m *MockSubscriptionRepository) SetTags(ctx context.Context, id int64, tags []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTags", ctx, id, tags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTags indicates an expected call of SetTags.
+func (mr *MockSubscriptionRepositoryMockRecorder) SetTags(ctx, id, tags any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTags", reflect.TypeOf((*MockSubscriptionRepository)(nil).SetTags), ctx, id, tags)
+}
+
 // Update mocks base method.
-func (m *MockSubscriptionRepository) Update(ctx context.Context, id int64, fields map[string]any) error {
+func (m *MockSubscriptionRepository) Update(ctx context.Context, id int64, fields map[string]any, version int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Update", ctx, id, fields)
+	ret := m.ctrl.Call(m, "Update", ctx, id, fields, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Update indicates an expected call of Update.
-func (mr *MockSubscriptionRepositoryMockRecorder) Update(ctx, id, fields any) *gomock.Call {
+func (mr *MockSubscriptionRepositoryMockRecorder) Update(ctx, id, fields, version any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSubscriptionRepository)(nil).Update), ctx, id, fields)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSubscriptionRepository)(nil).Update), ctx, id, fields, version)
 }